@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"stockpricews/entity"
+)
+
+// MaxProfitWithCosts calculates the optimal schedule with unlimited number of transactions in a given historical
+// time slice, taking into account the fee charged for every trade and the cooldown required between a sell and the next buy
+func (c MaxProfitController) MaxProfitWithCosts(ctx context.Context, req entity.StockQuoteRequest, costs entity.TradeCosts) (entity.MultiTradeProfit, error) {
+	if costs.Fee < 0 || costs.Cooldown < 0 {
+		return entity.MultiTradeProfit{}, fmt.Errorf("fee and cooldown can't be negative: %w", entity.ErrBadRequest)
+	}
+
+	history, err := c.Repository.StockQuotesPerTimeSlice(ctx, req)
+	if err != nil {
+		return entity.MultiTradeProfit{}, err
+	}
+
+	if history, err = priceSeries(history, req.Field); err != nil {
+		return entity.MultiTradeProfit{}, err
+	}
+
+	return maxProfitWithCosts(history, costs)
+}
+
+func maxProfitWithCosts(history []entity.StockQuote, costs entity.TradeCosts) (entity.MultiTradeProfit, error) {
+	if len(history) == 0 {
+		return entity.MultiTradeProfit{}, fmt.Errorf("no records found for the given period: %w", entity.ErrNotFound)
+	}
+
+	n := len(history)
+	// cash[i] holds the max net profit without an open position after the i-th quote. sellFrom[i] holds the buy index
+	// of the trade closed at i or -1 if the value is carried over from the previous quote
+	cash := make([]float64, n)
+	sellFrom := make([]int, n)
+	// hold[i] holds the max net profit with an open position after the i-th quote and holdFrom[i] the buy index of it
+	hold := make([]float64, n)
+	holdFrom := make([]int, n)
+	// buyAfter[b] holds the cash index the buy at b was funded from or -1 if it's the first trade
+	buyAfter := make([]int, n)
+
+	sellFrom[0] = -1
+	hold[0] = -buyCost(history[0].Price, costs)
+	holdFrom[0] = 0
+	buyAfter[0] = -1
+
+	// funded is the last cash index whose sells have finished their cooldown at the current quote
+	funded := -1
+	for i := 1; i < n; i++ {
+		cash[i] = cash[i-1]
+		sellFrom[i] = -1
+		if net := hold[i-1] + sellProceeds(history[i].Price, costs); net > cash[i] {
+			cash[i] = net
+			sellFrom[i] = holdFrom[i-1]
+		}
+
+		for funded+1 < i && !history[funded+1].Datepoint.Add(costs.Cooldown).After(history[i].Datepoint) {
+			funded++
+		}
+
+		hold[i] = hold[i-1]
+		holdFrom[i] = holdFrom[i-1]
+		budget := float64(0)
+		if funded >= 0 {
+			budget = cash[funded]
+		}
+		if candidate := budget - buyCost(history[i].Price, costs); candidate > hold[i] {
+			hold[i] = candidate
+			holdFrom[i] = i
+			buyAfter[i] = funded
+		}
+	}
+
+	// walk back from the last quote and collect the trades in reverse order
+	var trades []entity.Trade
+	for i := n - 1; i >= 0; {
+		if sellFrom[i] == -1 {
+			i--
+			continue
+		}
+		buy, sell := history[sellFrom[i]], history[i]
+		trades = append(trades, entity.Trade{
+			BuyPoint:  entity.TradePoint{Price: buy.Price, Date: buy.Datepoint},
+			SellPoint: entity.TradePoint{Price: sell.Price, Date: sell.Datepoint},
+			Profit:    sell.Price - buy.Price,
+			Fee:       buyCost(buy.Price, costs) - buy.Price + sell.Price - sellProceeds(sell.Price, costs),
+			NetProfit: sellProceeds(sell.Price, costs) - buyCost(buy.Price, costs),
+		})
+		i = buyAfter[sellFrom[i]]
+	}
+
+	if len(trades) == 0 {
+		return entity.MultiTradeProfit{}, fmt.Errorf("it's not possible to realize a profit in the given period: %w", entity.ErrNotFound)
+	}
+
+	result := entity.MultiTradeProfit{Trades: make([]entity.Trade, 0, len(trades))}
+	for i := len(trades) - 1; i >= 0; i-- {
+		result.Trades = append(result.Trades, trades[i])
+		result.TotalProfit += trades[i].Profit
+		result.TotalNetProfit += trades[i].NetProfit
+	}
+
+	return result, nil
+}
+
+// buyCost returns the amount paid to open a position at the given price. Absolute fees are charged once per
+// round trip on the sell side, while percentage fees are charged on the traded value of both sides
+func buyCost(price float64, costs entity.TradeCosts) float64 {
+	if costs.FeePercent {
+		return price * (1 + costs.Fee/100)
+	}
+	return price
+}
+
+// sellProceeds returns the amount received by closing a position at the given price
+func sellProceeds(price float64, costs entity.TradeCosts) float64 {
+	if costs.FeePercent {
+		return price * (1 - costs.Fee/100)
+	}
+	return price - costs.Fee
+}
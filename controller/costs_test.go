@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"stockpricews/entity"
+	"testing"
+	"time"
+)
+
+func TestMaxProfitWithCosts(t *testing.T) {
+	times, history := quoteFixture(time.Now(), time.Hour, 10)
+
+	trade := func(buyIdx, sellIdx int, buy, sell, fee float64) entity.Trade {
+		return entity.Trade{
+			BuyPoint:  entity.TradePoint{Price: buy, Date: times[buyIdx]},
+			SellPoint: entity.TradePoint{Price: sell, Date: times[sellIdx]},
+			Profit:    sell - buy,
+			Fee:       fee,
+			NetProfit: sell - buy - fee,
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		history     []entity.StockQuote
+		costs       entity.TradeCosts
+		expected    entity.MultiTradeProfit
+		expectedErr error
+	}{
+		{
+			name:        "No history records found - error not found",
+			history:     []entity.StockQuote{},
+			expectedErr: entity.ErrNotFound,
+		},
+		{
+			name:        "Fee eats the whole margin - error not found",
+			history:     history(1, 2, 1, 2),
+			costs:       entity.TradeCosts{Fee: 1},
+			expectedErr: entity.ErrNotFound,
+		},
+		{
+			name:    "No costs - take every rise",
+			history: history(1, 3, 2, 8, 4, 9),
+			expected: entity.MultiTradeProfit{
+				Trades:         []entity.Trade{trade(0, 1, 1, 3, 0), trade(2, 3, 2, 8, 0), trade(4, 5, 4, 9, 0)},
+				TotalProfit:    13,
+				TotalNetProfit: 13,
+			},
+		},
+		{
+			name:    "Absolute fee - merge the trades with small dips",
+			history: history(1, 3, 2, 8, 4, 9),
+			costs:   entity.TradeCosts{Fee: 2},
+			expected: entity.MultiTradeProfit{
+				Trades:         []entity.Trade{trade(0, 3, 1, 8, 2), trade(4, 5, 4, 9, 2)},
+				TotalProfit:    12,
+				TotalNetProfit: 8,
+			},
+		},
+		{
+			name:    "Percentage fee - charged on both sides",
+			history: history(10, 20, 19, 30),
+			costs:   entity.TradeCosts{Fee: 10, FeePercent: true},
+			expected: entity.MultiTradeProfit{
+				Trades:         []entity.Trade{trade(0, 3, 10, 30, 4)},
+				TotalProfit:    20,
+				TotalNetProfit: 16,
+			},
+		},
+		{
+			name:    "Cooldown - skip the buy right after the sell",
+			history: history(1, 4, 2, 5, 8),
+			costs:   entity.TradeCosts{Cooldown: time.Hour * 2},
+			expected: entity.MultiTradeProfit{
+				Trades:         []entity.Trade{trade(0, 4, 1, 8, 0)},
+				TotalProfit:    7,
+				TotalNetProfit: 7,
+			},
+		},
+		{
+			name:    "Cooldown - buy again once it's over",
+			history: history(1, 6, 5, 2, 9),
+			costs:   entity.TradeCosts{Cooldown: time.Hour * 2},
+			expected: entity.MultiTradeProfit{
+				Trades:         []entity.Trade{trade(0, 1, 1, 6, 0), trade(3, 4, 2, 9, 0)},
+				TotalProfit:    12,
+				TotalNetProfit: 12,
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := maxProfitWithCosts(tt.history, tt.costs)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+			} else {
+				assert.NoError(t, err)
+				assert.InDeltaSlice(t, totals(tt.expected), totals(got), 1e-9)
+				assert.Equal(t, len(tt.expected.Trades), len(got.Trades))
+				for i := range got.Trades {
+					assert.Equal(t, tt.expected.Trades[i].BuyPoint, got.Trades[i].BuyPoint)
+					assert.Equal(t, tt.expected.Trades[i].SellPoint, got.Trades[i].SellPoint)
+					assert.InDelta(t, tt.expected.Trades[i].Fee, got.Trades[i].Fee, 1e-9)
+					assert.InDelta(t, tt.expected.Trades[i].NetProfit, got.Trades[i].NetProfit, 1e-9)
+				}
+			}
+		})
+	}
+}
+
+func totals(p entity.MultiTradeProfit) []float64 {
+	return []float64{p.TotalProfit, p.TotalNetProfit}
+}
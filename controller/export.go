@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"stockpricews/entity"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultExportIdleTimeout is the time an export waits for a quote to be taken if the controller has no idle timeout
+const DefaultExportIdleTimeout = 30 * time.Second
+
+// idleTimer is the part of time.Timer bounding the time a quote of the export is taken in
+type idleTimer interface {
+	Reset(d time.Duration) bool
+	Stop() bool
+}
+
+// newIdleTimer calls f in its own goroutine once d elapsed, it's replaced by tests
+var newIdleTimer = func(d time.Duration, f func()) idleTimer {
+	return time.AfterFunc(d, f)
+}
+
+// ExportStockQuotes streams the stored quotes of the time slice ordered by date point to fn, straight from the
+// repository without loading them all. The quotes are exported as they're stored, adjusted prices can't be streamed as
+// the adjustment of a quote depends on the corporate actions after it. The export lasts as long as fn takes the quotes,
+// if it takes longer than the idle timeout for any of them the query is canceled, so a client that stops reading
+// doesn't hold the database connection, and context.DeadlineExceeded is reported.
+func (c MaxProfitController) ExportStockQuotes(ctx context.Context, timeSlice entity.StockQuoteRequest, fn func(entity.StockQuote) error) error {
+	if timeSlice.Adjusted {
+		return fmt.Errorf("adjusted quotes can't be exported: %w", entity.ErrBadRequest)
+	}
+
+	idleTimeout := c.ExportIdleTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultExportIdleTimeout
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var stalled int32
+	idle := newIdleTimer(idleTimeout, func() {
+		atomic.StoreInt32(&stalled, 1)
+		cancel()
+	})
+	// the timer only runs while fn takes a quote
+	idle.Stop()
+
+	err := c.Repository.EachStockQuote(ctx, timeSlice, func(quote entity.StockQuote) error {
+		idle.Reset(idleTimeout)
+		defer idle.Stop()
+		return fn(quote)
+	})
+	if err != nil {
+		if atomic.LoadInt32(&stalled) == 1 {
+			return fmt.Errorf("failed to export stock quotes, a quote wasn't taken within %s: %w", idleTimeout, context.DeadlineExceeded)
+		}
+		return fmt.Errorf("failed to export stock quotes: %w", err)
+	}
+	return nil
+}
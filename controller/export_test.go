@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"stockpricews/entity"
+	"stockpricews/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTimer fires only when the test tells it to
+type fakeTimer struct {
+	timeout time.Duration
+	fire    func()
+	running bool
+	resets  int
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.timeout = d
+	t.resets++
+	wasRunning := t.running
+	t.running = true
+	return wasRunning
+}
+
+func (t *fakeTimer) Stop() bool {
+	wasRunning := t.running
+	t.running = false
+	return wasRunning
+}
+
+func TestExportStockQuotes(t *testing.T) {
+	defer func(original func(time.Duration, func()) idleTimer) { newIdleTimer = original }(newIdleTimer)
+	var timer *fakeTimer
+	newIdleTimer = func(d time.Duration, f func()) idleTimer {
+		timer = &fakeTimer{timeout: d, fire: f, running: true}
+		return timer
+	}
+
+	initialTime := time.Unix(1699228800, 0)
+	repo := repository.NewMemory(
+		entity.StockQuote{Symbol: "UBER", Datepoint: initialTime, Price: 1},
+		entity.StockQuote{Symbol: "UBER", Datepoint: initialTime.Add(time.Hour), Price: 2},
+		entity.StockQuote{Symbol: "UBER", Datepoint: initialTime.Add(time.Hour * 2), Price: 3},
+	)
+	c := MaxProfitController{Repository: repo, ExportIdleTimeout: time.Minute}
+	req := entity.StockQuoteRequest{Symbol: "UBER", Begin: initialTime.Add(-time.Hour), End: initialTime.Add(time.Hour * 24)}
+
+	// the timer runs only while a quote is taken
+	var prices []float64
+	assert.NoError(t, c.ExportStockQuotes(context.Background(), req, func(quote entity.StockQuote) error {
+		assert.True(t, timer.running)
+		prices = append(prices, quote.Price)
+		return nil
+	}))
+	assert.Equal(t, []float64{1, 2, 3}, prices)
+	assert.Equal(t, 3, timer.resets)
+	assert.Equal(t, time.Minute, timer.timeout)
+	assert.False(t, timer.running)
+
+	// a quote not taken in time cancels the export
+	prices = nil
+	err := c.ExportStockQuotes(context.Background(), req, func(quote entity.StockQuote) error {
+		prices = append(prices, quote.Price)
+		timer.fire()
+		return nil
+	})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, []float64{1}, prices)
+
+	// the default idle timeout applies if the controller has none
+	assert.NoError(t, MaxProfitController{Repository: repo}.ExportStockQuotes(context.Background(), req, func(entity.StockQuote) error { return nil }))
+	assert.Equal(t, DefaultExportIdleTimeout, timer.timeout)
+
+	req.Adjusted = true
+	err = c.ExportStockQuotes(context.Background(), req, func(entity.StockQuote) error { return nil })
+	assert.True(t, errors.Is(err, entity.ErrBadRequest))
+}
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"stockpricews/entity"
+	"stockpricews/repository"
+	"sync"
+	"time"
+)
+
+// subscriptionBuffer is the number of quotes a subscriber can fall behind before it gets dropped
+const subscriptionBuffer = 64
+
+// QuoteFeed fans out new quotes to the subscribers of their symbol as they're ingested, whatever their date point.
+// Publishing never blocks - subscribers that can't keep up are dropped by closing their channel rather than stalling
+// the publisher.
+type QuoteFeed struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan entity.StockQuote]struct{}
+	// latest published date point per symbol, the repository is polled for the quotes after it
+	last map[string]time.Time
+}
+
+// NewQuoteFeed initializes a QuoteFeed without subscribers
+func NewQuoteFeed() *QuoteFeed {
+	return &QuoteFeed{
+		subscribers: make(map[string]map[chan entity.StockQuote]struct{}),
+		last:        make(map[string]time.Time),
+	}
+}
+
+// Subscribe registers a new subscriber for the quotes of the given symbol. The returned func must be called to
+// unsubscribe, it's safe to call it more than once.
+func (f *QuoteFeed) Subscribe(symbol string) (<-chan entity.StockQuote, func()) {
+	ch := make(chan entity.StockQuote, subscriptionBuffer)
+
+	f.mu.Lock()
+	if f.subscribers[symbol] == nil {
+		f.subscribers[symbol] = make(map[chan entity.StockQuote]struct{})
+	}
+	f.subscribers[symbol][ch] = struct{}{}
+	if _, ok := f.last[symbol]; !ok {
+		// only quotes stored from now on are polled
+		f.last[symbol] = time.Now()
+	}
+	f.mu.Unlock()
+
+	return ch, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.drop(symbol, ch)
+	}
+}
+
+// Publish delivers the quote to the subscribers of its symbol. Every stored quote is delivered, the ones dated before
+// the quotes published already as well, e.g. a close posted after the end of the day.
+func (f *QuoteFeed) Publish(quote entity.StockQuote) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.publish(quote)
+}
+
+// publishNew delivers the quote unless a quote of the same or a later date point was published already, so the
+// polled quotes stored by this process aren't delivered twice
+func (f *QuoteFeed) publishNew(quote entity.StockQuote) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if last, ok := f.last[quote.Symbol]; ok && !quote.Datepoint.After(last) {
+		return
+	}
+	f.publish(quote)
+}
+
+// publish delivers the quote to the subscribers of its symbol, the caller must hold the lock
+func (f *QuoteFeed) publish(quote entity.StockQuote) {
+	if last, ok := f.last[quote.Symbol]; !ok || quote.Datepoint.After(last) {
+		f.last[quote.Symbol] = quote.Datepoint
+	}
+
+	for ch := range f.subscribers[quote.Symbol] {
+		select {
+		case ch <- quote:
+		default:
+			// slow subscriber - drop it so the rest don't have to wait
+			f.drop(quote.Symbol, ch)
+		}
+	}
+}
+
+// PollRepository publishes the quotes stored in the repository after the latest published one for every subscribed
+// symbol until the context is done. It makes the feed work for quotes written to the database by other processes; as
+// the quotes are told apart by their date points, the ones those processes store dated before the latest published
+// one aren't picked up.
+func (f *QuoteFeed) PollRepository(ctx context.Context, repository repository.Repository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		f.mu.Lock()
+		since := make(map[string]time.Time, len(f.subscribers))
+		for symbol := range f.subscribers {
+			since[symbol] = f.last[symbol]
+		}
+		f.mu.Unlock()
+
+		for symbol, last := range since {
+			quotes, err := repository.StockQuotesPerTimeSlice(ctx, entity.StockQuoteRequest{Symbol: symbol, Begin: last, End: time.Now()})
+			if err != nil {
+				// log the error at the server log and retry on the next tick
+				fmt.Println(fmt.Errorf("failed to poll quotes of %s: %w", symbol, err))
+				continue
+			}
+			for _, quote := range quotes {
+				f.publishNew(quote)
+			}
+		}
+	}
+}
+
+// drop removes the subscriber and closes its channel, the caller must hold the lock
+func (f *QuoteFeed) drop(symbol string, ch chan entity.StockQuote) {
+	if _, ok := f.subscribers[symbol][ch]; !ok {
+		return
+	}
+
+	delete(f.subscribers[symbol], ch)
+	close(ch)
+	if len(f.subscribers[symbol]) == 0 {
+		delete(f.subscribers, symbol)
+	}
+}
@@ -0,0 +1,23 @@
+package controller
+
+import (
+	"stockpricews/entity"
+	"time"
+)
+
+// quoteFixture returns n date points spaced step apart from start, along with a func building a history that assigns
+// the given prices to the date points in order
+func quoteFixture(start time.Time, step time.Duration, n int) ([]time.Time, func(prices ...float64) []entity.StockQuote) {
+	times := make([]time.Time, n)
+	for i := range times {
+		times[i] = start.Add(step * time.Duration(i))
+	}
+
+	return times, func(prices ...float64) []entity.StockQuote {
+		quotes := make([]entity.StockQuote, len(prices))
+		for i, p := range prices {
+			quotes[i] = entity.StockQuote{Datepoint: times[i], Price: p}
+		}
+		return quotes
+	}
+}
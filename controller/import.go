@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"stockpricews/entity"
+	"stockpricews/repository"
+	"time"
+)
+
+// DefaultImportBatchSize is the number of quotes stored in a single transaction if the Importer has no batch size
+const DefaultImportBatchSize = 1000
+
+// Importer streams the quotes of a file into the repository in batches, every batch is upserted in its own
+// transaction. Invalid records are reported and skipped rather than failing the import. A quote repeated within a
+// batch replaces the earlier one, the same as the upsert does across batches, so the last record of a symbol and date
+// point always wins.
+type Importer struct {
+	Repository repository.Repository
+	BatchSize  int
+	// Resume holds the totals of a previous run checkpointed by Progress, its records are read but not stored again
+	Resume entity.ImportProgress
+	// Progress is called with the totals after every stored batch, they're a checkpoint safe to resume from
+	Progress func(entity.ImportProgress)
+	// Reject is called for every record that can't be parsed or is invalid, Row is its line in the file
+	Reject func(entity.RejectedQuote)
+}
+
+// quoteKey identifies a stored quote
+type quoteKey struct {
+	symbol    string
+	datepoint int64
+}
+
+// Import reads the quotes until the end of the file and returns the totals. On error the totals of the batches
+// stored so far are returned, so the import can be resumed from them.
+func (im Importer) Import(ctx context.Context, reader *repository.QuoteReader) (entity.ImportProgress, error) {
+	batchSize := im.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultImportBatchSize
+	}
+
+	committed := im.Resume
+	pending := committed
+	batch := make([]entity.StockQuote, 0, batchSize)
+	positions := make(map[quoteKey]int, batchSize)
+	now := time.Now()
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := im.Repository.SaveStockQuotes(ctx, batch); err != nil {
+			return fmt.Errorf("failed to store batch %d ending at line %d: %w", pending.Batches+1, reader.Line(), err)
+		}
+		pending.Stored += int64(len(batch))
+		pending.Batches++
+		committed = pending
+		batch = batch[:0]
+		positions = make(map[quoteKey]int, batchSize)
+		if im.Progress != nil {
+			im.Progress(committed)
+		}
+		return nil
+	}
+
+	for records := int64(0); ; records++ {
+		quote, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var recordErr *repository.RecordError
+		if err != nil && !errors.As(err, &recordErr) {
+			return committed, fmt.Errorf("failed to read quotes: %w", err)
+		}
+		// the records of the previous run are skipped before they're validated, they were reported back then
+		if records < im.Resume.Records {
+			continue
+		}
+		pending.Records++
+
+		if err == nil {
+			err = validateQuote(quote, now)
+		} else {
+			// the line is reported as the row already
+			err = recordErr.Err
+		}
+		if err != nil {
+			pending.Rejected++
+			if im.Reject != nil {
+				im.Reject(entity.RejectedQuote{Row: reader.Line(), Err: err})
+			}
+			continue
+		}
+
+		key := quoteKey{symbol: quote.Symbol, datepoint: quote.Datepoint.Unix()}
+		if i, ok := positions[key]; ok {
+			batch[i] = quote
+			pending.Duplicates++
+			continue
+		}
+		positions[key] = len(batch)
+		batch = append(batch, quote)
+
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return committed, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return committed, err
+	}
+	// the trailing records may all have been rejected or duplicates, they count as done nevertheless
+	committed = pending
+
+	return committed, nil
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"stockpricews/entity"
+	"stockpricews/repository"
+	"strings"
+	"testing"
+)
+
+// flakyRepository fails the save of the given batch, counting from 1
+type flakyRepository struct {
+	*repository.MemoryRepository
+	saves  int
+	failAt int
+}
+
+func (r *flakyRepository) SaveStockQuotes(ctx context.Context, quotes []entity.StockQuote) error {
+	r.saves++
+	if r.saves == r.failAt {
+		return errors.New("connection reset")
+	}
+	return r.MemoryRepository.SaveStockQuotes(ctx, quotes)
+}
+
+const importCSV = `symbol,date,close
+UBER,2023-11-01,40
+UBER,2023-11-02,41
+UBER,2023-11-02,42
+UBER,2023-11-03,-1
+UBER,not a date,43
+UBER,2023-11-06,44
+TSLA,2023-11-06,220
+`
+
+func TestImporter(t *testing.T) {
+	read := func() *repository.QuoteReader {
+		reader, err := repository.NewQuoteReader(strings.NewReader(importCSV), entity.FormatCSV)
+		assert.NoError(t, err)
+		return reader
+	}
+
+	t.Run("Stores valid quotes in batches", func(t *testing.T) {
+		repo := repository.NewMemory()
+		var checkpoints []entity.ImportProgress
+		var rejectedLines []int
+		progress, err := Importer{
+			Repository: repo,
+			BatchSize:  3,
+			Progress:   func(p entity.ImportProgress) { checkpoints = append(checkpoints, p) },
+			Reject:     func(r entity.RejectedQuote) { rejectedLines = append(rejectedLines, r.Row) },
+		}.Import(context.Background(), read())
+		assert.NoError(t, err)
+		assert.Equal(t, entity.ImportProgress{Records: 7, Stored: 4, Rejected: 2, Duplicates: 1, Batches: 2}, progress)
+		assert.Equal(t, []int{5, 6}, rejectedLines)
+		// the duplicate within the batch is merged, the batch is full once it holds three distinct quotes
+		assert.Equal(t, []entity.ImportProgress{
+			{Records: 6, Stored: 3, Rejected: 2, Duplicates: 1, Batches: 1},
+			{Records: 7, Stored: 4, Rejected: 2, Duplicates: 1, Batches: 2},
+		}, checkpoints)
+
+		quotes, err := repo.StockQuotesPerSymbol(context.Background(), "UBER")
+		assert.NoError(t, err)
+		assert.Len(t, quotes, 3)
+		assert.Equal(t, 42.0, quotes[1].Price)
+	})
+
+	t.Run("Resumes after the last stored batch", func(t *testing.T) {
+		repo := &flakyRepository{MemoryRepository: repository.NewMemory(), failAt: 2}
+		importer := Importer{Repository: repo, BatchSize: 3}
+		progress, err := importer.Import(context.Background(), read())
+		assert.Error(t, err)
+		assert.Equal(t, entity.ImportProgress{Records: 6, Stored: 3, Rejected: 2, Duplicates: 1, Batches: 1}, progress)
+
+		var rejected int
+		importer.Resume = progress
+		importer.Reject = func(entity.RejectedQuote) { rejected++ }
+		progress, err = importer.Import(context.Background(), read())
+		assert.NoError(t, err)
+		assert.Equal(t, entity.ImportProgress{Records: 7, Stored: 4, Rejected: 2, Duplicates: 1, Batches: 2}, progress)
+		// the rejected records of the first run aren't reported again
+		assert.Zero(t, rejected)
+
+		symbols, err := repo.Symbols(context.Background())
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"UBER", "TSLA"}, symbols)
+	})
+
+	t.Run("Malformed file fails the import", func(t *testing.T) {
+		reader, err := repository.NewQuoteReader(strings.NewReader("symbol,date,close\nUBER,\"2023-11-01,40\n"), entity.FormatCSV)
+		assert.NoError(t, err)
+		_, err = Importer{Repository: repository.NewMemory()}.Import(context.Background(), reader)
+		assert.Error(t, err)
+	})
+}
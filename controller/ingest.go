@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"stockpricews/entity"
+	"stockpricews/repository"
+	"time"
+)
+
+// SaveStockQuotes validates the quotes and upserts the valid ones in a single transaction. Invalid quotes are reported
+// in the result by their 1-based position instead of failing the whole batch, the error is returned only if the valid
+// quotes couldn't be stored. The stored quotes are published to the Feed and appended to the Index with the missing
+// open, high and low prices completed by the close, the same as they're read back from the repository.
+func (c MaxProfitController) SaveStockQuotes(ctx context.Context, quotes []entity.StockQuote) (entity.QuoteIngestion, error) {
+	result := entity.QuoteIngestion{Rejected: []entity.RejectedQuote{}}
+	valid := make([]entity.StockQuote, 0, len(quotes))
+	now := time.Now()
+	for i, quote := range quotes {
+		if err := validateQuote(quote, now); err != nil {
+			result.Rejected = append(result.Rejected, entity.RejectedQuote{Row: i + 1, Err: err})
+			continue
+		}
+		quote.ID = 0
+		valid = append(valid, quote)
+	}
+
+	if len(valid) == 0 {
+		return result, nil
+	}
+
+	if err := c.Repository.SaveStockQuotes(ctx, valid); err != nil {
+		return entity.QuoteIngestion{}, fmt.Errorf("failed to store %d quotes: %w", len(valid), err)
+	}
+	result.Stored = len(valid)
+
+	// the quotes of a batch are appended to the index and delivered to the subscribers in chronological order
+	sort.SliceStable(valid, func(i, j int) bool { return valid[i].Datepoint.Before(valid[j].Datepoint) })
+	for _, quote := range valid {
+		// published the same as they're read back, so the replayed quotes are told apart from the new ones
+		quote = repository.CompletePrices(quote)
+		if c.Index != nil {
+			c.Index.Append(quote)
+		}
+		if c.Feed != nil {
+			c.Feed.Publish(quote)
+		}
+	}
+
+	return result, nil
+}
+
+// validateQuote checks the quote can be stored, now is the latest date point accepted
+func validateQuote(quote entity.StockQuote, now time.Time) error {
+	if err := ValidateSymbol(quote.Symbol); err != nil {
+		return err
+	}
+
+	if quote.Datepoint.IsZero() {
+		return fmt.Errorf("datepoint is missing: %w", entity.ErrBadRequest)
+	}
+	if quote.Datepoint.After(now) {
+		return fmt.Errorf("datepoint %s is in the future: %w", quote.Datepoint.Format(time.RFC3339), entity.ErrBadRequest)
+	}
+
+	if !(quote.Price > 0) || math.IsInf(quote.Price, 0) {
+		return fmt.Errorf("price must be a positive number: %w", entity.ErrBadRequest)
+	}
+
+	// open, high and low are optional, zero stands for a missing price
+	for _, price := range []struct {
+		name  string
+		value float64
+	}{{"open", quote.Open}, {"high", quote.High}, {"low", quote.Low}} {
+		if price.value < 0 || math.IsNaN(price.value) || math.IsInf(price.value, 0) {
+			return fmt.Errorf("%s price must be a positive number: %w", price.name, entity.ErrBadRequest)
+		}
+	}
+	if quote.High > 0 && quote.Low > 0 && quote.High < quote.Low {
+		return fmt.Errorf("high price can't be lower than the low price: %w", entity.ErrBadRequest)
+	}
+
+	if quote.Volume < 0 {
+		return fmt.Errorf("volume can't be negative: %w", entity.ErrBadRequest)
+	}
+
+	return nil
+}
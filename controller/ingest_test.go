@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"stockpricews/entity"
+	"stockpricews/repository"
+	"testing"
+	"time"
+)
+
+func TestSaveStockQuotes(t *testing.T) {
+	initialTime := time.Now().Add(-time.Hour)
+	repo := repository.NewMemory(entity.StockQuote{Symbol: "UBER", Datepoint: initialTime, Price: 2})
+	c := MaxProfitController{Repository: repo, Index: NewProfitIndex(repo, 0), Feed: NewQuoteFeed()}
+
+	req := entity.StockQuoteRequest{Symbol: "UBER", Begin: initialTime.Add(-time.Minute), End: time.Now()}
+	_, err := c.MaxProfitForPeriod(context.Background(), req)
+	assert.True(t, errors.Is(err, entity.ErrNotFound))
+	updates, unsubscribe := c.Feed.Subscribe("UBER")
+	defer unsubscribe()
+
+	result, err := c.SaveStockQuotes(context.Background(), []entity.StockQuote{
+		{Symbol: "UBER", Datepoint: initialTime.Add(time.Minute * 2), Price: 5},
+		{Symbol: "UBER/X", Datepoint: initialTime, Price: 5},
+		{Symbol: "UBER", Datepoint: initialTime.Add(time.Minute), Price: 1},
+		{Symbol: "UBER", Datepoint: time.Now().Add(time.Hour), Price: 5},
+		{Symbol: "UBER", Datepoint: initialTime, Price: -1},
+		{Symbol: "UBER", Datepoint: initialTime, Price: math.NaN()},
+		{Symbol: "UBER", Datepoint: initialTime, Price: 3, High: 2, Low: 4},
+		{Symbol: "UBER", Price: 3},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Stored)
+	rows := make([]int, len(result.Rejected))
+	for i, rejected := range result.Rejected {
+		rows[i] = rejected.Row
+		assert.True(t, errors.Is(rejected.Err, entity.ErrBadRequest))
+	}
+	assert.Equal(t, []int{2, 4, 5, 6, 7, 8}, rows)
+
+	// the stored quotes are queryable right away, from the index as well
+	got, err := c.MaxProfitForPeriod(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, got.BuyPoint.Price)
+	assert.Equal(t, 5.0, got.SellPoint.Price)
+
+	// and published to the subscribers in chronological order, the ones dated before the subscription as well
+	assert.Equal(t, 1.0, (<-updates).Price)
+	assert.Equal(t, 5.0, (<-updates).Price)
+	late := entity.StockQuote{Symbol: "UBER", Datepoint: time.Now(), Price: 6}
+	_, err = c.SaveStockQuotes(context.Background(), []entity.StockQuote{late})
+	assert.NoError(t, err)
+	assert.Equal(t, 6.0, (<-updates).Price)
+
+	dbErr := errors.New("connection refused")
+	_, err = MaxProfitController{Repository: &mockRepository{err: dbErr}}.SaveStockQuotes(context.Background(),
+		[]entity.StockQuote{{Symbol: "UBER", Datepoint: initialTime, Price: 1}})
+	assert.True(t, errors.Is(err, dbErr))
+}
@@ -1,9 +1,25 @@
 package controller
 
 import (
+	"context"
 	"stockpricews/entity"
 )
 
+// Controller computes the max profit results. The context bounds the loading of the quotes, the computations abort
+// with its error once it is done.
 type Controller interface {
-	MaxProfitForPeriod(timeSlice entity.StockQuoteRequest) (entity.MaxProfitPoints, error)
+	MaxProfitForPeriod(ctx context.Context, timeSlice entity.StockQuoteRequest) (entity.MaxProfitPoints, error)
+	MaxShortProfitForPeriod(ctx context.Context, timeSlice entity.StockQuoteRequest) (entity.MaxProfitPoints, error)
+	WithPosition(points entity.MaxProfitPoints, position entity.Position) (entity.MaxProfitPoints, error)
+	MaxProfitForTransactions(ctx context.Context, timeSlice entity.StockQuoteRequest, k int) (entity.MultiTradeProfit, error)
+	MaxProfitWithCosts(ctx context.Context, timeSlice entity.StockQuoteRequest, costs entity.TradeCosts) (entity.MultiTradeProfit, error)
+	TopTradeWindows(ctx context.Context, timeSlice entity.StockQuoteRequest, n int, rankBy entity.RankBy) (entity.TopTradeWindows, error)
+	SymbolLeaderboard(ctx context.Context, symbols []string, timeSlice entity.StockQuoteRequest) (entity.Leaderboard, error)
+	MaxProfitUpdates(ctx context.Context, timeSlice entity.StockQuoteRequest) (<-chan entity.MaxProfitPoints, func(), error)
+	QuoteUpdates(ctx context.Context, req entity.StockQuoteRequest) (<-chan entity.StockQuote, func(), error)
+	SaveStockQuotes(ctx context.Context, quotes []entity.StockQuote) (entity.QuoteIngestion, error)
+	ExportStockQuotes(ctx context.Context, timeSlice entity.StockQuoteRequest, fn func(entity.StockQuote) error) error
+	SymbolCatalog(ctx context.Context) (entity.SymbolCatalog, error)
+	DescribeSymbol(ctx context.Context, symbol string) (entity.Symbol, error)
+	AddSymbol(ctx context.Context, symbol entity.Symbol) (entity.Symbol, error)
 }
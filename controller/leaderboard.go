@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"stockpricews/entity"
+	"sync"
+)
+
+// defaultLeaderboardWorkers is the number of symbols computed concurrently if MaxProfitController.Workers is not set
+const defaultLeaderboardWorkers = 4
+
+// SymbolLeaderboard computes the max profit of every given symbol (or all stored symbols if none is given) in the
+// time slice and ranks them by percentage return. Failures of individual symbols are reported in their entries
+// instead of failing the whole leaderboard.
+func (c MaxProfitController) SymbolLeaderboard(ctx context.Context, symbols []string, timeSlice entity.StockQuoteRequest) (entity.Leaderboard, error) {
+	if len(symbols) == 0 {
+		var err error
+		if symbols, err = c.Repository.Symbols(ctx); err != nil {
+			return entity.Leaderboard{}, err
+		}
+		if len(symbols) == 0 {
+			return entity.Leaderboard{}, fmt.Errorf("no symbols found: %w", entity.ErrNotFound)
+		}
+	}
+
+	workers := c.Workers
+	if workers <= 0 {
+		workers = defaultLeaderboardWorkers
+	}
+	if workers > len(symbols) {
+		workers = len(symbols)
+	}
+
+	entries := make([]entity.SymbolPerformance, len(symbols))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				entries[i] = c.symbolPerformance(ctx, symbols[i], timeSlice)
+			}
+		}()
+	}
+	for i := range symbols {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	// the symbols failed because of the deadline or the client went away, the partial result isn't reported
+	if err := ctx.Err(); err != nil {
+		return entity.Leaderboard{}, err
+	}
+
+	return rankLeaderboard(entries), nil
+}
+
+func (c MaxProfitController) symbolPerformance(ctx context.Context, symbol string, timeSlice entity.StockQuoteRequest) entity.SymbolPerformance {
+	timeSlice.Symbol = symbol
+	points, err := c.MaxProfitForPeriod(ctx, timeSlice)
+	if err != nil {
+		return entity.SymbolPerformance{Symbol: symbol, Err: err}
+	}
+
+	return entity.SymbolPerformance{
+		Symbol:    symbol,
+		MaxProfit: &points,
+		Profit:    points.Profit,
+		Return:    points.Return,
+	}
+}
+
+// rankLeaderboard orders the entries by percentage return, the failed ones go last in the order of the symbols
+func rankLeaderboard(entries []entity.SymbolPerformance) entity.Leaderboard {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if (entries[i].Err == nil) != (entries[j].Err == nil) {
+			return entries[i].Err == nil
+		}
+		if entries[i].Err != nil {
+			return entries[i].Symbol < entries[j].Symbol
+		}
+		return entries[i].Return > entries[j].Return
+	})
+
+	for i := range entries {
+		if entries[i].Err == nil {
+			entries[i].Rank = i + 1
+		}
+	}
+
+	return entity.Leaderboard{Entries: entries}
+}
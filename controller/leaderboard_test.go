@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"stockpricews/entity"
+	"testing"
+	"time"
+)
+
+type multiSymbolRepository struct {
+	mockRepository
+	histories map[string][]entity.StockQuote
+	errs      map[string]error
+}
+
+func (r *multiSymbolRepository) StockQuotesPerTimeSlice(ctx context.Context, req entity.StockQuoteRequest) ([]entity.StockQuote, error) {
+	return r.histories[req.Symbol], r.errs[req.Symbol]
+}
+
+func (r *multiSymbolRepository) Symbols(ctx context.Context) ([]string, error) {
+	return []string{"AAPL", "TSLA", "UBER", "MSFT"}, r.err
+}
+
+func TestSymbolLeaderboard(t *testing.T) {
+	_, history := quoteFixture(time.Unix(1699228800, 0), time.Hour, 2)
+
+	dbErr := errors.New("connection refused")
+	repo := &multiSymbolRepository{
+		histories: map[string][]entity.StockQuote{
+			"AAPL": history(100, 110),
+			"TSLA": history(10, 15),
+			"UBER": history(50, 40),
+		},
+		errs: map[string]error{"MSFT": dbErr},
+	}
+	c := MaxProfitController{Repository: repo, Workers: 2}
+
+	t.Run("All stored symbols", func(t *testing.T) {
+		got, err := c.SymbolLeaderboard(context.Background(), nil, entity.StockQuoteRequest{})
+		assert.NoError(t, err)
+		assert.Len(t, got.Entries, 4)
+
+		assert.Equal(t, 1, got.Entries[0].Rank)
+		assert.Equal(t, "TSLA", got.Entries[0].Symbol)
+		assert.Equal(t, 50.0, got.Entries[0].Return)
+		assert.Equal(t, 5.0, got.Entries[0].Profit)
+
+		assert.Equal(t, 2, got.Entries[1].Rank)
+		assert.Equal(t, "AAPL", got.Entries[1].Symbol)
+		assert.Equal(t, 10.0, got.Entries[1].Return)
+
+		// failed symbols are not ranked and go last
+		assert.Equal(t, "MSFT", got.Entries[2].Symbol)
+		assert.Equal(t, 0, got.Entries[2].Rank)
+		assert.True(t, errors.Is(got.Entries[2].Err, dbErr))
+		assert.Equal(t, "UBER", got.Entries[3].Symbol)
+		assert.True(t, errors.Is(got.Entries[3].Err, entity.ErrNotFound))
+		assert.Nil(t, got.Entries[3].MaxProfit)
+	})
+
+	t.Run("Given symbols only", func(t *testing.T) {
+		got, err := c.SymbolLeaderboard(context.Background(), []string{"AAPL"}, entity.StockQuoteRequest{})
+		assert.NoError(t, err)
+		assert.Len(t, got.Entries, 1)
+		assert.Equal(t, "AAPL", got.Entries[0].Symbol)
+		assert.Equal(t, 110.0, got.Entries[0].MaxProfit.SellPoint.Price)
+	})
+
+	t.Run("Symbols can't be listed", func(t *testing.T) {
+		failing := MaxProfitController{Repository: &multiSymbolRepository{mockRepository: mockRepository{err: dbErr}}}
+		_, err := failing.SymbolLeaderboard(context.Background(), nil, entity.StockQuoteRequest{})
+		assert.True(t, errors.Is(err, dbErr))
+	})
+}
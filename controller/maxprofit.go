@@ -1,13 +1,31 @@
 package controller
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"stockpricews/entity"
 	"stockpricews/repository"
+	"time"
+)
+
+const (
+	secondsPerDay = 24 * 60 * 60
+	daysPerYear   = 365.25
 )
 
 type MaxProfitController struct {
 	Repository repository.Repository
+	// Index is optional, if set single transaction queries for raw prices are answered from it instead of rescanning
+	// the repository
+	Index *ProfitIndex
+	// Feed is optional, if set clients can subscribe to the running max profit of a symbol
+	Feed *QuoteFeed
+	// Workers is the number of symbols computed concurrently by SymbolLeaderboard
+	Workers int
+	// ExportIdleTimeout bounds the time ExportStockQuotes waits for a single quote to be taken, DefaultExportIdleTimeout
+	// if zero
+	ExportIdleTimeout time.Duration
 }
 
 // New initializes MaxProfitController that is used to calculate the maximum possible profit in a given historical time slice
@@ -15,48 +33,152 @@ func New(repository repository.Repository) MaxProfitController {
 	return MaxProfitController{Repository: repository}
 }
 
-func (c MaxProfitController) MaxProfitForPeriod(req entity.StockQuoteRequest) (entity.MaxProfitPoints, error) {
-	history, err := c.Repository.StockQuotesPerTimeSlice(req)
+func (c MaxProfitController) MaxProfitForPeriod(ctx context.Context, req entity.StockQuoteRequest) (entity.MaxProfitPoints, error) {
+	// the index holds raw close prices only
+	if c.Index != nil && !req.Adjusted && (req.Field == "" || req.Field == entity.FieldClose) {
+		return c.Index.MaxProfitForPeriod(ctx, req)
+	}
+
+	history, err := c.Repository.StockQuotesPerTimeSlice(ctx, req)
+	if err != nil {
+		return entity.MaxProfitPoints{}, err
+	}
+
+	return maxProfitAt(history, req.Field, entity.DirectionLong)
+}
+
+// MaxShortProfitForPeriod calculates the maximum profit of selling short and buying back later in a given historical
+// time slice, i.e. the maximum drawdown of the stock price
+func (c MaxProfitController) MaxShortProfitForPeriod(ctx context.Context, req entity.StockQuoteRequest) (entity.MaxProfitPoints, error) {
+	history, err := c.Repository.StockQuotesPerTimeSlice(ctx, req)
 	if err != nil {
 		return entity.MaxProfitPoints{}, err
 	}
 
-	return maxProfitForPeriod(history)
+	return maxProfitAt(history, req.Field, entity.DirectionShort)
 }
 
 func maxProfitForPeriod(history []entity.StockQuote) (entity.MaxProfitPoints, error) {
+	return maxProfitAt(history, entity.FieldClose, entity.DirectionLong)
+}
+
+func maxShortProfitForPeriod(history []entity.StockQuote) (entity.MaxProfitPoints, error) {
+	return maxProfitAt(history, entity.FieldClose, entity.DirectionShort)
+}
+
+// maxProfitAt finds the most profitable single position in the given direction trading at the given price field
+func maxProfitAt(history []entity.StockQuote, field entity.PriceField, direction entity.Direction) (entity.MaxProfitPoints, error) {
+	entryPrice, exitPrice, err := tradePrices(field, direction)
+	if err != nil {
+		return entity.MaxProfitPoints{}, err
+	}
+
+	entryIdx, exitIdx, err := bestTrade(history, direction, entryPrice, exitPrice)
+	if err != nil {
+		return entity.MaxProfitPoints{}, err
+	}
+
+	entry, exit := history[entryIdx], history[exitIdx]
+	entry.Price, exit.Price = entryPrice(entry), exitPrice(exit)
+	return newMaxProfitPoints(entry, exit, direction), nil
+}
+
+// newMaxProfitPoints builds the result of a trade opened at entry and closed at exit along with its returns.
+// A long position is opened by buying and closed by selling, a short one the other way around.
+func newMaxProfitPoints(entry, exit entity.StockQuote, direction entity.Direction) entity.MaxProfitPoints {
+	points := entity.MaxProfitPoints{
+		BuyPoint:    entity.TradePoint{Price: entry.Price, Date: entry.Datepoint},
+		SellPoint:   entity.TradePoint{Price: exit.Price, Date: exit.Datepoint},
+		Direction:   direction,
+		HoldingDays: exit.Datepoint.Sub(entry.Datepoint).Seconds() / secondsPerDay,
+	}
+	if direction == entity.DirectionShort {
+		points.BuyPoint, points.SellPoint = points.SellPoint, points.BuyPoint
+	}
+
+	points.Profit = points.SellPoint.Price - points.BuyPoint.Price
+	if entry.Price != 0 {
+		points.Return = points.Profit / entry.Price * 100
+	}
+
+	// annualizing returns of positions held for less than a day makes no sense and overflows easily
+	if points.HoldingDays >= 1 {
+		cagr := (math.Pow(1+points.Return/100, daysPerYear/points.HoldingDays) - 1) * 100
+		if !math.IsInf(cagr, 0) && !math.IsNaN(cagr) {
+			points.AnnualizedReturn = &cagr
+		}
+	}
+
+	return points
+}
+
+// WithPosition adds the profit realized by the given position size to the max profit result. The position is either
+// a number of shares or the capital invested when opening the position, in which case fractional shares are assumed.
+func (c MaxProfitController) WithPosition(points entity.MaxProfitPoints, position entity.Position) (entity.MaxProfitPoints, error) {
+	if position.Shares < 0 || position.Capital < 0 || (position.Shares > 0) == (position.Capital > 0) {
+		return entity.MaxProfitPoints{}, fmt.Errorf("either a positive number of shares or capital must be given: %w", entity.ErrBadRequest)
+	}
+
+	entryPrice := points.BuyPoint.Price
+	if points.Direction == entity.DirectionShort {
+		entryPrice = points.SellPoint.Price
+	}
+
+	shares := position.Shares
+	if position.Capital > 0 {
+		if entryPrice <= 0 {
+			return entity.MaxProfitPoints{}, fmt.Errorf("capital can't be invested at price %v: %w", entryPrice, entity.ErrBadRequest)
+		}
+		shares = position.Capital / entryPrice
+	}
+
+	points.Position = &entity.PositionProfit{
+		Shares:  shares,
+		Capital: shares * entryPrice,
+		Profit:  shares * points.Profit,
+	}
+
+	return points, nil
+}
+
+// bestTrade returns the indexes of the quotes where the most profitable position in the given direction is
+// opened and closed at the prices selected by entryPrice and exitPrice. Short positions are handled by negating the
+// prices so the same single pass applies.
+func bestTrade(history []entity.StockQuote, direction entity.Direction, entryPrice, exitPrice func(entity.StockQuote) float64) (int, int, error) {
 	if len(history) == 0 {
-		return entity.MaxProfitPoints{}, fmt.Errorf("no records found for the given period: %w", entity.ErrNotFound)
+		return 0, 0, fmt.Errorf("no records found for the given period: %w", entity.ErrNotFound)
+	}
+
+	sign := float64(1)
+	if direction == entity.DirectionShort {
+		sign = -1
 	}
 
 	// holds the current max margin
 	maxMargin := float64(0)
-	// holds the current lowest price
-	lowestPrice := history[0].Price
+	// holds the current lowest entry price
+	lowestPrice := sign * entryPrice(history[0])
 
 	// indexes of current low price and the prices that were used to compute the max margin
 	var currLowIdx, lowIdx, highIdx int
 	for i := 1; i < len(history); i++ {
-		currentPrice := history[i].Price
-		margin := currentPrice - lowestPrice
-		if margin < 0 {
-			// new lowest price - update the price and indexes
-			lowestPrice = currentPrice
-			currLowIdx = i
-		} else if margin > maxMargin {
+		// the position is closed at a later quote than it was opened
+		if margin := sign*exitPrice(history[i]) - lowestPrice; margin > maxMargin {
 			// new max margin - update the margin and indexes
 			maxMargin = margin
 			highIdx = i
 			lowIdx = currLowIdx
 		}
+		if currentPrice := sign * entryPrice(history[i]); currentPrice < lowestPrice {
+			// new lowest price - update the price and indexes
+			lowestPrice = currentPrice
+			currLowIdx = i
+		}
 	}
 
 	if maxMargin == 0 {
-		return entity.MaxProfitPoints{}, fmt.Errorf("it's not possible to realize a profit in the given period: %w", entity.ErrNotFound)
+		return 0, 0, fmt.Errorf("it's not possible to realize a profit in the given period: %w", entity.ErrNotFound)
 	}
 
-	return entity.MaxProfitPoints{
-		BuyPoint:  entity.TradePoint{Price: history[lowIdx].Price, Date: history[lowIdx].Datepoint},
-		SellPoint: entity.TradePoint{Price: history[highIdx].Price, Date: history[highIdx].Datepoint},
-	}, nil
+	return lowIdx, highIdx, nil
 }
@@ -35,8 +35,12 @@ func TestMaxProfitForPeriod(t *testing.T) {
 				{Datepoint: times[3], Price: 4.0},
 			},
 			expected: entity.MaxProfitPoints{
-				BuyPoint:  entity.TradePoint{1.0, times[0]},
-				SellPoint: entity.TradePoint{4.0, times[3]},
+				BuyPoint:    entity.TradePoint{Price: 1.0, Date: times[0]},
+				SellPoint:   entity.TradePoint{Price: 4.0, Date: times[3]},
+				Direction:   entity.DirectionLong,
+				Profit:      3,
+				Return:      300,
+				HoldingDays: 3.0 / secondsPerDay,
 			},
 		},
 		{
@@ -48,8 +52,12 @@ func TestMaxProfitForPeriod(t *testing.T) {
 				{Datepoint: times[3], Price: 5.0},
 			},
 			expected: entity.MaxProfitPoints{
-				BuyPoint:  entity.TradePoint{1.0, times[2]},
-				SellPoint: entity.TradePoint{5.0, times[3]},
+				BuyPoint:    entity.TradePoint{Price: 1.0, Date: times[2]},
+				SellPoint:   entity.TradePoint{Price: 5.0, Date: times[3]},
+				Direction:   entity.DirectionLong,
+				Profit:      4,
+				Return:      400,
+				HoldingDays: 1.0 / secondsPerDay,
 			},
 		},
 		{
@@ -63,8 +71,12 @@ func TestMaxProfitForPeriod(t *testing.T) {
 				{Datepoint: times[5], Price: 5.0},
 			},
 			expected: entity.MaxProfitPoints{
-				BuyPoint:  entity.TradePoint{2.0, times[2]},
-				SellPoint: entity.TradePoint{6.0, times[3]},
+				BuyPoint:    entity.TradePoint{Price: 2.0, Date: times[2]},
+				SellPoint:   entity.TradePoint{Price: 6.0, Date: times[3]},
+				Direction:   entity.DirectionLong,
+				Profit:      4,
+				Return:      200,
+				HoldingDays: 1.0 / secondsPerDay,
 			},
 		},
 		{
@@ -76,8 +88,12 @@ func TestMaxProfitForPeriod(t *testing.T) {
 				{Datepoint: times[3], Price: 4.0},
 			},
 			expected: entity.MaxProfitPoints{
-				BuyPoint:  entity.TradePoint{1.0, times[0]},
-				SellPoint: entity.TradePoint{4.0, times[1]},
+				BuyPoint:    entity.TradePoint{Price: 1.0, Date: times[0]},
+				SellPoint:   entity.TradePoint{Price: 4.0, Date: times[1]},
+				Direction:   entity.DirectionLong,
+				Profit:      3,
+				Return:      300,
+				HoldingDays: 1.0 / secondsPerDay,
 			},
 		},
 		{
@@ -91,8 +107,12 @@ func TestMaxProfitForPeriod(t *testing.T) {
 				{Datepoint: times[5], Price: 2.0},
 			},
 			expected: entity.MaxProfitPoints{
-				BuyPoint:  entity.TradePoint{1.0, times[0]},
-				SellPoint: entity.TradePoint{2.0, times[3]},
+				BuyPoint:    entity.TradePoint{Price: 1.0, Date: times[0]},
+				SellPoint:   entity.TradePoint{Price: 2.0, Date: times[3]},
+				Direction:   entity.DirectionLong,
+				Profit:      1,
+				Return:      100,
+				HoldingDays: 3.0 / secondsPerDay,
 			},
 		},
 		{
@@ -119,3 +139,178 @@ func TestMaxProfitForPeriod(t *testing.T) {
 		})
 	}
 }
+
+func TestMaxShortProfitForPeriod(t *testing.T) {
+	initialTime := time.Now()
+	times := make([]time.Time, 10)
+	for i := 0; i < 10; i++ {
+		times[i] = initialTime.Add(time.Second * time.Duration(i))
+	}
+
+	testCases := []struct {
+		name        string
+		history     []entity.StockQuote
+		expected    entity.MaxProfitPoints
+		expectedErr error
+	}{
+		{
+			name:        "No history records found - error not found",
+			history:     []entity.StockQuote{},
+			expectedErr: entity.ErrNotFound,
+		},
+		{
+			name: "Prices in ascending order - we can't realize profits",
+			history: []entity.StockQuote{
+				{Datepoint: times[0], Price: 1.0},
+				{Datepoint: times[1], Price: 2.0},
+				{Datepoint: times[2], Price: 3.0},
+			},
+			expectedErr: entity.ErrNotFound,
+		},
+		{
+			name: "Prices in descending order",
+			history: []entity.StockQuote{
+				{Datepoint: times[0], Price: 4.0},
+				{Datepoint: times[1], Price: 3.0},
+				{Datepoint: times[2], Price: 2.0},
+				{Datepoint: times[3], Price: 1.0},
+			},
+			expected: entity.MaxProfitPoints{
+				SellPoint:   entity.TradePoint{Price: 4.0, Date: times[0]},
+				BuyPoint:    entity.TradePoint{Price: 1.0, Date: times[3]},
+				Direction:   entity.DirectionShort,
+				Profit:      3,
+				Return:      75,
+				HoldingDays: 3.0 / secondsPerDay,
+			},
+		},
+		{
+			name: "Max drawdown is not at the min price",
+			history: []entity.StockQuote{
+				{Datepoint: times[0], Price: 2.0},
+				{Datepoint: times[1], Price: 1.0},
+				{Datepoint: times[2], Price: 9.0},
+				{Datepoint: times[3], Price: 4.0},
+			},
+			expected: entity.MaxProfitPoints{
+				SellPoint:   entity.TradePoint{Price: 9.0, Date: times[2]},
+				BuyPoint:    entity.TradePoint{Price: 4.0, Date: times[3]},
+				Direction:   entity.DirectionShort,
+				Profit:      5,
+				Return:      5.0 / 9 * 100,
+				HoldingDays: 1.0 / secondsPerDay,
+			},
+		},
+		{
+			name: "Two variants for max drawdown - take the earliest",
+			history: []entity.StockQuote{
+				{Datepoint: times[0], Price: 5.0},
+				{Datepoint: times[1], Price: 2.0},
+				{Datepoint: times[2], Price: 6.0},
+				{Datepoint: times[3], Price: 3.0},
+			},
+			expected: entity.MaxProfitPoints{
+				SellPoint:   entity.TradePoint{Price: 5.0, Date: times[0]},
+				BuyPoint:    entity.TradePoint{Price: 2.0, Date: times[1]},
+				Direction:   entity.DirectionShort,
+				Profit:      3,
+				Return:      60,
+				HoldingDays: 1.0 / secondsPerDay,
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := maxShortProfitForPeriod(tt.history)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewMaxProfitPoints(t *testing.T) {
+	day := time.Unix(1699228800, 0)
+	year := day.Add(time.Hour * 24 * 365)
+	halfYear := day.Add(time.Hour * 12 * 365)
+
+	long := newMaxProfitPoints(entity.StockQuote{Datepoint: day, Price: 50}, entity.StockQuote{Datepoint: year, Price: 75}, entity.DirectionLong)
+	assert.Equal(t, 25.0, long.Profit)
+	assert.Equal(t, 50.0, long.Return)
+	assert.Equal(t, 365.0, long.HoldingDays)
+	assert.InDelta(t, 50.04, *long.AnnualizedReturn, 0.01)
+
+	// a short position returns relative to the price it's sold at
+	short := newMaxProfitPoints(entity.StockQuote{Datepoint: day, Price: 80}, entity.StockQuote{Datepoint: halfYear, Price: 60}, entity.DirectionShort)
+	assert.Equal(t, entity.TradePoint{Price: 80, Date: day}, short.SellPoint)
+	assert.Equal(t, entity.TradePoint{Price: 60, Date: halfYear}, short.BuyPoint)
+	assert.Equal(t, 20.0, short.Profit)
+	assert.Equal(t, 25.0, short.Return)
+	assert.Equal(t, 182.5, short.HoldingDays)
+	assert.InDelta(t, 56.30, *short.AnnualizedReturn, 0.01)
+
+	// no annualized return for intraday trades
+	intraday := newMaxProfitPoints(entity.StockQuote{Datepoint: day, Price: 1}, entity.StockQuote{Datepoint: day.Add(time.Hour), Price: 2}, entity.DirectionLong)
+	assert.Nil(t, intraday.AnnualizedReturn)
+}
+
+func TestWithPosition(t *testing.T) {
+	day := time.Unix(1699228800, 0)
+	long := newMaxProfitPoints(entity.StockQuote{Datepoint: day, Price: 50}, entity.StockQuote{Datepoint: day.Add(time.Hour), Price: 60}, entity.DirectionLong)
+	short := newMaxProfitPoints(entity.StockQuote{Datepoint: day, Price: 80}, entity.StockQuote{Datepoint: day.Add(time.Hour), Price: 60}, entity.DirectionShort)
+
+	testCases := []struct {
+		name        string
+		points      entity.MaxProfitPoints
+		position    entity.Position
+		expected    *entity.PositionProfit
+		expectedErr error
+	}{
+		{
+			name:     "Number of shares",
+			points:   long,
+			position: entity.Position{Shares: 10},
+			expected: &entity.PositionProfit{Shares: 10, Capital: 500, Profit: 100},
+		},
+		{
+			name:     "Invested capital buys fractional shares",
+			points:   long,
+			position: entity.Position{Capital: 1000},
+			expected: &entity.PositionProfit{Shares: 20, Capital: 1000, Profit: 200},
+		},
+		{
+			name:     "Short position is opened at the sell price",
+			points:   short,
+			position: entity.Position{Capital: 400},
+			expected: &entity.PositionProfit{Shares: 5, Capital: 400, Profit: 100},
+		},
+		{
+			name:        "Both shares and capital",
+			points:      long,
+			position:    entity.Position{Shares: 1, Capital: 1},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:        "Negative shares",
+			points:      long,
+			position:    entity.Position{Shares: -1},
+			expectedErr: entity.ErrBadRequest,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MaxProfitController{}.WithPosition(tt.points, tt.position)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, got.Position)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"fmt"
+	"stockpricews/entity"
+)
+
+// fieldPrice returns the requested price of the quote. Quotes with a single price only have the close set, in which
+// case it stands for the open, high and low too.
+func fieldPrice(quote entity.StockQuote, field entity.PriceField) float64 {
+	var price float64
+	switch field {
+	case entity.FieldOpen:
+		price = quote.Open
+	case entity.FieldHigh:
+		price = quote.High
+	case entity.FieldLow:
+		price = quote.Low
+	}
+
+	if price == 0 {
+		return quote.Price
+	}
+	return price
+}
+
+// priceSeries returns a copy of the history with the price replaced by the requested field, so the algorithms
+// trading on a single price series can use any of them. Fields with different buy and sell prices are not supported.
+func priceSeries(history []entity.StockQuote, field entity.PriceField) ([]entity.StockQuote, error) {
+	switch field {
+	case "", entity.FieldClose:
+		return history, nil
+	case entity.FieldOpen, entity.FieldHigh, entity.FieldLow:
+		series := make([]entity.StockQuote, len(history))
+		for i, quote := range history {
+			series[i] = quote
+			series[i].Price = fieldPrice(quote, field)
+		}
+		return series, nil
+	case entity.FieldOptimistic, entity.FieldPessimistic:
+		return nil, fmt.Errorf("price field %s is supported for a single transaction only: %w", field, entity.ErrBadRequest)
+	default:
+		return nil, fmt.Errorf("unknown price field %s: %w", field, entity.ErrBadRequest)
+	}
+}
+
+// tradePrices returns the functions selecting the price a position in the given direction is opened and closed at
+func tradePrices(field entity.PriceField, direction entity.Direction) (func(entity.StockQuote) float64, func(entity.StockQuote) float64, error) {
+	price := func(field entity.PriceField) func(entity.StockQuote) float64 {
+		return func(quote entity.StockQuote) float64 { return fieldPrice(quote, field) }
+	}
+
+	buy, sell := field, field
+	switch field {
+	case "":
+		buy, sell = entity.FieldClose, entity.FieldClose
+	case entity.FieldOpen, entity.FieldHigh, entity.FieldLow, entity.FieldClose:
+	case entity.FieldOptimistic:
+		buy, sell = entity.FieldLow, entity.FieldHigh
+	case entity.FieldPessimistic:
+		buy, sell = entity.FieldHigh, entity.FieldLow
+	default:
+		return nil, nil, fmt.Errorf("unknown price field %s: %w", field, entity.ErrBadRequest)
+	}
+
+	// a short position is opened by selling and closed by buying back
+	if direction == entity.DirectionShort {
+		return price(sell), price(buy), nil
+	}
+	return price(buy), price(sell), nil
+}
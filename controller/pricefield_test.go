@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"stockpricews/entity"
+	"testing"
+	"time"
+)
+
+func TestMaxProfitAt(t *testing.T) {
+	day := time.Unix(1699228800, 0)
+	history := []entity.StockQuote{
+		{Datepoint: day, Open: 10, High: 12, Low: 8, Price: 11},
+		{Datepoint: day.Add(24 * time.Hour), Open: 11, High: 15, Low: 9, Price: 14},
+		{Datepoint: day.Add(48 * time.Hour), Open: 14, High: 16, Low: 7, Price: 9},
+	}
+
+	testCases := []struct {
+		name        string
+		field       entity.PriceField
+		direction   entity.Direction
+		buy, sell   entity.TradePoint
+		expectedErr error
+	}{
+		{
+			name:      "Close price by default",
+			direction: entity.DirectionLong,
+			buy:       entity.TradePoint{Price: 11, Date: history[0].Datepoint},
+			sell:      entity.TradePoint{Price: 14, Date: history[1].Datepoint},
+		},
+		{
+			name:      "Open price",
+			field:     entity.FieldOpen,
+			direction: entity.DirectionLong,
+			buy:       entity.TradePoint{Price: 10, Date: history[0].Datepoint},
+			sell:      entity.TradePoint{Price: 14, Date: history[2].Datepoint},
+		},
+		{
+			name:      "Optimistic buys at the low and sells at the high",
+			field:     entity.FieldOptimistic,
+			direction: entity.DirectionLong,
+			buy:       entity.TradePoint{Price: 8, Date: history[0].Datepoint},
+			sell:      entity.TradePoint{Price: 16, Date: history[2].Datepoint},
+		},
+		{
+			name:      "Low price",
+			field:     entity.FieldLow,
+			direction: entity.DirectionLong,
+			buy:       entity.TradePoint{Price: 8, Date: history[0].Datepoint},
+			sell:      entity.TradePoint{Price: 9, Date: history[1].Datepoint},
+		},
+		{
+			name:        "Pessimistic buys at the high and sells at the low - no profit",
+			field:       entity.FieldPessimistic,
+			direction:   entity.DirectionLong,
+			expectedErr: entity.ErrNotFound,
+		},
+		{
+			name:      "Optimistic short sells at the high and buys back at the low",
+			field:     entity.FieldOptimistic,
+			direction: entity.DirectionShort,
+			buy:       entity.TradePoint{Price: 7, Date: history[2].Datepoint},
+			sell:      entity.TradePoint{Price: 15, Date: history[1].Datepoint},
+		},
+		{
+			name:        "Pessimistic short can't realize a profit",
+			field:       entity.FieldPessimistic,
+			direction:   entity.DirectionShort,
+			expectedErr: entity.ErrNotFound,
+		},
+		{
+			name:        "Unknown field",
+			field:       "vwap",
+			direction:   entity.DirectionLong,
+			expectedErr: entity.ErrBadRequest,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := maxProfitAt(history, tt.field, tt.direction)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.buy, got.BuyPoint)
+			assert.Equal(t, tt.sell, got.SellPoint)
+			assert.Equal(t, tt.sell.Price-tt.buy.Price, got.Profit)
+		})
+	}
+}
+
+func TestPriceSeries(t *testing.T) {
+	history := []entity.StockQuote{
+		{Open: 10, High: 12, Low: 8, Price: 11},
+		// quotes with the close price only
+		{Price: 14},
+	}
+
+	series, err := priceSeries(history, entity.FieldHigh)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{12, 14}, []float64{series[0].Price, series[1].Price})
+	assert.Equal(t, float64(11), history[0].Price)
+
+	_, err = priceSeries(history, entity.FieldOptimistic)
+	assert.True(t, errors.Is(err, entity.ErrBadRequest))
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"stockpricews/entity"
+	"sync"
+	"time"
+)
+
+// QuoteUpdates subscribes to the new quotes of the requested symbol and streams them as they're stored. The quotes
+// stored after req.Begin are replayed from the repository first in chronological order, so a client resuming the stream
+// gets the quotes it missed; the new ones follow in the order they're stored, whatever their date point. A replayed
+// quote isn't delivered again if it's published while the history is loaded. The context bounds the loading of the
+// stored quotes only. The returned func must be called to release the subscription, the channel is closed afterwards
+// or as soon as the subscription gets dropped for being too slow.
+func (c MaxProfitController) QuoteUpdates(ctx context.Context, req entity.StockQuoteRequest) (<-chan entity.StockQuote, func(), error) {
+	if c.Feed == nil {
+		return nil, nil, fmt.Errorf("streaming of quotes is not enabled: %w", entity.ErrNotFound)
+	}
+
+	// subscribe before loading the history so no quote falls in between, the ones replayed already are skipped
+	quotes, unsubscribe := c.Feed.Subscribe(req.Symbol)
+	history, err := c.Repository.StockQuotesPerTimeSlice(ctx, entity.StockQuoteRequest{Symbol: req.Symbol, Begin: req.Begin, End: time.Now()})
+	if err != nil {
+		unsubscribe()
+		return nil, nil, fmt.Errorf("failed to load stock quotes: %w", err)
+	}
+
+	updates := make(chan entity.StockQuote, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(updates)
+		send := func(quote entity.StockQuote) bool {
+			select {
+			case updates <- quote:
+				return true
+			case <-done:
+				return false
+			}
+		}
+
+		replayed := make(map[int64]entity.StockQuote, len(history))
+		for _, quote := range history {
+			replayed[quote.Datepoint.UnixNano()] = quote
+			if !send(quote) {
+				return
+			}
+		}
+		for quote := range quotes {
+			if sameQuote(replayed[quote.Datepoint.UnixNano()], quote) {
+				continue
+			}
+			if !send(quote) {
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return updates, func() {
+		once.Do(func() {
+			close(done)
+			unsubscribe()
+		})
+	}, nil
+}
+
+// sameQuote reports whether both quotes hold the same values, the IDs aside as the published quotes may have none
+func sameQuote(a, b entity.StockQuote) bool {
+	return a.Symbol == b.Symbol && a.Datepoint.Equal(b.Datepoint) && a.Price == b.Price && a.Open == b.Open &&
+		a.High == b.High && a.Low == b.Low && a.Volume == b.Volume
+}
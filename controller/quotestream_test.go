@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"stockpricews/entity"
+	"stockpricews/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteUpdates(t *testing.T) {
+	initialTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	repo := repository.NewMemory(
+		entity.StockQuote{Symbol: "UBER", Datepoint: initialTime, Price: 2},
+		entity.StockQuote{Symbol: "UBER", Datepoint: initialTime.Add(time.Minute), Price: 3},
+		entity.StockQuote{Symbol: "UBER", Datepoint: initialTime.Add(2 * time.Minute), Price: 4},
+	)
+	c := MaxProfitController{Repository: repo, Feed: NewQuoteFeed()}
+
+	// resuming after the first quote replays the stored ones after it
+	updates, cancel, err := c.QuoteUpdates(context.Background(), entity.StockQuoteRequest{Symbol: "UBER", Begin: initialTime})
+	assert.NoError(t, err)
+	assert.Equal(t, 3.0, (<-updates).Price)
+	assert.Equal(t, 4.0, (<-updates).Price)
+
+	// the stored quotes follow the replayed ones as they're stored, the ones dated before the subscription as well,
+	// e.g. a close posted after the end of the day
+	late := entity.StockQuote{Symbol: "UBER", Datepoint: initialTime.Add(30 * time.Minute), Price: 5}
+	ingestion, err := c.SaveStockQuotes(context.Background(), []entity.StockQuote{
+		late, {Symbol: "TSLA", Datepoint: initialTime.Add(30 * time.Minute), Price: 200}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ingestion.Stored)
+	assert.Equal(t, late.Price, (<-updates).Price)
+
+	// a replayed quote published again is skipped, a corrected one is delivered
+	c.Feed.Publish(repository.CompletePrices(entity.StockQuote{Symbol: "UBER", Datepoint: initialTime.Add(2 * time.Minute), Price: 4}))
+	corrected := entity.StockQuote{Symbol: "UBER", Datepoint: initialTime.Add(2 * time.Minute), Price: 4.5}
+	c.Feed.Publish(corrected)
+	assert.Equal(t, corrected, <-updates)
+
+	cancel()
+	for range updates {
+	}
+	// releasing the subscription twice is safe
+	cancel()
+
+	_, _, err = MaxProfitController{Repository: repo}.QuoteUpdates(context.Background(), entity.StockQuoteRequest{Symbol: "UBER"})
+	assert.True(t, errors.Is(err, entity.ErrNotFound))
+}
+
+// ingestingRepository stores a quote through the controller right before the history is loaded, as if it was posted
+// while the subscriber is replaying
+type ingestingRepository struct {
+	*repository.MemoryRepository
+	ingest func()
+}
+
+func (r *ingestingRepository) StockQuotesPerTimeSlice(ctx context.Context, req entity.StockQuoteRequest) ([]entity.StockQuote, error) {
+	r.ingest()
+	return r.MemoryRepository.StockQuotesPerTimeSlice(ctx, req)
+}
+
+func TestQuoteUpdates_IngestedWhileReplaying(t *testing.T) {
+	initialTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	repo := &ingestingRepository{MemoryRepository: repository.NewMemory(
+		entity.StockQuote{Symbol: "UBER", Datepoint: initialTime, Price: 2},
+	)}
+	c := MaxProfitController{Repository: repo, Feed: NewQuoteFeed()}
+	closeOnly := entity.StockQuote{Symbol: "UBER", Datepoint: initialTime.Add(time.Minute), Price: 3}
+	repo.ingest = func() {
+		_, err := c.SaveStockQuotes(context.Background(), []entity.StockQuote{closeOnly})
+		assert.NoError(t, err)
+	}
+
+	updates, cancel, err := c.QuoteUpdates(context.Background(), entity.StockQuoteRequest{Symbol: "UBER", Begin: initialTime.Add(-time.Second)})
+	assert.NoError(t, err)
+	defer cancel()
+
+	// the quote is both replayed and published, it's delivered once with the open, high and low of the close
+	assert.Equal(t, 2.0, (<-updates).Price)
+	replayed := <-updates
+	assert.Equal(t, []float64{3, 3, 3, 3}, []float64{replayed.Price, replayed.Open, replayed.High, replayed.Low})
+	c.Feed.Publish(entity.StockQuote{Symbol: "UBER", Datepoint: initialTime.Add(2 * time.Minute), Price: 4})
+	assert.Equal(t, 4.0, (<-updates).Price)
+}
+
+func TestQuoteUpdates_DropsSlowSubscriber(t *testing.T) {
+	c := MaxProfitController{Repository: repository.NewMemory(), Feed: NewQuoteFeed()}
+	updates, cancel, err := c.QuoteUpdates(context.Background(), entity.StockQuoteRequest{Symbol: "UBER", Begin: time.Now()})
+	assert.NoError(t, err)
+	defer cancel()
+
+	// the subscriber doesn't read, publishing must not block
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < subscriptionBuffer+2; i++ {
+		c.Feed.Publish(entity.StockQuote{Symbol: "UBER", Datepoint: start.Add(time.Duration(i) * time.Second), Price: float64(i + 1)})
+	}
+	c.Feed.Publish(entity.StockQuote{Symbol: "UBER", Datepoint: start.Add(time.Minute), Price: 1000})
+
+	// the buffered quotes are delivered before the channel is closed
+	received := 0
+	for range updates {
+		received++
+	}
+	assert.Greater(t, received, 0)
+	assert.LessOrEqual(t, received, subscriptionBuffer+2)
+}
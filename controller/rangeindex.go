@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"stockpricews/entity"
+	"stockpricews/repository"
+	"sync"
+	"time"
+)
+
+// ProfitIndex keeps an in-memory segment tree per symbol so the max profit of arbitrary time slices is answered in
+// O(log n) instead of loading and rescanning the whole slice. A symbol is loaded from the repository on its first query
+// and reloaded on the first query after the TTL, so the quotes stored by imports and other processes are picked up.
+type ProfitIndex struct {
+	repository repository.Repository
+	ttl        time.Duration
+	// now is replaced by tests
+	now func() time.Time
+
+	mu    sync.RWMutex
+	trees map[string]*profitTree
+}
+
+// NewProfitIndex initializes an empty ProfitIndex backed by the given repository, the index of a symbol is reloaded
+// after ttl. A zero ttl keeps it until Rebuild, only the quotes added by Append are picked up then.
+func NewProfitIndex(repository repository.Repository, ttl time.Duration) *ProfitIndex {
+	return &ProfitIndex{repository: repository, ttl: ttl, now: time.Now, trees: make(map[string]*profitTree)}
+}
+
+// Rebuild reloads the whole history of the symbol from the repository and replaces its index
+func (ix *ProfitIndex) Rebuild(ctx context.Context, symbol string) error {
+	history, err := ix.repository.StockQuotesPerSymbol(ctx, symbol)
+	if err != nil {
+		return err
+	}
+
+	tree := newProfitTree(history)
+	if ix.ttl > 0 {
+		tree.expires = ix.now().Add(ix.ttl)
+	}
+	ix.mu.Lock()
+	ix.trees[symbol] = tree
+	ix.mu.Unlock()
+
+	return nil
+}
+
+// Append adds a new quote to the index of its symbol. Symbols that are not indexed yet are skipped as they are
+// fully loaded from the repository on the first query anyway.
+func (ix *ProfitIndex) Append(quote entity.StockQuote) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if tree, ok := ix.trees[quote.Symbol]; ok {
+		tree.append(quote)
+	}
+}
+
+// MaxProfitForPeriod answers the max profit for the given time slice from the index of the requested symbol
+func (ix *ProfitIndex) MaxProfitForPeriod(ctx context.Context, req entity.StockQuoteRequest) (entity.MaxProfitPoints, error) {
+	ix.mu.RLock()
+	tree, ok := ix.trees[req.Symbol]
+	ix.mu.RUnlock()
+	if !ok || (!tree.expires.IsZero() && !ix.now().Before(tree.expires)) {
+		if err := ix.Rebuild(ctx, req.Symbol); err != nil {
+			return entity.MaxProfitPoints{}, err
+		}
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	tree = ix.trees[req.Symbol]
+
+	// keep the same boundaries as the repository - both ends of the time slice are exclusive
+	lo := sort.Search(len(tree.quotes), func(i int) bool { return tree.quotes[i].Datepoint.After(req.Begin) })
+	hi := sort.Search(len(tree.quotes), func(i int) bool { return !tree.quotes[i].Datepoint.Before(req.End) }) - 1
+	if lo > hi {
+		return entity.MaxProfitPoints{}, fmt.Errorf("no records found for the given period: %w", entity.ErrNotFound)
+	}
+
+	best := tree.query(lo, hi)
+	if best.profit <= 0 {
+		return entity.MaxProfitPoints{}, fmt.Errorf("it's not possible to realize a profit in the given period: %w", entity.ErrNotFound)
+	}
+
+	return newMaxProfitPoints(tree.quotes[best.buyIdx], tree.quotes[best.sellIdx], entity.DirectionLong), nil
+}
+
+// profitNode holds the aggregated values of a range of quotes - the lowest and the highest price and the best trade
+type profitNode struct {
+	empty           bool
+	minIdx, maxIdx  int
+	buyIdx, sellIdx int
+	profit          float64
+}
+
+// profitTree is a bottom-up segment tree over the quotes of a single symbol ordered by date
+type profitTree struct {
+	quotes []entity.StockQuote
+	// size is the number of leaves, always a power of 2 so appends don't need a rebuild until it's exhausted
+	size  int
+	nodes []profitNode
+	// expires is the time the tree is reloaded from the repository at, zero if it never expires
+	expires time.Time
+}
+
+func newProfitTree(quotes []entity.StockQuote) *profitTree {
+	t := &profitTree{quotes: quotes}
+	t.rebuild()
+	return t
+}
+
+func (t *profitTree) rebuild() {
+	t.size = 1
+	for t.size < len(t.quotes) {
+		t.size *= 2
+	}
+
+	t.nodes = make([]profitNode, 2*t.size)
+	for i := 0; i < t.size; i++ {
+		t.nodes[t.size+i] = t.leaf(i)
+	}
+	for i := t.size - 1; i > 0; i-- {
+		t.nodes[i] = t.merge(t.nodes[2*i], t.nodes[2*i+1])
+	}
+}
+
+// append adds the quote keeping the quotes ordered by date. Quotes newer than the last one are added in O(log n),
+// while older ones (or quotes replacing an existing date point) require a full rebuild.
+func (t *profitTree) append(quote entity.StockQuote) {
+	n := len(t.quotes)
+	if n > 0 && !quote.Datepoint.After(t.quotes[n-1].Datepoint) {
+		i := sort.Search(n, func(i int) bool { return !t.quotes[i].Datepoint.Before(quote.Datepoint) })
+		if t.quotes[i].Datepoint.Equal(quote.Datepoint) {
+			t.quotes[i] = quote
+		} else {
+			t.quotes = append(t.quotes[:i], append([]entity.StockQuote{quote}, t.quotes[i:]...)...)
+		}
+		t.rebuild()
+		return
+	}
+
+	t.quotes = append(t.quotes, quote)
+	if n == t.size {
+		t.rebuild()
+		return
+	}
+
+	i := t.size + n
+	t.nodes[i] = t.leaf(n)
+	for i /= 2; i > 0; i /= 2 {
+		t.nodes[i] = t.merge(t.nodes[2*i], t.nodes[2*i+1])
+	}
+}
+
+// query returns the aggregated node of the quotes in [lo, hi]
+func (t *profitTree) query(lo, hi int) profitNode {
+	left, right := profitNode{empty: true}, profitNode{empty: true}
+	for l, r := lo+t.size, hi+t.size+1; l < r; l, r = l/2, r/2 {
+		if l%2 == 1 {
+			left = t.merge(left, t.nodes[l])
+			l++
+		}
+		if r%2 == 1 {
+			r--
+			right = t.merge(t.nodes[r], right)
+		}
+	}
+
+	return t.merge(left, right)
+}
+
+func (t *profitTree) leaf(i int) profitNode {
+	if i >= len(t.quotes) {
+		return profitNode{empty: true}
+	}
+	return profitNode{minIdx: i, maxIdx: i, buyIdx: i, sellIdx: i}
+}
+
+// merge combines two adjacent ranges. The best trade is either fully in one of them or buys at the lowest price of
+// the left range and sells at the highest price of the right one. Ties are resolved the same way as the single pass
+// scan in maxProfitForPeriod - the earliest sell point wins, then the earliest buy point.
+func (t *profitTree) merge(l, r profitNode) profitNode {
+	if l.empty {
+		return r
+	}
+	if r.empty {
+		return l
+	}
+
+	node := l
+	if t.quotes[r.minIdx].Price < t.quotes[l.minIdx].Price {
+		node.minIdx = r.minIdx
+	}
+	if t.quotes[r.maxIdx].Price > t.quotes[l.maxIdx].Price {
+		node.maxIdx = r.maxIdx
+	}
+
+	cross := profitNode{buyIdx: l.minIdx, sellIdx: r.maxIdx, profit: t.quotes[r.maxIdx].Price - t.quotes[l.minIdx].Price}
+	for _, candidate := range []profitNode{cross, r} {
+		if candidate.profit > node.profit ||
+			(candidate.profit == node.profit && candidate.profit > 0 && node.profit > 0 && candidate.sellIdx < node.sellIdx) {
+			node.buyIdx, node.sellIdx, node.profit = candidate.buyIdx, candidate.sellIdx, candidate.profit
+		}
+	}
+
+	return node
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"stockpricews/entity"
+	"stockpricews/repository"
+	"testing"
+	"time"
+)
+
+type mockRepository struct {
+	history []entity.StockQuote
+	err     error
+}
+
+func (r *mockRepository) StockQuotesPerTimeSlice(ctx context.Context, req entity.StockQuoteRequest) ([]entity.StockQuote, error) {
+	var history []entity.StockQuote
+	for _, q := range r.history {
+		if q.Datepoint.After(req.Begin) && q.Datepoint.Before(req.End) {
+			history = append(history, q)
+		}
+	}
+	return history, r.err
+}
+
+func (r *mockRepository) StockQuotesPerSymbol(ctx context.Context, symbol string) ([]entity.StockQuote, error) {
+	return append([]entity.StockQuote{}, r.history...), r.err
+}
+
+func (r *mockRepository) EachStockQuote(ctx context.Context, req entity.StockQuoteRequest, fn func(entity.StockQuote) error) error {
+	history, err := r.StockQuotesPerTimeSlice(ctx, req)
+	for _, q := range history {
+		if err := fn(q); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (r *mockRepository) Symbols(ctx context.Context) ([]string, error) {
+	return []string{"UBER"}, r.err
+}
+
+func (r *mockRepository) SaveStockQuotes(ctx context.Context, quotes []entity.StockQuote) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.history = append(r.history, quotes...)
+	return nil
+}
+
+func (r *mockRepository) SymbolCatalog(ctx context.Context) ([]entity.Symbol, error) {
+	return []entity.Symbol{{Symbol: "UBER", Timezone: "UTC", Status: entity.SymbolListed}}, r.err
+}
+
+func (r *mockRepository) DescribeSymbol(ctx context.Context, symbol string) (entity.Symbol, error) {
+	return entity.Symbol{Symbol: symbol, Timezone: "UTC", Status: entity.SymbolListed}, r.err
+}
+
+func (r *mockRepository) AddSymbol(ctx context.Context, symbol entity.Symbol) error {
+	return r.err
+}
+
+func TestProfitIndex_MatchesSinglePass(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	initialTime := time.Unix(1699228800, 0)
+	quote := func(i int) entity.StockQuote {
+		// few distinct prices so the tie breaking is exercised as well
+		return entity.StockQuote{Symbol: "UBER", Datepoint: initialTime.Add(time.Hour * time.Duration(i)), Price: float64(rnd.Intn(20))}
+	}
+
+	repo := repository.NewMemory()
+	for i := 0; i < 100; i++ {
+		repo.Append(quote(i))
+	}
+	index := NewProfitIndex(repo, 0)
+	scan := New(repo)
+	indexed := MaxProfitController{Repository: repo, Index: index}
+
+	check := func() {
+		for i := 0; i < 300; i++ {
+			history, _ := repo.StockQuotesPerSymbol(context.Background(), "UBER")
+			begin := initialTime.Add(time.Hour * time.Duration(rnd.Intn(len(history)+2)-1))
+			end := begin.Add(time.Hour * time.Duration(rnd.Intn(len(history)+2)))
+			req := entity.StockQuoteRequest{Symbol: "UBER", Begin: begin, End: end}
+
+			expected, expectedErr := scan.MaxProfitForPeriod(context.Background(), req)
+			got, err := indexed.MaxProfitForPeriod(context.Background(), req)
+			if expectedErr != nil {
+				assert.True(t, errors.Is(err, entity.ErrNotFound))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			}
+		}
+	}
+
+	check()
+
+	// appends beyond the current capacity of the tree as well as within it
+	for i := 100; i < 150; i++ {
+		q := quote(i)
+		repo.Append(q)
+		index.Append(q)
+	}
+	check()
+
+	// quotes older than the last one replace the existing date point
+	q := quote(10)
+	repo.Append(q)
+	index.Append(q)
+	check()
+
+	// rebuild picks up changes that bypassed Append
+	q = quote(20)
+	q.Price = 100
+	repo.Append(q)
+	assert.NoError(t, index.Rebuild(context.Background(), "UBER"))
+	check()
+}
+
+func TestProfitIndex_Errors(t *testing.T) {
+	repoErr := errors.New("connection refused")
+	index := NewProfitIndex(&mockRepository{err: repoErr}, 0)
+	_, err := index.MaxProfitForPeriod(context.Background(), entity.StockQuoteRequest{Symbol: "UBER", End: time.Now()})
+	assert.True(t, errors.Is(err, repoErr))
+
+	index = NewProfitIndex(&mockRepository{}, 0)
+	_, err = index.MaxProfitForPeriod(context.Background(), entity.StockQuoteRequest{Symbol: "UBER", End: time.Now()})
+	assert.True(t, errors.Is(err, entity.ErrNotFound))
+}
+
+func TestProfitIndex_Expires(t *testing.T) {
+	initialTime := time.Unix(1699228800, 0)
+	repo := repository.NewMemory()
+	repo.Append(entity.StockQuote{Symbol: "UBER", Datepoint: initialTime, Price: 1})
+	repo.Append(entity.StockQuote{Symbol: "UBER", Datepoint: initialTime.Add(time.Hour), Price: 2})
+
+	now := initialTime
+	index := NewProfitIndex(repo, time.Minute)
+	index.now = func() time.Time { return now }
+	req := entity.StockQuoteRequest{Symbol: "UBER", Begin: initialTime.Add(-time.Hour), End: initialTime.Add(time.Hour * 24)}
+
+	got, err := index.MaxProfitForPeriod(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, got.Profit)
+
+	// stored by another process, bypassing Append
+	repo.Append(entity.StockQuote{Symbol: "UBER", Datepoint: initialTime.Add(time.Hour * 2), Price: 5})
+	got, err = index.MaxProfitForPeriod(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, got.Profit)
+
+	now = now.Add(time.Minute)
+	got, err = index.MaxProfitForPeriod(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 4.0, got.Profit)
+}
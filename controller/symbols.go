@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"stockpricews/entity"
+	"time"
+)
+
+// MaxSymbolLength is the longest ticker accepted, long enough for the class shares and exchange suffixes like BRK.B or
+// VOD.L and the index tickers like ^GSPC
+const MaxSymbolLength = 16
+
+// symbolPattern starts with a letter, a digit or the index caret, then the class and suffix separators are allowed
+var symbolPattern = regexp.MustCompile(`^[A-Za-z0-9^][A-Za-z0-9.=^-]*$`)
+
+// currencyPattern is an ISO 4217 currency code
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ValidateSymbol checks the ticker has between 1 and MaxSymbolLength chars: letters, digits, '.', '-', '=' or '^'
+func ValidateSymbol(symbol string) error {
+	if len(symbol) < 1 || len(symbol) > MaxSymbolLength {
+		return fmt.Errorf("stock symbol must be between 1 and %d chars long: %w", MaxSymbolLength, entity.ErrBadRequest)
+	}
+	if !symbolPattern.MatchString(symbol) {
+		return fmt.Errorf("stock symbol %q may contain letters, digits, '.', '-', '=' and '^' only: %w", symbol, entity.ErrBadRequest)
+	}
+	return nil
+}
+
+// SymbolCatalog returns all known symbols in alphabetical order
+func (c MaxProfitController) SymbolCatalog(ctx context.Context) (entity.SymbolCatalog, error) {
+	symbols, err := c.Repository.SymbolCatalog(ctx)
+	if err != nil {
+		return entity.SymbolCatalog{}, fmt.Errorf("failed to load symbol catalog: %w", err)
+	}
+	return entity.SymbolCatalog{Symbols: symbols}, nil
+}
+
+// DescribeSymbol returns the metadata of the symbol, entity.ErrNotFound if it isn't in the catalog
+func (c MaxProfitController) DescribeSymbol(ctx context.Context, symbol string) (entity.Symbol, error) {
+	if err := ValidateSymbol(symbol); err != nil {
+		return entity.Symbol{}, err
+	}
+
+	s, err := c.Repository.DescribeSymbol(ctx, symbol)
+	if err != nil {
+		return entity.Symbol{}, fmt.Errorf("failed to describe symbol: %w", err)
+	}
+	return s, nil
+}
+
+// AddSymbol validates the metadata and stores the symbol in the catalog, entity.ErrConflict if it's known already. The
+// timezone defaults to UTC and the status to listed. The date points are taken from the quotes, the passed ones are
+// ignored.
+func (c MaxProfitController) AddSymbol(ctx context.Context, symbol entity.Symbol) (entity.Symbol, error) {
+	if err := ValidateSymbol(symbol.Symbol); err != nil {
+		return entity.Symbol{}, err
+	}
+	if len(symbol.Name) > 255 {
+		return entity.Symbol{}, fmt.Errorf("name must be at most 255 chars long: %w", entity.ErrBadRequest)
+	}
+	if len(symbol.Exchange) > 32 {
+		return entity.Symbol{}, fmt.Errorf("exchange must be at most 32 chars long: %w", entity.ErrBadRequest)
+	}
+	if symbol.Currency != "" && !currencyPattern.MatchString(symbol.Currency) {
+		return entity.Symbol{}, fmt.Errorf("currency must be a 3 letter ISO 4217 code like USD: %w", entity.ErrBadRequest)
+	}
+
+	if symbol.Timezone == "" {
+		symbol.Timezone = "UTC"
+	}
+	if len(symbol.Timezone) > 64 {
+		return entity.Symbol{}, fmt.Errorf("timezone must be at most 64 chars long: %w", entity.ErrBadRequest)
+	}
+	if _, err := time.LoadLocation(symbol.Timezone); err != nil {
+		return entity.Symbol{}, fmt.Errorf("unknown timezone %q: %w", symbol.Timezone, entity.ErrBadRequest)
+	}
+
+	switch symbol.Status {
+	case "":
+		symbol.Status = entity.SymbolListed
+	case entity.SymbolListed, entity.SymbolDelisted:
+	default:
+		return entity.Symbol{}, fmt.Errorf("status must be %s or %s: %w", entity.SymbolListed, entity.SymbolDelisted, entity.ErrBadRequest)
+	}
+
+	symbol.FirstDatapoint, symbol.LastDatapoint = nil, nil
+	if err := c.Repository.AddSymbol(ctx, symbol); err != nil {
+		return entity.Symbol{}, fmt.Errorf("failed to add symbol: %w", err)
+	}
+	return symbol, nil
+}
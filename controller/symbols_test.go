@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"stockpricews/entity"
+	"stockpricews/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSymbol(t *testing.T) {
+	for _, symbol := range []string{"A", "UBER", "BRK.B", "VOD.L", "RDS-A", "^GSPC", "EURUSD=X", "ABCDEFGHIJKLMNOP"} {
+		assert.NoError(t, ValidateSymbol(symbol), symbol)
+	}
+	for _, symbol := range []string{"", "ABCDEFGHIJKLMNOPQ", ".B", "UBER/X", "TES LA", "UBER;"} {
+		assert.True(t, errors.Is(ValidateSymbol(symbol), entity.ErrBadRequest), symbol)
+	}
+}
+
+func TestAddSymbol(t *testing.T) {
+	testCases := []struct {
+		name        string
+		symbol      entity.Symbol
+		expected    entity.Symbol
+		expectedErr error
+	}{
+		{
+			name:     "Defaults",
+			symbol:   entity.Symbol{Symbol: "BRK.B"},
+			expected: entity.Symbol{Symbol: "BRK.B", Timezone: "UTC", Status: entity.SymbolListed},
+		},
+		{
+			name: "All fields",
+			symbol: entity.Symbol{Symbol: "VOD.L", Name: "Vodafone Group", Exchange: "LSE", Currency: "GBP",
+				Timezone: "Europe/London", Status: entity.SymbolDelisted},
+			expected: entity.Symbol{Symbol: "VOD.L", Name: "Vodafone Group", Exchange: "LSE", Currency: "GBP",
+				Timezone: "Europe/London", Status: entity.SymbolDelisted},
+		},
+		{
+			name:        "Known symbol",
+			symbol:      entity.Symbol{Symbol: "UBER"},
+			expectedErr: entity.ErrConflict,
+		},
+		{
+			name:        "Invalid symbol",
+			symbol:      entity.Symbol{Symbol: "BRK B"},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:        "Invalid currency",
+			symbol:      entity.Symbol{Symbol: "VOD.L", Currency: "gbp"},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:        "Unknown timezone",
+			symbol:      entity.Symbol{Symbol: "VOD.L", Timezone: "Europe/Atlantis"},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:        "Unknown status",
+			symbol:      entity.Symbol{Symbol: "VOD.L", Status: "suspended"},
+			expectedErr: entity.ErrBadRequest,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemory()
+			repo.Append(entity.StockQuote{Symbol: "UBER", Price: 48})
+			c := New(repo)
+
+			got, err := c.AddSymbol(context.Background(), tt.symbol)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr), err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+
+			described, err := c.DescribeSymbol(context.Background(), tt.symbol.Symbol)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, described)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"stockpricews/entity"
+)
+
+// TopTradeWindows returns the n most profitable non-overlapping buy/sell windows in a given historical time slice
+// ranked by either absolute profit or percentage return
+func (c MaxProfitController) TopTradeWindows(ctx context.Context, req entity.StockQuoteRequest, n int, rankBy entity.RankBy) (entity.TopTradeWindows, error) {
+	if n < 1 {
+		return entity.TopTradeWindows{}, fmt.Errorf("number of windows must be positive: %w", entity.ErrBadRequest)
+	}
+
+	if rankBy != entity.RankByAbsolute && rankBy != entity.RankByPercent {
+		return entity.TopTradeWindows{}, fmt.Errorf("unknown rank criteria %s: %w", rankBy, entity.ErrBadRequest)
+	}
+
+	history, err := c.Repository.StockQuotesPerTimeSlice(ctx, req)
+	if err != nil {
+		return entity.TopTradeWindows{}, err
+	}
+
+	if history, err = priceSeries(history, req.Field); err != nil {
+		return entity.TopTradeWindows{}, err
+	}
+
+	return topTradeWindows(history, n, rankBy)
+}
+
+// topTradeWindows greedily picks the best window of the whole slice and then keeps picking the best window of the
+// segments left on its both sides until n windows are found or no segment can realize a profit anymore
+func topTradeWindows(history []entity.StockQuote, n int, rankBy entity.RankBy) (entity.TopTradeWindows, error) {
+	if len(history) == 0 {
+		return entity.TopTradeWindows{}, fmt.Errorf("no records found for the given period: %w", entity.ErrNotFound)
+	}
+
+	score := func(buy, sell float64) float64 { return sell - buy }
+	if rankBy == entity.RankByPercent {
+		score = func(buy, sell float64) float64 { return percentReturn(buy, sell) }
+	}
+
+	candidates := &windowHeap{}
+	pushBestWindow := func(lo, hi int) {
+		if w, ok := bestWindow(history, lo, hi, score); ok {
+			heap.Push(candidates, w)
+		}
+	}
+	pushBestWindow(0, len(history)-1)
+
+	result := entity.TopTradeWindows{RankBy: rankBy, Windows: []entity.TradeWindow{}}
+	for len(result.Windows) < n && candidates.Len() > 0 {
+		w := heap.Pop(candidates).(window)
+		buy, sell := history[w.buyIdx], history[w.sellIdx]
+		result.Windows = append(result.Windows, entity.TradeWindow{
+			BuyPoint:  entity.TradePoint{Price: buy.Price, Date: buy.Datepoint},
+			SellPoint: entity.TradePoint{Price: sell.Price, Date: sell.Datepoint},
+			Profit:    sell.Price - buy.Price,
+			Return:    percentReturn(buy.Price, sell.Price),
+		})
+
+		pushBestWindow(w.lo, w.buyIdx-1)
+		pushBestWindow(w.sellIdx+1, w.hi)
+	}
+
+	if len(result.Windows) == 0 {
+		return entity.TopTradeWindows{}, fmt.Errorf("it's not possible to realize a profit in the given period: %w", entity.ErrNotFound)
+	}
+
+	return result, nil
+}
+
+// bestWindow finds the best scored buy/sell pair within history[lo:hi+1] in a single pass. For a given sell point the
+// lowest preceding price is the best buy point for both absolute and percentage score.
+func bestWindow(history []entity.StockQuote, lo, hi int, score func(buy, sell float64) float64) (window, bool) {
+	if hi-lo < 1 {
+		return window{}, false
+	}
+
+	best := window{lo: lo, hi: hi}
+	lowIdx := lo
+	for i := lo + 1; i <= hi; i++ {
+		if history[i].Price < history[lowIdx].Price {
+			lowIdx = i
+		} else if history[i].Price > history[lowIdx].Price {
+			if s := score(history[lowIdx].Price, history[i].Price); s > best.score {
+				best.score, best.buyIdx, best.sellIdx = s, lowIdx, i
+			}
+		}
+	}
+
+	return best, best.score > 0
+}
+
+func percentReturn(buy, sell float64) float64 {
+	if buy == 0 {
+		return 0
+	}
+	return (sell - buy) / buy * 100
+}
+
+// window is a candidate buy/sell pair along with the boundaries of the segment it was found in
+type window struct {
+	lo, hi          int
+	buyIdx, sellIdx int
+	score           float64
+}
+
+// windowHeap is a max heap of windows by score, earlier windows first on ties
+type windowHeap []window
+
+func (h windowHeap) Len() int { return len(h) }
+func (h windowHeap) Less(i, j int) bool {
+	if h[i].score == h[j].score {
+		return h[i].buyIdx < h[j].buyIdx
+	}
+	return h[i].score > h[j].score
+}
+func (h windowHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *windowHeap) Push(x interface{}) { *h = append(*h, x.(window)) }
+func (h *windowHeap) Pop() interface{} {
+	old := *h
+	w := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return w
+}
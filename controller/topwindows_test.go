@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"stockpricews/entity"
+	"testing"
+	"time"
+)
+
+func TestTopTradeWindows(t *testing.T) {
+	times, history := quoteFixture(time.Now(), time.Second, 10)
+
+	window := func(buyIdx, sellIdx int, buy, sell float64) entity.TradeWindow {
+		return entity.TradeWindow{
+			BuyPoint:  entity.TradePoint{Price: buy, Date: times[buyIdx]},
+			SellPoint: entity.TradePoint{Price: sell, Date: times[sellIdx]},
+			Profit:    sell - buy,
+			Return:    (sell - buy) / buy * 100,
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		history     []entity.StockQuote
+		n           int
+		rankBy      entity.RankBy
+		expected    entity.TopTradeWindows
+		expectedErr error
+	}{
+		{
+			name:        "No history records found - error not found",
+			history:     []entity.StockQuote{},
+			n:           3,
+			rankBy:      entity.RankByAbsolute,
+			expectedErr: entity.ErrNotFound,
+		},
+		{
+			name:        "Prices in descending order - we can't realize profits",
+			history:     history(4, 3, 2, 1),
+			n:           3,
+			rankBy:      entity.RankByAbsolute,
+			expectedErr: entity.ErrNotFound,
+		},
+		{
+			name:    "Ranked by absolute profit",
+			history: history(10, 14, 2, 5, 20, 30, 8, 9),
+			n:       3,
+			rankBy:  entity.RankByAbsolute,
+			expected: entity.TopTradeWindows{RankBy: entity.RankByAbsolute, Windows: []entity.TradeWindow{
+				window(2, 5, 2, 30), window(0, 1, 10, 14), window(6, 7, 8, 9),
+			}},
+		},
+		{
+			name:    "Ranked by percentage return",
+			history: history(10, 30, 100, 150, 1, 2),
+			n:       2,
+			rankBy:  entity.RankByPercent,
+			expected: entity.TopTradeWindows{RankBy: entity.RankByPercent, Windows: []entity.TradeWindow{
+				window(0, 3, 10, 150), window(4, 5, 1, 2),
+			}},
+		},
+		{
+			name:    "Less windows than requested",
+			history: history(1, 2, 1),
+			n:       5,
+			rankBy:  entity.RankByAbsolute,
+			expected: entity.TopTradeWindows{RankBy: entity.RankByAbsolute, Windows: []entity.TradeWindow{
+				window(0, 1, 1, 2),
+			}},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := topTradeWindows(tt.history, tt.n, tt.rankBy)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, got)
+			}
+		})
+	}
+}
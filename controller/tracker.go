@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"stockpricews/entity"
+	"sync"
+	"time"
+)
+
+// ProfitTracker maintains the best buy/sell pair of a stream of quotes ingested one by one in chronological order.
+// It applies the same single pass as maxProfitForPeriod so every quote is processed in O(1).
+type ProfitTracker struct {
+	count  int
+	last   entity.StockQuote
+	lowest entity.StockQuote
+	margin float64
+	best   entity.MaxProfitPoints
+}
+
+// NewProfitTracker initializes an empty ProfitTracker
+func NewProfitTracker() *ProfitTracker {
+	return &ProfitTracker{}
+}
+
+// Add ingests the next quote and reports whether the best pair changed. Quotes that are not newer than the last
+// ingested one are ignored so the same quote delivered twice doesn't affect the result.
+func (t *ProfitTracker) Add(quote entity.StockQuote) bool {
+	if t.count > 0 && !quote.Datepoint.After(t.last.Datepoint) {
+		return false
+	}
+	t.count++
+	t.last = quote
+
+	if t.count == 1 {
+		t.lowest = quote
+		return false
+	}
+
+	margin := quote.Price - t.lowest.Price
+	if margin < 0 {
+		// new lowest price - it may open a better trade later on
+		t.lowest = quote
+	} else if margin > t.margin {
+		// new max margin - update the best pair
+		t.margin = margin
+		t.best = newMaxProfitPoints(t.lowest, quote, entity.DirectionLong)
+		return true
+	}
+
+	return false
+}
+
+// Best returns the best pair of the quotes ingested so far
+func (t *ProfitTracker) Best() (entity.MaxProfitPoints, error) {
+	if t.count == 0 {
+		return entity.MaxProfitPoints{}, fmt.Errorf("no records found for the given period: %w", entity.ErrNotFound)
+	}
+
+	if t.margin == 0 {
+		return entity.MaxProfitPoints{}, fmt.Errorf("it's not possible to realize a profit in the given period: %w", entity.ErrNotFound)
+	}
+
+	return t.best, nil
+}
+
+// MaxProfitUpdates subscribes to the new quotes of the requested symbol and streams the running best pair every time
+// it changes. The tracker is seeded with the quotes stored after req.Begin so the current best pair is sent right away
+// if there is one. The context bounds the loading of the stored quotes only. The returned func must be called to
+// release the subscription, the channel is closed afterwards or as soon as the subscription gets dropped for being too
+// slow.
+func (c MaxProfitController) MaxProfitUpdates(ctx context.Context, req entity.StockQuoteRequest) (<-chan entity.MaxProfitPoints, func(), error) {
+	if c.Feed == nil {
+		return nil, nil, fmt.Errorf("streaming of quotes is not enabled: %w", entity.ErrNotFound)
+	}
+
+	// subscribe before loading the history so no quote falls in between, the tracker skips the ones it has seen
+	quotes, unsubscribe := c.Feed.Subscribe(req.Symbol)
+	history, err := c.Repository.StockQuotesPerTimeSlice(ctx, entity.StockQuoteRequest{Symbol: req.Symbol, Begin: req.Begin, End: time.Now()})
+	if err != nil {
+		unsubscribe()
+		return nil, nil, err
+	}
+
+	tracker := NewProfitTracker()
+	for _, quote := range history {
+		tracker.Add(quote)
+	}
+
+	updates := make(chan entity.MaxProfitPoints, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(updates)
+		if best, err := tracker.Best(); err == nil {
+			updates <- best
+		}
+
+		for quote := range quotes {
+			if !tracker.Add(quote) {
+				continue
+			}
+			best, _ := tracker.Best()
+			select {
+			case updates <- best:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return updates, func() {
+		once.Do(func() {
+			close(done)
+			unsubscribe()
+		})
+	}, nil
+}
@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"stockpricews/entity"
+	"stockpricews/repository"
+	"testing"
+	"time"
+)
+
+func TestProfitTracker_MatchesSinglePass(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	initialTime := time.Unix(1699228800, 0)
+
+	tracker := NewProfitTracker()
+	_, err := tracker.Best()
+	assert.True(t, errors.Is(err, entity.ErrNotFound))
+
+	var history []entity.StockQuote
+	for i := 0; i < 200; i++ {
+		quote := entity.StockQuote{Datepoint: initialTime.Add(time.Minute * time.Duration(i)), Price: float64(rnd.Intn(30))}
+		history = append(history, quote)
+
+		previous, previousErr := tracker.Best()
+		changed := tracker.Add(quote)
+		got, err := tracker.Best()
+
+		expected, expectedErr := maxProfitForPeriod(history)
+		if expectedErr != nil {
+			assert.True(t, errors.Is(err, entity.ErrNotFound))
+		} else {
+			assert.NoError(t, err)
+			assert.Equal(t, expected, got)
+		}
+		assert.Equal(t, changed, previousErr == nil && err == nil && previous != got || previousErr != nil && err == nil)
+	}
+}
+
+func TestProfitTracker_SkipsOldQuotes(t *testing.T) {
+	initialTime := time.Unix(1699228800, 0)
+	tracker := NewProfitTracker()
+
+	assert.False(t, tracker.Add(entity.StockQuote{Datepoint: initialTime, Price: 1}))
+	assert.True(t, tracker.Add(entity.StockQuote{Datepoint: initialTime.Add(time.Minute), Price: 2}))
+	// the same quote delivered twice and an older one are ignored
+	assert.False(t, tracker.Add(entity.StockQuote{Datepoint: initialTime.Add(time.Minute), Price: 5}))
+	assert.False(t, tracker.Add(entity.StockQuote{Datepoint: initialTime.Add(-time.Minute), Price: 0}))
+
+	got, err := tracker.Best()
+	assert.NoError(t, err)
+	assert.Equal(t, entity.TradePoint{Price: 1, Date: initialTime}, got.BuyPoint)
+	assert.Equal(t, entity.TradePoint{Price: 2, Date: initialTime.Add(time.Minute)}, got.SellPoint)
+	assert.Equal(t, 1.0, got.Profit)
+}
+
+func TestQuoteFeed(t *testing.T) {
+	feed := NewQuoteFeed()
+	uber, unsubscribeUber := feed.Subscribe("UBER")
+	tsla, unsubscribeTsla := feed.Subscribe("TSLA")
+	defer unsubscribeTsla()
+
+	now := time.Now()
+	feed.Publish(entity.StockQuote{Symbol: "UBER", Datepoint: now, Price: 1})
+	// every stored quote is delivered, the corrected and the older ones as well
+	feed.Publish(entity.StockQuote{Symbol: "UBER", Datepoint: now, Price: 2})
+	feed.Publish(entity.StockQuote{Symbol: "UBER", Datepoint: now.Add(-time.Hour), Price: 3})
+
+	assert.Equal(t, entity.StockQuote{Symbol: "UBER", Datepoint: now, Price: 1}, <-uber)
+	assert.Equal(t, entity.StockQuote{Symbol: "UBER", Datepoint: now, Price: 2}, <-uber)
+	assert.Equal(t, entity.StockQuote{Symbol: "UBER", Datepoint: now.Add(-time.Hour), Price: 3}, <-uber)
+	assert.Len(t, uber, 0)
+	assert.Len(t, tsla, 0)
+
+	unsubscribeUber()
+	unsubscribeUber()
+	_, ok := <-uber
+	assert.False(t, ok)
+
+	// slow subscribers are dropped instead of blocking the publisher
+	for i := 1; i <= subscriptionBuffer+1; i++ {
+		feed.Publish(entity.StockQuote{Symbol: "TSLA", Datepoint: now.Add(time.Second * time.Duration(i))})
+	}
+	for i := 0; i < subscriptionBuffer; i++ {
+		<-tsla
+	}
+	_, ok = <-tsla
+	assert.False(t, ok)
+}
+
+func TestQuoteFeed_PollRepository(t *testing.T) {
+	feed := NewQuoteFeed()
+	repo := repository.NewMemory()
+	quotes, unsubscribe := feed.Subscribe("UBER")
+	defer unsubscribe()
+
+	// a quote stored and published by this process is polled as well, it must not be delivered twice
+	time.Sleep(time.Millisecond)
+	stored := entity.StockQuote{Symbol: "UBER", Datepoint: time.Now(), Price: 10}
+	assert.NoError(t, repo.SaveStockQuotes(context.Background(), []entity.StockQuote{stored}))
+	feed.Publish(stored)
+	// the quotes stored by other processes are polled from the repository
+	time.Sleep(time.Millisecond)
+	assert.NoError(t, repo.SaveStockQuotes(context.Background(), []entity.StockQuote{{Symbol: "UBER", Datepoint: time.Now(), Price: 11}}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go feed.PollRepository(ctx, repo, 5*time.Millisecond)
+
+	assert.Equal(t, 10.0, (<-quotes).Price)
+	assert.Equal(t, 11.0, (<-quotes).Price)
+	select {
+	case quote := <-quotes:
+		assert.Fail(t, "quote delivered twice", "%v", quote)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMaxProfitUpdates(t *testing.T) {
+	initialTime := time.Now().Add(-time.Hour)
+	repo := repository.NewMemory(
+		entity.StockQuote{Symbol: "UBER", Datepoint: initialTime, Price: 2},
+		entity.StockQuote{Symbol: "UBER", Datepoint: initialTime.Add(time.Minute), Price: 3},
+	)
+	c := MaxProfitController{Repository: repo, Feed: NewQuoteFeed()}
+
+	updates, cancel, err := c.MaxProfitUpdates(context.Background(), entity.StockQuoteRequest{Symbol: "UBER", Begin: initialTime.Add(-time.Minute)})
+	assert.NoError(t, err)
+
+	// the best pair of the stored history comes first
+	assert.Equal(t, 3.0, (<-updates).SellPoint.Price)
+
+	// quotes that don't change the best pair are not reported
+	low := entity.StockQuote{Symbol: "UBER", Datepoint: time.Now().Add(time.Minute), Price: 1}
+	high := entity.StockQuote{Symbol: "UBER", Datepoint: time.Now().Add(time.Minute * 2), Price: 5}
+	c.Feed.Publish(low)
+	c.Feed.Publish(high)
+	assert.Equal(t, newMaxProfitPoints(low, high, entity.DirectionLong), <-updates)
+
+	cancel()
+	for range updates {
+	}
+
+	_, _, err = MaxProfitController{Repository: repo}.MaxProfitUpdates(context.Background(), entity.StockQuoteRequest{Symbol: "UBER"})
+	assert.True(t, errors.Is(err, entity.ErrNotFound))
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"stockpricews/entity"
+)
+
+// MaxProfitForTransactions calculates the maximum profit that could be realized in a given historical time slice
+// with at most k non-overlapping buy/sell round trips
+func (c MaxProfitController) MaxProfitForTransactions(ctx context.Context, req entity.StockQuoteRequest, k int) (entity.MultiTradeProfit, error) {
+	if k < 1 {
+		return entity.MultiTradeProfit{}, fmt.Errorf("number of transactions must be positive: %w", entity.ErrBadRequest)
+	}
+
+	history, err := c.Repository.StockQuotesPerTimeSlice(ctx, req)
+	if err != nil {
+		return entity.MultiTradeProfit{}, err
+	}
+
+	if history, err = priceSeries(history, req.Field); err != nil {
+		return entity.MultiTradeProfit{}, err
+	}
+
+	return maxProfitForTransactions(history, k)
+}
+
+func maxProfitForTransactions(history []entity.StockQuote, k int) (entity.MultiTradeProfit, error) {
+	if len(history) == 0 {
+		return entity.MultiTradeProfit{}, fmt.Errorf("no records found for the given period: %w", entity.ErrNotFound)
+	}
+
+	var pairs [][2]int
+	if 2*k >= len(history) {
+		// k is big enough to take every single price rise, no need to run the DP
+		pairs = allRisingRuns(history)
+	} else {
+		pairs = bestTransactions(history, k)
+	}
+
+	if len(pairs) == 0 {
+		return entity.MultiTradeProfit{}, fmt.Errorf("it's not possible to realize a profit in the given period: %w", entity.ErrNotFound)
+	}
+
+	result := entity.MultiTradeProfit{Trades: make([]entity.Trade, 0, len(pairs))}
+	for _, p := range pairs {
+		buy, sell := history[p[0]], history[p[1]]
+		trade := entity.Trade{
+			BuyPoint:  entity.TradePoint{Price: buy.Price, Date: buy.Datepoint},
+			SellPoint: entity.TradePoint{Price: sell.Price, Date: sell.Datepoint},
+			Profit:    sell.Price - buy.Price,
+			NetProfit: sell.Price - buy.Price,
+		}
+		result.Trades = append(result.Trades, trade)
+		result.TotalProfit += trade.Profit
+		result.TotalNetProfit += trade.NetProfit
+	}
+
+	return result, nil
+}
+
+// allRisingRuns returns the (buy, sell) indexes of every consecutive price rise, i.e. the unlimited transactions optimum
+func allRisingRuns(history []entity.StockQuote) [][2]int {
+	var pairs [][2]int
+	for i := 0; i < len(history)-1; {
+		// find the next local minimum
+		for i < len(history)-1 && history[i+1].Price <= history[i].Price {
+			i++
+		}
+		low := i
+		// and the local maximum that follows it
+		for i < len(history)-1 && history[i+1].Price > history[i].Price {
+			i++
+		}
+		if i > low {
+			pairs = append(pairs, [2]int{low, i})
+		}
+	}
+
+	return pairs
+}
+
+// bestTransactions returns the (buy, sell) indexes of at most k non-overlapping trades with the highest total profit.
+// profit[t][i] holds the max profit using at most t transactions up to the i-th quote, while buyIdx[t][i] records the
+// buy index of the trade closed at i (or -1 if no trade is closed there) so the schedule can be reconstructed.
+func bestTransactions(history []entity.StockQuote, k int) [][2]int {
+	n := len(history)
+	profit := make([][]float64, k+1)
+	buyIdx := make([][]int, k+1)
+	profit[0] = make([]float64, n)
+	for t := 1; t <= k; t++ {
+		profit[t] = make([]float64, n)
+		buyIdx[t] = make([]int, n)
+		buyIdx[t][0] = -1
+
+		// best value of profit[t-1][j-1] - price[j] seen so far, i.e. the best moment to open the t-th trade
+		bestBuy := -history[0].Price
+		bestBuyIdx := 0
+		for i := 1; i < n; i++ {
+			profit[t][i] = profit[t][i-1]
+			buyIdx[t][i] = -1
+			if margin := history[i].Price + bestBuy; margin > profit[t][i] {
+				profit[t][i] = margin
+				buyIdx[t][i] = bestBuyIdx
+			}
+
+			if candidate := profit[t-1][i-1] - history[i].Price; candidate > bestBuy {
+				bestBuy = candidate
+				bestBuyIdx = i
+			}
+		}
+	}
+
+	// walk back from the last quote and collect the trades in reverse order
+	var pairs [][2]int
+	for t, i := k, n-1; t > 0 && i > 0; {
+		if buyIdx[t][i] == -1 {
+			i--
+			continue
+		}
+		pairs = append(pairs, [2]int{buyIdx[t][i], i})
+		i = buyIdx[t][i] - 1
+		t--
+	}
+	for l, r := 0, len(pairs)-1; l < r; l, r = l+1, r-1 {
+		pairs[l], pairs[r] = pairs[r], pairs[l]
+	}
+
+	return pairs
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"stockpricews/entity"
+	"testing"
+	"time"
+)
+
+func TestMaxProfitForTransactions(t *testing.T) {
+	times, history := quoteFixture(time.Now(), time.Second, 10)
+
+	trade := func(buyIdx, sellIdx int, buy, sell float64) entity.Trade {
+		return entity.Trade{
+			BuyPoint:  entity.TradePoint{Price: buy, Date: times[buyIdx]},
+			SellPoint: entity.TradePoint{Price: sell, Date: times[sellIdx]},
+			Profit:    sell - buy,
+			NetProfit: sell - buy,
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		history     []entity.StockQuote
+		k           int
+		expected    entity.MultiTradeProfit
+		expectedErr error
+	}{
+		{
+			name:        "No history records found - error not found",
+			history:     []entity.StockQuote{},
+			k:           2,
+			expectedErr: entity.ErrNotFound,
+		},
+		{
+			name:        "Prices in descending order - we can't realize profits",
+			history:     history(9, 7, 5, 3, 2, 1),
+			k:           2,
+			expectedErr: entity.ErrNotFound,
+		},
+		{
+			name:     "Single transaction takes the widest swing",
+			history:  history(1, 5, 2, 8),
+			k:        1,
+			expected: entity.MultiTradeProfit{Trades: []entity.Trade{trade(0, 3, 1, 8)}, TotalProfit: 7, TotalNetProfit: 7},
+		},
+		{
+			name:     "Two transactions split the swings",
+			history:  history(1, 5, 2, 8),
+			k:        2,
+			expected: entity.MultiTradeProfit{Trades: []entity.Trade{trade(0, 1, 1, 5), trade(2, 3, 2, 8)}, TotalProfit: 10, TotalNetProfit: 10},
+		},
+		{
+			name:    "Two transactions out of three swings - take the best two",
+			history: history(3, 5, 1, 9, 4, 6, 2, 10),
+			k:       2,
+			expected: entity.MultiTradeProfit{
+				Trades:         []entity.Trade{trade(2, 3, 1, 9), trade(6, 7, 2, 10)},
+				TotalProfit:    16,
+				TotalNetProfit: 16,
+			},
+		},
+		{
+			name:    "Three transactions out of four swings - merge the cheapest dip",
+			history: history(1, 4, 3, 8, 2, 7, 5, 9),
+			k:       3,
+			expected: entity.MultiTradeProfit{
+				Trades:         []entity.Trade{trade(0, 3, 1, 8), trade(4, 5, 2, 7), trade(6, 7, 5, 9)},
+				TotalProfit:    16,
+				TotalNetProfit: 16,
+			},
+		},
+		{
+			name:    "K exceeds the number of swings - take every rise",
+			history: history(1, 2, 3, 1, 4, 4, 2, 5),
+			k:       10,
+			expected: entity.MultiTradeProfit{
+				Trades:         []entity.Trade{trade(0, 2, 1, 3), trade(3, 4, 1, 4), trade(6, 7, 2, 5)},
+				TotalProfit:    8,
+				TotalNetProfit: 8,
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := maxProfitForTransactions(tt.history, tt.k)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, got)
+			}
+		})
+	}
+}
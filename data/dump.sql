@@ -5,9 +5,13 @@ CREATE TABLE `stock_quote` (
    `id` int NOT NULL AUTO_INCREMENT,
    `symbol` varchar(4) NOT NULL,
    `price` double DEFAULT NULL,
+   `open` double DEFAULT NULL,
+   `high` double DEFAULT NULL,
+   `low` double DEFAULT NULL,
+   `volume` bigint DEFAULT NULL,
    `datepoint` timestamp NULL DEFAULT NULL,
    PRIMARY KEY (`id`),
-   KEY `symbol` (`symbol`,`datepoint`)
+   UNIQUE KEY `symbol` (`symbol`,`datepoint`)
 ) ENGINE=InnoDB AUTO_INCREMENT=529 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
@@ -522,4 +526,16 @@ INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-09',177
 INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-08',191.3);
 INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-07',197.08);
 /*!40000 ALTER TABLE `stock_quote` ENABLE KEYS */;
-UNLOCK TABLES;
\ No newline at end of file
+UNLOCK TABLES;
+
+DROP TABLE IF EXISTS `corporate_action`;
+CREATE TABLE `corporate_action` (
+   `id` int NOT NULL AUTO_INCREMENT,
+   `symbol` varchar(4) NOT NULL,
+   `type` enum('split','reverse_split','dividend') NOT NULL,
+   `ex_date` timestamp NOT NULL,
+   `ratio` double NOT NULL DEFAULT 1,
+   `amount` double NOT NULL DEFAULT 0,
+   PRIMARY KEY (`id`),
+   KEY `symbol` (`symbol`,`ex_date`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,7 @@
+-- Adds the OHLCV columns to a stock_quote table created before they were introduced.
+-- The existing price column keeps holding the close price.
+ALTER TABLE `stock_quote`
+  ADD COLUMN `open` double DEFAULT NULL AFTER `price`,
+  ADD COLUMN `high` double DEFAULT NULL AFTER `open`,
+  ADD COLUMN `low` double DEFAULT NULL AFTER `high`,
+  ADD COLUMN `volume` bigint DEFAULT NULL AFTER `low`;
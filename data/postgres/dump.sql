@@ -0,0 +1,509 @@
+-- Sample quotes for the PostgreSQL repository, create the schema first with: go run . migrate up -db.driver=postgres
+BEGIN;
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-11-07', 49.92);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-11-06', 48.14);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-11-03', 47.75);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-11-02', 46.48);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-11-01', 43.83);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-31', 43.28);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-30', 42.73);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-27', 41.23);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-26', 40.62);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-25', 42.35);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-24', 44.19);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-23', 43.04);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-20', 42.96);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-19', 42.72);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-18', 43);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-17', 44.38);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-16', 44.71);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-13', 43.48);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-12', 45.95);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-11', 46.64);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-10', 46.63);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-09', 45.45);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-06', 45.78);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-05', 44.61);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-04', 44.94);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-03', 44.51);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-10-02', 45.68);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-29', 45.99);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-28', 46.14);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-27', 45.14);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-26', 44.27);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-25', 44.91);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-22', 44.41);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-21', 44.6);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-20', 46.55);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-19', 47.59);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-18', 46.51);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-15', 47.52);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-14', 48.32);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-13', 48.16);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-12', 47.92);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-11', 48.94);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-08', 47.24);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-07', 46.27);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-06', 45.9);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-05', 46.55);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-09-01', 47.04);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-31', 47.23);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-30', 46.51);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-29', 45.35);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-28', 44.15);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-25', 43.96);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-24', 44.68);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-23', 45.14);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-22', 44.35);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-21', 44.63);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-18', 44.69);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-17', 43.97);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-16', 43.65);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-15', 44.08);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-14', 44.85);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-11', 43.71);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-10', 44.6);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-09', 44.11);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-08', 45.16);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-07', 44.95);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-04', 45.2);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-03', 45.91);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-02', 46.96);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-08-01', 46.65);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-31', 49.46);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-28', 48.14);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-27', 46.61);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-26', 47.31);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-25', 47.17);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-24', 47.32);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-21', 47.23);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-20', 46.57);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-19', 47.12);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-18', 47.41);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-17', 45.51);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-14', 44.75);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-13', 45.64);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-12', 44.52);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-11', 44.36);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-10', 42.78);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-07', 42.91);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-06', 42.11);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-05', 43.66);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-07-03', 43.09);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-30', 43.17);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-29', 42.58);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-28', 44.24);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-27', 43.83);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-26', 44.42);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-23', 43.34);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-22', 42.81);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-21', 42.66);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-20', 42.17);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-16', 43.52);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-15', 43.36);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-14', 41.27);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-13', 41.41);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-12', 41.74);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-09', 40.99);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-08', 40.26);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-07', 38.99);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-06', 40.25);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-05', 40.42);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-02', 39.73);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-06-01', 38.48);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-31', 37.93);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-30', 37.56);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-26', 38.45);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-25', 37.95);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-24', 37.96);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-23', 38.66);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-22', 39.17);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-19', 39.18);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-18', 39.25);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-17', 37.84);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-16', 37.44);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-15', 38.14);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-12', 38.45);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-11', 38.42);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-10', 38.79);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-09', 38.19);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-08', 38.83);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-05', 37.75);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-04', 37.49);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-03', 37.84);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-02', 36.52);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-05-01', 32.74);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-28', 31.05);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-27', 29.7);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-26', 29.68);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-25', 29.59);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-24', 30.68);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-21', 30.83);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-20', 31.5);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-19', 32.04);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-18', 31.73);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-17', 32.08);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-14', 31.48);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-13', 31.44);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-12', 30.59);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-11', 31.12);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-10', 31.74);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-06', 31.18);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-05', 31.12);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-04', 31.39);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-04-03', 31.46);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-31', 31.7);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-30', 31.19);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-29', 30.87);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-28', 30.07);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-27', 30.62);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-24', 30.75);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-23', 31.18);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-22', 31.52);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-21', 32.86);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-20', 31.93);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-17', 31.78);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-16', 32.73);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-15', 31.97);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-14', 32.36);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-13', 30.82);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-10', 31.11);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-09', 32.32);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-08', 34.01);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-07', 34.14);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-06', 33.88);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-03', 34.57);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-02', 33.69);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-03-01', 32.99);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-28', 33.26);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-27', 33.55);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-24', 33.4);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-23', 34.47);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-22', 34.54);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-21', 34.2);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-17', 34.77);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-16', 36.22);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-15', 36.23);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-14', 35.23);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-13', 33.44);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-10', 34.3);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-09', 35.89);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-08', 36.83);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-07', 34.9);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-06', 33.9);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-03', 33.09);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-02', 33.05);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-02-01', 31.49);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-31', 30.93);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-30', 29.63);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-27', 30.36);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-26', 30.02);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-25', 30.29);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-24', 29.93);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-23', 30.53);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-20', 30.36);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-19', 29.03);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-18', 28.96);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-17', 29.2);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-13', 29.44);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-12', 29.03);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-11', 28.35);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-10', 28.04);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-09', 27.4);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-06', 26.4);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-05', 25.55);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-04', 25.91);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-01-03', 25.36);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-30', 24.73);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-29', 24.91);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-28', 24.59);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-27', 24.4);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-23', 24.64);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-22', 24.64);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-21', 25.36);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-20', 24.96);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-19', 24.95);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-16', 25.97);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-15', 26.24);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-14', 27.47);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-13', 26.98);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-12', 27.03);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-09', 26.55);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-08', 26.45);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-07', 26.4);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-06', 26.92);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-05', 27.7);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-02', 28.75);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-12-01', 28.34);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-30', 29.14);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-29', 27.76);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-28', 27.76);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-25', 28.5);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-23', 28.79);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-22', 28.08);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-21', 28.25);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-18', 28.96);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-17', 28.88);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-16', 30.04);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-15', 31.57);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-14', 29.07);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-11', 29.15);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-10', 28.85);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-09', 26.55);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-08', 27.44);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2022-11-07', 27.69);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-11-07',222.18);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-11-06',219.27);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-11-03',219.96);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-11-02',218.51);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-11-01',205.66);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-31',200.84);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-30',197.36);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-27',207.3);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-26',205.76);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-25',212.42);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-24',216.52);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-23',212.08);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-20',211.99);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-19',220.11);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-18',242.68);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-17',254.85);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-16',253.92);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-13',251.12);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-12',258.87);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-11',262.99);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-10',263.62);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-09',259.67);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-06',260.53);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-05',260.05);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-04',261.16);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-03',246.53);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-10-02',251.6);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-29',250.22);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-28',246.38);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-27',240.5);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-26',244.12);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-25',246.99);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-22',244.88);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-21',255.7);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-20',262.59);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-19',266.5);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-18',265.28);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-15',274.39);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-14',276.04);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-13',271.3);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-12',267.48);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-11',273.58);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-08',248.5);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-07',251.49);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-06',251.92);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-05',256.49);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-09-01',245.01);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-31',258.08);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-30',256.9);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-29',257.18);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-28',238.82);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-25',238.59);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-24',230.04);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-23',236.86);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-22',233.19);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-21',231.28);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-18',215.49);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-17',219.22);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-16',225.6);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-15',232.96);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-14',239.76);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-11',242.65);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-10',245.34);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-09',242.19);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-08',249.7);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-07',251.45);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-04',253.86);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-03',259.32);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-02',254.11);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-08-01',261.07);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-31',267.43);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-28',266.44);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-27',255.71);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-26',264.35);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-25',265.28);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-24',269.06);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-21',260.02);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-20',262.9);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-19',291.26);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-18',293.34);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-17',290.38);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-14',281.38);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-13',277.9);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-12',271.99);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-11',269.79);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-10',269.61);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-07',274.43);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-06',276.54);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-05',282.48);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-07-03',279.82);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-30',261.77);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-29',257.5);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-28',256.24);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-27',250.21);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-26',241.05);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-23',256.6);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-22',264.61);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-21',259.46);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-20',274.45);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-16',260.54);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-15',255.9);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-14',256.79);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-13',258.71);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-12',249.83);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-09',244.4);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-08',234.86);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-07',224.57);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-06',221.31);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-05',217.61);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-02',213.97);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-06-01',207.52);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-31',203.93);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-30',201.16);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-26',193.17);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-25',184.47);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-24',182.9);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-23',185.77);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-22',188.87);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-19',180.14);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-18',176.89);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-17',173.86);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-16',166.52);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-15',166.35);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-12',167.98);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-11',172.08);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-10',168.54);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-09',169.15);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-08',171.79);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-05',170.06);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-04',161.2);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-03',160.61);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-02',160.31);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-05-01',161.83);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-28',164.31);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-27',160.19);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-26',153.75);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-25',160.67);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-24',162.55);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-21',165.08);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-20',162.99);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-19',180.59);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-18',184.31);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-17',187.04);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-14',185);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-13',185.9);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-12',180.54);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-11',186.79);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-10',184.51);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-06',185.06);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-05',185.52);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-04',192.58);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-04-03',194.77);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-31',207.46);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-30',195.28);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-29',193.88);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-28',189.19);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-27',191.81);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-24',190.41);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-23',192.22);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-22',191.15);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-21',197.58);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-20',183.25);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-17',180.13);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-16',184.13);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-15',180.45);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-14',183.26);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-13',174.48);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-10',173.44);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-09',172.92);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-08',182);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-07',187.71);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-06',193.81);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-03',197.79);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-02',190.9);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-03-01',202.77);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-28',205.71);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-27',207.63);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-24',196.88);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-23',202.07);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-22',200.86);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-21',197.37);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-17',208.31);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-16',202.04);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-15',214.24);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-14',209.25);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-13',194.64);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-10',196.89);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-09',207.32);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-08',201.29);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-07',196.81);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-06',194.76);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-03',189.98);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-02',188.27);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-02-01',181.41);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-31',173.22);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-30',166.66);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-27',177.9);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-26',160.27);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-25',144.43);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-24',143.89);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-23',143.75);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-20',133.42);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-19',127.17);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-18',128.78);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-17',131.49);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-13',122.4);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-12',123.56);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-11',123.22);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-10',118.85);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-09',119.77);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-06',113.06);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-05',110.34);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-04',113.64);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2023-01-03',108.1);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-30',123.18);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-29',121.82);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-28',112.71);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-27',109.1);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-23',123.15);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-22',125.35);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-21',137.57);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-20',137.8);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-19',149.87);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-16',150.23);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-15',157.67);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-14',156.8);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-13',160.95);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-12',167.82);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-09',179.05);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-08',173.44);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-07',174.04);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-06',179.82);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-05',182.45);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-02',194.86);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-12-01',194.7);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-30',194.7);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-29',180.83);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-28',182.92);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-25',182.86);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-23',183.2);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-22',169.91);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-21',167.87);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-18',180.19);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-17',183.17);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-16',186.92);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-15',194.42);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-14',190.95);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-11',195.97);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-10',190.72);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-09',177.59);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-08',191.3);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA','2022-11-07',197.08);
+-- the quotes are inserted as they are, so their symbols are registered in the catalog here
+INSERT INTO symbol (symbol) SELECT DISTINCT symbol FROM stock_quote ON CONFLICT (symbol) DO NOTHING;
+COMMIT;
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+type CorporateActionType string
+
+const (
+	// ActionSplit is a forward split, Ratio is the number of new shares per old share (e.g. 2 for 2-for-1 split)
+	ActionSplit CorporateActionType = "split"
+	// ActionReverseSplit is a reverse split, Ratio is the number of old shares per new share (e.g. 10 for 1-for-10)
+	ActionReverseSplit CorporateActionType = "reverse_split"
+	// ActionDividend is a cash dividend, Amount is paid per share
+	ActionDividend CorporateActionType = "dividend"
+)
+
+type CorporateAction struct {
+	ID     int64               `json:"id"`
+	Symbol string              `json:"symbol"`
+	Type   CorporateActionType `json:"type"`
+	// ExDate is the first date point the price reflects the action
+	ExDate time.Time `json:"exDate"`
+	Ratio  float64   `json:"ratio,omitempty"`
+	Amount float64   `json:"amount,omitempty"`
+}
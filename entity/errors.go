@@ -5,6 +5,9 @@ import "errors"
 var ErrBadRequest = errors.New("bad request")
 var ErrNotFound = errors.New("not found")
 var ErrMethodNotAllowed = errors.New("method not allowed")
+var ErrUnauthorized = errors.New("unauthorized")
+var ErrNotAcceptable = errors.New("not acceptable")
+var ErrConflict = errors.New("conflict")
 
 type ErrorMessage struct {
 	Message string `json:"message"`
@@ -6,21 +6,203 @@ type StockQuoteRequest struct {
 	Symbol string
 	Begin  time.Time
 	End    time.Time
+	// Adjusted requests prices back-adjusted for splits and dividends, i.e. total return series
+	Adjusted bool
+	// Field is the price the trades are made at, close if not set
+	Field PriceField
+	// Limit bounds the number of quotes exported from the start of the time slice, all of them if not set
+	Limit int
 }
 
 type StockQuote struct {
 	ID        int64
 	Symbol    string
 	Datepoint time.Time
-	Price     float64
+	// Price is the close price of the period
+	Price  float64
+	Open   float64
+	High   float64
+	Low    float64
+	Volume int64
 }
 
+// PriceField selects the price of the quote trades are made at
+type PriceField string
+
+const (
+	FieldOpen  PriceField = "open"
+	FieldHigh  PriceField = "high"
+	FieldLow   PriceField = "low"
+	FieldClose PriceField = "close"
+	// FieldOptimistic buys at the low and sells at the high of the period, i.e. the upper bound of the profit
+	FieldOptimistic PriceField = "optimistic"
+	// FieldPessimistic buys at the high and sells at the low of the period, i.e. the lower bound of the profit
+	FieldPessimistic PriceField = "pessimistic"
+)
+
+// QuoteFormat is the format of a file or response holding quotes
+type QuoteFormat string
+
+const (
+	// FormatCSV has a header row naming the columns: symbol, datepoint (or date), price (or close), open, high, low
+	// and volume
+	FormatCSV QuoteFormat = "csv"
+	// FormatJSONLines holds a JSON object per line with the same fields as the columns of CSV files
+	FormatJSONLines QuoteFormat = "jsonl"
+	// FormatJSON is a JSON array of objects with the same fields as the columns of CSV files
+	FormatJSON QuoteFormat = "json"
+)
+
 type TradePoint struct {
 	Price float64   `json:"price"`
 	Date  time.Time `json:"date"`
 }
 
+// Direction of a trade - long buys first and sells later, short sells first and buys back later
+type Direction string
+
+const (
+	DirectionLong  Direction = "long"
+	DirectionShort Direction = "short"
+)
+
 type MaxProfitPoints struct {
 	BuyPoint  TradePoint `json:"buyPoint"`
 	SellPoint TradePoint `json:"sellPoint"`
+	Direction Direction  `json:"direction,omitempty"`
+	// Profit is the profit per share
+	Profit float64 `json:"profit"`
+	// Return is the percentage return relative to the price the position is opened at
+	Return float64 `json:"return"`
+	// AnnualizedReturn is the compound annual growth rate in percents, omitted for positions held less than a day
+	AnnualizedReturn *float64 `json:"annualizedReturn,omitempty"`
+	// HoldingDays is the time between opening and closing the position in days
+	HoldingDays float64         `json:"holdingDays"`
+	Position    *PositionProfit `json:"position,omitempty"`
+}
+
+// Position is the size of the position requested by the client either as number of shares or as invested capital
+type Position struct {
+	Shares  float64
+	Capital float64
+}
+
+// PositionProfit is the profit realized by the max profit trade for a given position size
+type PositionProfit struct {
+	Shares float64 `json:"shares"`
+	// Capital is the amount needed to open the position
+	Capital float64 `json:"capital"`
+	Profit  float64 `json:"profit"`
+}
+
+type Trade struct {
+	BuyPoint  TradePoint `json:"buyPoint"`
+	SellPoint TradePoint `json:"sellPoint"`
+	Profit    float64    `json:"profit"`
+	Fee       float64    `json:"fee,omitempty"`
+	NetProfit float64    `json:"netProfit"`
+}
+
+type MultiTradeProfit struct {
+	Trades         []Trade `json:"trades"`
+	TotalProfit    float64 `json:"totalProfit"`
+	TotalNetProfit float64 `json:"totalNetProfit"`
+}
+
+// TradeCosts holds the constraints applied to every buy/sell round trip
+type TradeCosts struct {
+	// Fee is either an absolute amount charged per round trip or a percentage of the traded value of each side
+	Fee        float64
+	FeePercent bool
+	// Cooldown is the minimum time that must pass between a sell and the next buy
+	Cooldown time.Duration
+}
+
+// RankBy is the criteria trade windows are ranked by - absolute profit or percentage return
+type RankBy string
+
+const (
+	RankByAbsolute RankBy = "absolute"
+	RankByPercent  RankBy = "percent"
+)
+
+type TradeWindow struct {
+	BuyPoint  TradePoint `json:"buyPoint"`
+	SellPoint TradePoint `json:"sellPoint"`
+	Profit    float64    `json:"profit"`
+	// Return is the percentage return of the window
+	Return float64 `json:"return"`
+}
+
+type TopTradeWindows struct {
+	RankBy  RankBy        `json:"rankBy"`
+	Windows []TradeWindow `json:"windows"`
+}
+
+// SymbolPerformance is an entry of the Leaderboard. Symbols whose max profit can't be computed carry the error
+// instead of the result and are not ranked.
+type SymbolPerformance struct {
+	Rank      int              `json:"rank,omitempty"`
+	Symbol    string           `json:"symbol"`
+	MaxProfit *MaxProfitPoints `json:"maxProfit,omitempty"`
+	Profit    float64          `json:"profit"`
+	// Return is the percentage return of the max profit trade
+	Return float64 `json:"return"`
+	Err    error   `json:"-"`
+	Error  string  `json:"error,omitempty"`
+}
+
+type Leaderboard struct {
+	Entries []SymbolPerformance `json:"entries"`
+}
+
+// RejectedQuote is a quote of a stored batch that failed to be parsed or validated, Row is its 1-based position in
+// the batch
+type RejectedQuote struct {
+	Row   int    `json:"row"`
+	Err   error  `json:"-"`
+	Error string `json:"error,omitempty"`
+}
+
+// QuoteIngestion reports the outcome of storing a batch of quotes
+type QuoteIngestion struct {
+	Stored   int             `json:"stored"`
+	Rejected []RejectedQuote `json:"rejected"`
+}
+
+// ImportProgress reports the progress of a bulk import. Records counts every record read from the file, the valid,
+// the rejected and the duplicate ones, up to the last stored batch.
+type ImportProgress struct {
+	Records    int64 `json:"records"`
+	Stored     int64 `json:"stored"`
+	Rejected   int64 `json:"rejected"`
+	Duplicates int64 `json:"duplicates"`
+	Batches    int64 `json:"batches"`
+}
+
+// SymbolStatus tells whether the symbol is still traded
+type SymbolStatus string
+
+const (
+	SymbolListed   SymbolStatus = "listed"
+	SymbolDelisted SymbolStatus = "delisted"
+)
+
+// Symbol describes a stock of the symbol catalog. Symbols quoted before they were described are registered with the
+// defaults only: listed in UTC.
+type Symbol struct {
+	Symbol   string       `json:"symbol"`
+	Name     string       `json:"name"`
+	Exchange string       `json:"exchange"`
+	Currency string       `json:"currency"`
+	Timezone string       `json:"timezone"`
+	Status   SymbolStatus `json:"status"`
+	// FirstDatapoint and LastDatapoint bound the stored quotes of the symbol, they're omitted if there's none yet
+	FirstDatapoint *time.Time `json:"firstDatapoint,omitempty"`
+	LastDatapoint  *time.Time `json:"lastDatapoint,omitempty"`
+}
+
+// SymbolCatalog lists the known symbols
+type SymbolCatalog struct {
+	Symbols []Symbol `json:"symbols"`
 }
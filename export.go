@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"stockpricews/controller"
+	"stockpricews/entity"
+	"stockpricews/handler"
+	"stockpricews/repository"
+)
+
+// exportQuotes runs the export subcommand: export -symbol=<symbol> -begin=<time> -end=<time> [-tz=<zone>] [-format=csv|jsonl|json] [-o=<file>] [-db.* params]
+func exportQuotes(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	db := registerDBFlags(flags)
+	symbol := flags.String("symbol", "", "symbol of the exported quotes")
+	begin := flags.String("begin", "", "quotes after the time point are exported: unix seconds, RFC 3339, a date, now, today, ytd or relative like -30d")
+	end := flags.String("end", "", "quotes before the time point are exported, in the same formats as begin")
+	tz := flags.String("tz", "", "IANA time zone the dates without an offset are read in, UTC by default")
+	format := flags.String("format", "", "format of the export: csv, jsonl or json, by default it's taken from the output file extension, csv for stdout")
+	output := flags.String("o", "", "file the quotes are written to, stdout by default")
+	flags.Parse(args)
+
+	// the params are validated the same way as the ones of GET /quotes
+	params := url.Values{}
+	params.Set("symbol", *symbol)
+	params.Set("begin", *begin)
+	params.Set("end", *end)
+	if *tz != "" {
+		params.Set("tz", *tz)
+	}
+	timeSlice, err := handler.ParseQuoteRequest(params)
+	if err != nil {
+		return fmt.Errorf("%w\nusage: export -symbol=<symbol> -begin=<time> -end=<time> [-tz=<zone>] [-format=csv|jsonl|json] [-o=<file>] [-db.driver=<driver> ...]", err)
+	}
+
+	if *format == "" {
+		*format = string(entity.FormatCSV)
+		if *output != "" {
+			*format = quoteFormat(*output)
+		}
+	}
+
+	r, err := newExportRepository(db)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	buffered := bufio.NewWriter(out)
+
+	writer, err := handler.NewQuoteWriter(buffered, entity.QuoteFormat(*format))
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	count := 0
+	if err := controller.New(r).ExportStockQuotes(ctx, timeSlice, func(quote entity.StockQuote) error {
+		count++
+		return writer.Write(quote)
+	}); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+
+	// stdout may be piped into another tool, so the summary goes to stderr
+	fmt.Fprintf(os.Stderr, "%d %s quotes exported\n", count, timeSlice.Symbol)
+	return nil
+}
+
+// newExportRepository opens the configured database as it is, the sqlite database isn't migrated nor seeded
+func newExportRepository(db dbConfig) (repository.Repository, error) {
+	if *db.driver == "sqlite" {
+		return repository.OpenSQLite(*db.path)
+	}
+	return newRepository(db)
+}
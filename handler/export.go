@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"stockpricews/entity"
+	"strconv"
+	"strings"
+)
+
+// exportFormat is the query param overriding the Accept header of the export
+const exportFormat = "format"
+
+// exportMediaTypes are the media types the quotes are exported as, the first one is the default
+var exportMediaTypes = []struct {
+	mediaType string
+	format    entity.QuoteFormat
+	// param is the value of the format param selecting the media type
+	param string
+}{
+	{"application/json", entity.FormatJSON, "json"},
+	{"text/csv", entity.FormatCSV, "csv"},
+	{"application/x-ndjson", entity.FormatJSONLines, "ndjson"},
+}
+
+// Quotes is HTTP handler of /quotes, GET exports and POST stores the quotes
+func (h StockPriceHandler) Quotes(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		h.SaveStockQuotes(w, r)
+		return
+	}
+	h.ExportStockQuotes(w, r)
+}
+
+// ExportStockQuotes is HTTP handler that streams the stored quotes of the time slice ordered by date point. The quotes
+// are written as they're read from the database, so exports of any size take constant memory. As the export takes as
+// long as the client reads it, the request timeout doesn't apply. The export is aborted if the client goes away or
+// doesn't take a quote within the export idle timeout, so a stalled client doesn't hold the database connection.
+// Usage: curl GET /quotes?begin=<begin_time>&end=<end_time>&symbol=<STOCK_SYMBOL>[&format=json|csv|ndjson]
+// The format is negotiated by the Accept header: application/json (default), text/csv or application/x-ndjson, the
+// format param overrides it. The fields are symbol, datepoint (RFC3339 in UTC), price (close), open, high, low and volume.
+// Result status codes:
+//   - 200 OK - the quotes of the time slice, none if there's no quote within it
+//   - 400 Bad Request - if any of the query params is not passed or doesn't have a correct format, adjusted quotes can't be exported
+//   - 404 Not Found - if the symbol isn't in the catalog
+//   - 406 Not Acceptable - if none of the accepted media types can be exported
+//   - 500 Internal Server Error - if the quotes can't be read. If the export fails after it started, the connection is
+//     aborted so the client doesn't take the truncated export for a complete one
+func (h StockPriceHandler) ExportStockQuotes(w http.ResponseWriter, r *http.Request) {
+	// the errors are reported as json, whatever the format of the export
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Vary", "Accept")
+
+	timeSlice, err := parseRequestData(r)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	mediaType, format, err := negotiateExport(r)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	writer, err := NewQuoteWriter(w, format)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	if err := h.checkSymbol(r.Context(), timeSlice.Symbol); err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	// the status is sent with the first quote, so a query failing right away is still reported properly
+	started := false
+	start := func() {
+		if !started {
+			started = true
+			w.Header().Set("Content-Type", mediaType)
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+
+	err = h.Controller.ExportStockQuotes(r.Context(), timeSlice, func(quote entity.StockQuote) error {
+		start()
+		return writer.Write(quote)
+	})
+	if err == nil {
+		start()
+		err = writer.Close()
+	}
+	if err != nil {
+		if !started {
+			respondWithError(err, w)
+			return
+		}
+		fmt.Println(err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// negotiateExport picks the media type of the export by the format param or the Accept header, the media ranges of
+// the header are tried in the order of their quality
+func negotiateExport(r *http.Request) (string, entity.QuoteFormat, error) {
+	if r.URL.Query().Has(exportFormat) {
+		param := r.URL.Query().Get(exportFormat)
+		for _, t := range exportMediaTypes {
+			if t.param == param {
+				return t.mediaType, t.format, nil
+			}
+		}
+		return "", "", fmt.Errorf("%s param must be one of json, csv or ndjson: %w", exportFormat, entity.ErrBadRequest)
+	}
+
+	accept := strings.TrimSpace(strings.Join(r.Header.Values("Accept"), ","))
+	if accept == "" {
+		return exportMediaTypes[0].mediaType, exportMediaTypes[0].format, nil
+	}
+
+	type mediaRange struct {
+		mediaType string
+		quality   float64
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > 0 {
+			ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].quality > ranges[j].quality })
+
+	for _, accepted := range ranges {
+		for _, t := range exportMediaTypes {
+			if accepted.mediaType == t.mediaType || accepted.mediaType == "*/*" ||
+				accepted.mediaType == t.mediaType[:strings.Index(t.mediaType, "/")]+"/*" ||
+				// the registered ndjson type is accepted as well as the widespread x- one
+				(accepted.mediaType == "application/ndjson" && t.format == entity.FormatJSONLines) {
+				return t.mediaType, t.format, nil
+			}
+		}
+	}
+
+	return "", "", fmt.Errorf("quotes can be exported as application/json, text/csv or application/x-ndjson only: %w", entity.ErrNotAcceptable)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportStockQuotes_StatusCodes(t *testing.T) {
+	testCases := []struct {
+		name                string
+		controller          MockController
+		method              string
+		url                 string
+		accept              string
+		expectedStatusCode  int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "JSON by default",
+			url:                 "quotes?begin=1699228000&end=1699401600&symbol=UBER",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody: "[{\"symbol\":\"UBER\",\"datepoint\":\"2023-11-06T00:00:00Z\",\"price\":48.14,\"open\":47,\"high\":50.5,\"low\":46.8,\"volume\":1000}," +
+				"{\"symbol\":\"UBER\",\"datepoint\":\"2023-11-07T00:00:00Z\",\"price\":49.92,\"open\":47,\"high\":50.5,\"low\":46.8,\"volume\":1000}]\n",
+		},
+		{
+			name:                "CSV by Accept header",
+			url:                 "quotes?begin=1699228000&end=1699401600&symbol=UBER",
+			accept:              "application/xml, text/csv;q=0.9, */*;q=0.1",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/csv",
+			expectedBody: "symbol,datepoint,price,open,high,low,volume\n" +
+				"UBER,2023-11-06T00:00:00Z,48.14,47,50.5,46.8,1000\n" +
+				"UBER,2023-11-07T00:00:00Z,49.92,47,50.5,46.8,1000\n",
+		},
+		{
+			name:                "NDJSON by format param",
+			url:                 "quotes?begin=1699228000&end=1699401600&symbol=UBER&format=ndjson",
+			accept:              "text/csv",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody: "{\"symbol\":\"UBER\",\"datepoint\":\"2023-11-06T00:00:00Z\",\"price\":48.14,\"open\":47,\"high\":50.5,\"low\":46.8,\"volume\":1000}\n" +
+				"{\"symbol\":\"UBER\",\"datepoint\":\"2023-11-07T00:00:00Z\",\"price\":49.92,\"open\":47,\"high\":50.5,\"low\":46.8,\"volume\":1000}\n",
+		},
+		{
+			name:                "Registered ndjson media type",
+			url:                 "quotes?begin=1699228000&end=1699401600&symbol=UBER",
+			accept:              "application/ndjson",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody: "{\"symbol\":\"UBER\",\"datepoint\":\"2023-11-06T00:00:00Z\",\"price\":48.14,\"open\":47,\"high\":50.5,\"low\":46.8,\"volume\":1000}\n" +
+				"{\"symbol\":\"UBER\",\"datepoint\":\"2023-11-07T00:00:00Z\",\"price\":49.92,\"open\":47,\"high\":50.5,\"low\":46.8,\"volume\":1000}\n",
+		},
+		{
+			name:                "Not acceptable media type",
+			url:                 "quotes?begin=1699228000&end=1699401600&symbol=UBER",
+			accept:              "application/xml, text/csv;q=0",
+			expectedStatusCode:  http.StatusNotAcceptable,
+			expectedContentType: "application/json",
+			expectedBody:        "{\"message\":\"quotes can be exported as application/json, text/csv or application/x-ndjson only: not acceptable\"}\n",
+		},
+		{
+			name:                "Unknown format param",
+			url:                 "quotes?begin=1699228000&end=1699401600&symbol=UBER&format=xml",
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        "{\"message\":\"format param must be one of json, csv or ndjson: bad request\"}\n",
+		},
+		{
+			name:                "Params validated as for max profit",
+			url:                 "quotes?begin=1699401600&end=1699228000&symbol=UBER",
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        "{\"message\":\"begin period is after the end period: bad request\"}\n",
+		},
+		{
+			name:                "Unknown symbol",
+			url:                 "quotes?begin=1699228000&end=1699401600&symbol=UNKN",
+			expectedStatusCode:  http.StatusNotFound,
+			expectedContentType: "application/json",
+			expectedBody:        "{\"message\":\"unknown symbol UNKN: not found\"}\n",
+		},
+		{
+			name:                "Non GET request",
+			method:              "PUT",
+			url:                 "quotes?begin=1699228000&end=1699401600&symbol=UBER",
+			expectedStatusCode:  http.StatusMethodNotAllowed,
+			expectedContentType: "application/json",
+			expectedBody:        "{\"message\":\"method PUT not allowed: method not allowed\"}\n",
+		},
+		{
+			name:                "Export failed before the first quote",
+			controller:          MockController{err: errors.New("connection refused")},
+			url:                 "quotes?begin=1699228000&end=1699401600&symbol=UBER",
+			expectedStatusCode:  http.StatusInternalServerError,
+			expectedContentType: "application/json",
+			expectedBody:        "{\"message\":\"Internal server error\"}\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = "GET"
+			}
+			req, err := http.NewRequest(method, tt.url, nil)
+			assert.NoError(t, err)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			rr := httptest.NewRecorder()
+			StockPriceHandler{Controller: tt.controller}.Quotes(rr, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedContentType, rr.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestExportStockQuotes_FailedMidway(t *testing.T) {
+	req, err := http.NewRequest("GET", "quotes?begin=1699228000&end=1699401600&symbol=FAIL&format=csv", nil)
+	assert.NoError(t, err)
+
+	// the status is sent already, the connection is aborted so the export doesn't look complete
+	rr := httptest.NewRecorder()
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		StockPriceHandler{Controller: MockController{}}.ExportStockQuotes(rr, req)
+	})
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
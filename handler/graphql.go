@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"stockpricews/entity"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+const (
+	// maxGraphQLBytes is the upper bound of a GraphQL request body
+	maxGraphQLBytes = 64 << 10
+
+	// maxQueryComplexity bounds the estimated cost of a query, see queryCost
+	maxQueryComplexity = 5000
+
+	// loadCost is the cost of a field loading from the repository, every object resolved costs 1
+	loadCost = 10
+
+	query         = "query"
+	operationName = "operationName"
+	variables     = "variables"
+)
+
+// loadingFields are the object fields resolved by loading from the repository
+var loadingFields = map[string]bool{"symbols": true, "symbol": true, "quotes": true, "maxProfit": true}
+
+// listFields are the fields resolving a list of objects bounded by their first argument
+var listFields = map[string]bool{"symbols": true, "quotes": true}
+
+// graphQLRequest is the body of POST /graphql, GET takes the same fields as query params
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQL is HTTP handler of the GraphQL API, it fetches the symbols, their quotes and max profits in a single round
+// trip. The queries are rejected before they're executed if their estimated cost exceeds maxQueryComplexity, so a
+// single request can't fan out into unbounded repository loads.
+// Usage: curl -X POST /graphql -d '{"query":"{ symbol(symbol: \"UBER\") { name maxProfit(begin: \"2023-10-10T00:00:00Z\", end: \"2023-11-08T00:00:00Z\") { profit } } }"}'
+// or curl GET /graphql?query=<query>[&operationName=<name>][&variables=<json>]
+// Result status codes:
+//   - 200 OK - body contains the GraphQL result as json, the fields that failed are null and their errors are listed
+//     with extensions.code: BAD_REQUEST, NOT_FOUND, TIMEOUT or INTERNAL
+//   - 400 Bad Request - if the request can't be read, the query isn't valid or it's too complex. Body contains the
+//     errors as json
+//   - 405 Method Not Allowed - if the method is neither GET nor POST
+//   - 429 Too Many Requests if the client got rate limited.
+func (h StockPriceHandler) GraphQL(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	req, err := parseGraphQLRequest(w, r)
+	if err != nil {
+		if statusCode, _ := errorStatus(err); statusCode != http.StatusBadRequest {
+			respondWithError(err, w)
+			return
+		}
+		respondWithGraphQLErrors(w, gqlerrors.FormatErrors(err))
+		return
+	}
+
+	document, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		respondWithGraphQLErrors(w, gqlerrors.FormatErrors(err))
+		return
+	}
+
+	if validation := graphql.ValidateDocument(&graphQLSchema, document, nil); !validation.IsValid {
+		respondWithGraphQLErrors(w, validation.Errors)
+		return
+	}
+
+	if err := checkComplexity(document, req); err != nil {
+		fmt.Println(err)
+		respondWithGraphQLErrors(w, gqlerrors.FormatErrors(err))
+		return
+	}
+
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        graphQLSchema,
+		Root:          h,
+		AST:           document,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+// parseGraphQLRequest reads the query from the JSON body of POST requests or the query params of GET requests
+func parseGraphQLRequest(w http.ResponseWriter, r *http.Request) (graphQLRequest, error) {
+	var req graphQLRequest
+	switch r.Method {
+	case http.MethodPost:
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGraphQLBytes)).Decode(&req); err != nil {
+			return graphQLRequest{}, fmt.Errorf("body is not a valid GraphQL request: %w", entity.ErrBadRequest)
+		}
+	case http.MethodGet, http.MethodHead:
+		params := r.URL.Query()
+		req.Query = params.Get(query)
+		req.OperationName = params.Get(operationName)
+		if params.Has(variables) {
+			if err := json.Unmarshal([]byte(params.Get(variables)), &req.Variables); err != nil {
+				return graphQLRequest{}, fmt.Errorf("%s param is not a json object: %w", variables, entity.ErrBadRequest)
+			}
+		}
+	default:
+		return graphQLRequest{}, fmt.Errorf("method %s not allowed: %w", r.Method, entity.ErrMethodNotAllowed)
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		return graphQLRequest{}, fmt.Errorf("%s is missing: %w", query, entity.ErrBadRequest)
+	}
+	return req, nil
+}
+
+// respondWithGraphQLErrors reports the errors of a request that isn't executed in the format of the GraphQL results
+func respondWithGraphQLErrors(w http.ResponseWriter, errs []gqlerrors.FormattedError) {
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(graphql.Result{Errors: errs})
+}
+
+// checkComplexity reports entity.ErrBadRequest if the operation of the request costs more than maxQueryComplexity
+func checkComplexity(document *ast.Document, req graphQLRequest) error {
+	cost := queryCost{fragments: map[string]*ast.FragmentDefinition{}, variables: map[string]interface{}{}}
+	var operations []*ast.OperationDefinition
+	for _, definition := range document.Definitions {
+		switch definition := definition.(type) {
+		case *ast.OperationDefinition:
+			if req.OperationName == "" || (definition.Name != nil && definition.Name.Value == req.OperationName) {
+				operations = append(operations, definition)
+			}
+		case *ast.FragmentDefinition:
+			cost.fragments[definition.Name.Value] = definition
+		}
+	}
+
+	if len(operations) != 1 {
+		return fmt.Errorf("%s must name one of the operations of the query: %w", operationName, entity.ErrBadRequest)
+	}
+
+	// the variables take their default values if they're not passed
+	for _, v := range operations[0].VariableDefinitions {
+		if value, ok := req.Variables[v.Variable.Name.Value]; ok {
+			cost.variables[v.Variable.Name.Value] = value
+		} else if v.DefaultValue != nil {
+			cost.variables[v.Variable.Name.Value] = v.DefaultValue.GetValue()
+		}
+	}
+
+	if complexity := cost.selectionSet(operations[0].SelectionSet); complexity > maxQueryComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d, select fewer symbols or quotes: %w",
+			complexity, maxQueryComplexity, entity.ErrBadRequest)
+	}
+	return nil
+}
+
+// queryCost estimates the cost of a query before it's executed: every object resolved costs 1 and every field loading
+// from the repository loadCost, the cost of the selection of a list field is multiplied by its first argument. The
+// estimate is an upper bound, fragments are counted as if all of them applied and the fields skipped by directives
+// count as well. The introspection fields are free.
+type queryCost struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// selectionSet returns the cost of the selection
+func (c queryCost) selectionSet(set *ast.SelectionSet) int {
+	if set == nil {
+		return 0
+	}
+
+	cost := 0
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if !strings.HasPrefix(selection.Name.Value, "__") {
+				cost += c.field(selection)
+			}
+		case *ast.InlineFragment:
+			cost += c.selectionSet(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			// fragment cycles are rejected by the validation already
+			if fragment, ok := c.fragments[selection.Name.Value]; ok {
+				cost += c.selectionSet(fragment.SelectionSet)
+			}
+		}
+	}
+	return cost
+}
+
+func (c queryCost) field(field *ast.Field) int {
+	// the scalar fields are free, Symbol.symbol is a scalar unlike Query.symbol
+	if field.SelectionSet == nil {
+		return 0
+	}
+
+	cost := 0
+	if loadingFields[field.Name.Value] {
+		cost = loadCost
+	}
+	// objects resolved by the field, a single one unless it's a list
+	objects := 1
+	if first, ok := c.listLength(field); ok {
+		objects = first
+	}
+	return cost + objects*(1+c.selectionSet(field.SelectionSet))
+}
+
+// listLength returns the first argument of the list field, the default length if it's not passed
+func (c queryCost) listLength(field *ast.Field) (int, bool) {
+	if !listFields[field.Name.Value] {
+		return 0, false
+	}
+
+	first := defaultListLength
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != firstArg {
+			continue
+		}
+		var value interface{} = argument.Value.GetValue()
+		if v, ok := argument.Value.(*ast.Variable); ok {
+			value = c.variables[v.Name.Value]
+		}
+		switch value := value.(type) {
+		case string:
+			// int literals keep their text
+			if n, err := strconv.Atoi(value); err == nil {
+				first = n
+			}
+		case float64:
+			first = int(value)
+		}
+	}
+
+	// lengths out of bounds are rejected by the resolver before anything is loaded
+	if first < 1 || first > maxListLength {
+		first = 1
+	}
+	return first, true
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"stockpricews/controller"
+	"stockpricews/entity"
+	"stockpricews/repository"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQL(t *testing.T) {
+	day := time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC)
+	repo := repository.NewMemory(
+		entity.StockQuote{Symbol: "UBER", Datepoint: day, Price: 10, Volume: 3000000000},
+		entity.StockQuote{Symbol: "UBER", Datepoint: day.AddDate(0, 0, 1), Price: 8},
+		entity.StockQuote{Symbol: "UBER", Datepoint: day.AddDate(0, 0, 2), Price: 12},
+		entity.StockQuote{Symbol: "VOD.L", Datepoint: day, Price: 72},
+	)
+	handler := StockPriceHandler{Controller: controller.New(repo)}
+
+	testCases := []struct {
+		name               string
+		method             string
+		url                string
+		body               string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "Max profit",
+			method:             "POST",
+			body:               `{"query":"{ maxProfit(symbol: \"UBER\", begin: \"2023-11-05T00:00:00Z\", end: \"2023-11-09T00:00:00Z\") { buyPoint { price date } sellPoint { price } direction profit return holdingDays } }"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"data":{"maxProfit":{"buyPoint":{"date":"2023-11-07T00:00:00Z","price":8},"direction":"LONG","holdingDays":1,"profit":4,"return":50,"sellPoint":{"price":12}}}}` + "\n",
+		},
+		{
+			name:   "Symbols with quotes and max profit in one round trip",
+			method: "POST",
+			body: `{"query":"query Dashboard($begin: DateTime!, $end: DateTime!) { symbols { symbol status lastDatapoint quotes(begin: $begin, end: $end, first: 2) { price volume } ` +
+				`maxProfit(begin: $begin, end: $end, direction: SHORT) { profit } } }","variables":{"begin":"2023-11-05T00:00:00Z","end":"2023-11-09T00:00:00Z"}}`,
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"data":{"symbols":[{"lastDatapoint":"2023-11-08T00:00:00Z","maxProfit":{"profit":2},"quotes":[{"price":10,"volume":3000000000},{"price":8,"volume":0}],"status":"LISTED","symbol":"UBER"},` +
+				`{"lastDatapoint":"2023-11-06T00:00:00Z","maxProfit":null,"quotes":[{"price":72,"volume":0}],"status":"LISTED","symbol":"VOD.L"}]},` +
+				`"errors":[{"message":"it's not possible to realize a profit in the given period: not found","locations":[{"line":1,"column":153}],"path":["symbols",1,"maxProfit"],"extensions":{"code":"NOT_FOUND"}}]}` + "\n",
+		},
+		{
+			name:               "Query params",
+			method:             "GET",
+			url:                "/graphql?query=" + url.QueryEscape(`query Symbol($symbol: String!) { symbol(symbol: $symbol) { timezone } }`) + "&variables=" + url.QueryEscape(`{"symbol":"VOD.L"}`),
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"data":{"symbol":{"timezone":"UTC"}}}` + "\n",
+		},
+		{
+			name:               "Unknown symbol",
+			method:             "POST",
+			body:               `{"query":"{ symbol(symbol: \"UNKN\") { name } }"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"data":{"symbol":null},"errors":[{"message":"failed to describe symbol: unknown symbol UNKN: not found","locations":[{"line":1,"column":3}],"path":["symbol"],"extensions":{"code":"NOT_FOUND"}}]}` + "\n",
+		},
+		{
+			name:               "Invalid symbol",
+			method:             "POST",
+			body:               `{"query":"{ quotes(symbol: \"UBER X\", begin: \"2023-11-05T00:00:00Z\", end: \"2023-11-09T00:00:00Z\") { price } }"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"data":null,"errors":[{"message":"stock symbol \"UBER X\" may contain letters, digits, '.', '-', '=' and '^' only: bad request","locations":[{"line":1,"column":3}],"path":["quotes"],"extensions":{"code":"BAD_REQUEST"}}]}` + "\n",
+		},
+		{
+			name:               "List too long",
+			method:             "POST",
+			body:               `{"query":"{ symbols(first: 5000) { symbol } }"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"data":null,"errors":[{"message":"first argument must be between 1 and 1000: bad request","locations":[{"line":1,"column":3}],"path":["symbols"],"extensions":{"code":"BAD_REQUEST"}}]}` + "\n",
+		},
+		{
+			name:               "Unknown field",
+			method:             "POST",
+			body:               `{"query":"{ symbols { isin } }"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"data":null,"errors":[{"message":"Cannot query field \"isin\" on type \"Symbol\".","locations":[{"line":1,"column":13}]}]}` + "\n",
+		},
+		{
+			name:               "Too complex",
+			method:             "POST",
+			body:               `{"query":"{ symbols(first: 500) { maxProfit(begin: \"2023-11-05T00:00:00Z\", end: \"2023-11-09T00:00:00Z\") { profit } } }"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"data":null,"errors":[{"message":"query complexity 6010 exceeds the limit of 5000, select fewer symbols or quotes: bad request","locations":[]}]}` + "\n",
+		},
+		{
+			name:               "Missing query",
+			method:             "POST",
+			body:               `{"variables":{}}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"data":null,"errors":[{"message":"query is missing: bad request","locations":[]}]}` + "\n",
+		},
+		{
+			name:               "Syntax error",
+			method:             "GET",
+			url:                "/graphql?query=" + url.QueryEscape("{ symbols {"),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"data":null,"errors":[{"message":"Syntax Error GraphQL request (1:12) Expected Name, found EOF\n\n1: { symbols {\n              ^\n","locations":[{"line":1,"column":12}]}]}` + "\n",
+		},
+		{
+			name:               "Method not allowed",
+			method:             "DELETE",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedBody:       `{"message":"method DELETE not allowed: method not allowed"}` + "\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tt.url
+			if target == "" {
+				target = "/graphql"
+			}
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(handler.GraphQL).ServeHTTP(rr, httptest.NewRequest(tt.method, target, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestCheckComplexity(t *testing.T) {
+	testCases := []struct {
+		name          string
+		query         string
+		operationName string
+		variables     map[string]interface{}
+		expectedError string
+	}{
+		{
+			name:  "Symbol",
+			query: `{ symbol(symbol: "UBER") { name } }`,
+		},
+		{
+			name:  "Max quotes",
+			query: `{ quotes(symbol: "UBER", begin: "2023-11-05T00:00:00Z", end: "2023-11-09T00:00:00Z", first: 1000) { price } }`,
+		},
+		{
+			name:          "Quotes of every symbol",
+			query:         `{ symbols { quotes(begin: "2023-11-05T00:00:00Z", end: "2023-11-09T00:00:00Z") { price } } }`,
+			expectedError: "query complexity 11110 exceeds the limit of 5000, select fewer symbols or quotes: bad request",
+		},
+		{
+			name:          "Length passed as variable",
+			query:         `query Q($n: Int) { symbols(first: $n) { maxProfit(begin: "2023-11-05T00:00:00Z", end: "2023-11-09T00:00:00Z") { profit } } }`,
+			variables:     map[string]interface{}{"n": 500.0},
+			expectedError: "query complexity 6010 exceeds the limit of 5000, select fewer symbols or quotes: bad request",
+		},
+		{
+			name:  "Length of variable default",
+			query: `query Q($n: Int = 20) { symbols(first: $n) { maxProfit(begin: "2023-11-05T00:00:00Z", end: "2023-11-09T00:00:00Z") { profit } } }`,
+		},
+		{
+			name: "Aliased fields in fragments",
+			query: `{ symbols(first: 300) { ...windows } } fragment windows on Symbol { long: maxProfit(begin: "2023-11-05T00:00:00Z", end: "2023-11-09T00:00:00Z") { profit } ` +
+				`short: maxProfit(begin: "2023-11-05T00:00:00Z", end: "2023-11-09T00:00:00Z", direction: SHORT) { profit } }`,
+			expectedError: "query complexity 6910 exceeds the limit of 5000, select fewer symbols or quotes: bad request",
+		},
+		{
+			name:          "Operation by name",
+			query:         `query Cheap { symbol(symbol: "UBER") { name } } query Expensive { symbols(first: 1000) { quotes(begin: "2023-11-05T00:00:00Z", end: "2023-11-09T00:00:00Z") { price } } }`,
+			operationName: "Cheap",
+		},
+		{
+			name:          "Ambiguous operation",
+			query:         `query Cheap { symbol(symbol: "UBER") { name } } query Other { symbol(symbol: "TSLA") { name } }`,
+			expectedError: "operationName must name one of the operations of the query: bad request",
+		},
+		{
+			name:  "Introspection is free",
+			query: `{ __schema { types { name fields { name type { name ofType { name ofType { name ofType { name ofType { name } } } } } } } } }`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			assert.NoError(t, err)
+
+			err = checkComplexity(document, graphQLRequest{Query: tt.query, OperationName: tt.operationName, Variables: tt.variables})
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
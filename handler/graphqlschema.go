@@ -0,0 +1,321 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"stockpricews/entity"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	// defaultListLength and maxListLength bound the first argument of the list fields
+	defaultListLength = 100
+	maxListLength     = 1000
+
+	firstArg = "first"
+)
+
+// graphQLSchema is built once, the resolvers reach the controller through the root value of the query, i.e. the
+// StockPriceHandler serving it
+var graphQLSchema = newGraphQLSchema()
+
+// graphQLError is the error reported by the resolvers, the code tells the kind of the failure the same way the HTTP
+// status codes do for the REST endpoints
+type graphQLError struct {
+	message string
+	code    string
+}
+
+func (e graphQLError) Error() string {
+	return e.message
+}
+
+// Extensions implements gqlerrors.ExtendedError, so the code is reported as extensions.code of the error
+func (e graphQLError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+// resolverError maps the error to the message that is safe to be reported to the client and the code of its kind
+func resolverError(err error) error {
+	// log the error at the server log for debug purposes
+	fmt.Println(err)
+
+	statusCode, errMsg := errorStatus(err)
+	switch statusCode {
+	case http.StatusBadRequest:
+		return graphQLError{message: errMsg, code: "BAD_REQUEST"}
+	case http.StatusNotFound:
+		return graphQLError{message: errMsg, code: "NOT_FOUND"}
+	case http.StatusGatewayTimeout:
+		return graphQLError{message: errMsg, code: "TIMEOUT"}
+	default:
+		return graphQLError{message: errMsg, code: "INTERNAL"}
+	}
+}
+
+// rootHandler returns the handler the query is executed by
+func rootHandler(p graphql.ResolveParams) StockPriceHandler {
+	return p.Info.RootValue.(StockPriceHandler)
+}
+
+func newGraphQLSchema() graphql.Schema {
+	directionEnum := graphql.NewEnum(graphql.EnumConfig{
+		Name:        "Direction",
+		Description: "LONG buys first and sells later, SHORT sells first and buys back later",
+		Values: graphql.EnumValueConfigMap{
+			"LONG":  {Value: entity.DirectionLong},
+			"SHORT": {Value: entity.DirectionShort},
+		},
+	})
+
+	priceFieldEnum := graphql.NewEnum(graphql.EnumConfig{
+		Name:        "PriceField",
+		Description: "The price of the quotes the trades are made at",
+		Values: graphql.EnumValueConfigMap{
+			"OPEN":        {Value: entity.FieldOpen},
+			"HIGH":        {Value: entity.FieldHigh},
+			"LOW":         {Value: entity.FieldLow},
+			"CLOSE":       {Value: entity.FieldClose},
+			"OPTIMISTIC":  {Value: entity.FieldOptimistic, Description: "buys at the low and sells at the high of the period"},
+			"PESSIMISTIC": {Value: entity.FieldPessimistic, Description: "buys at the high and sells at the low of the period"},
+		},
+	})
+
+	symbolStatusEnum := graphql.NewEnum(graphql.EnumConfig{
+		Name: "SymbolStatus",
+		Values: graphql.EnumValueConfigMap{
+			"LISTED":   {Value: entity.SymbolListed},
+			"DELISTED": {Value: entity.SymbolDelisted},
+		},
+	})
+
+	tradePointType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "TradePoint",
+		Fields: graphql.Fields{
+			"price": {Type: graphql.NewNonNull(graphql.Float)},
+			"date":  {Type: graphql.NewNonNull(graphql.DateTime)},
+		},
+	})
+
+	maxProfitType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "MaxProfit",
+		Description: "The single buy/sell trade with the maximum profit within the time slice",
+		Fields: graphql.Fields{
+			"buyPoint":         {Type: graphql.NewNonNull(tradePointType)},
+			"sellPoint":        {Type: graphql.NewNonNull(tradePointType)},
+			"direction":        {Type: graphql.NewNonNull(directionEnum)},
+			"profit":           {Type: graphql.NewNonNull(graphql.Float), Description: "profit per share"},
+			"return":           {Type: graphql.NewNonNull(graphql.Float), Description: "percentage return relative to the price the position is opened at"},
+			"annualizedReturn": {Type: graphql.Float, Description: "compound annual growth rate in percents, null for positions held less than a day"},
+			"holdingDays":      {Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+
+	quoteType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Quote",
+		Fields: graphql.Fields{
+			"symbol":    {Type: graphql.NewNonNull(graphql.String)},
+			"datepoint": {Type: graphql.NewNonNull(graphql.DateTime)},
+			"price":     {Type: graphql.NewNonNull(graphql.Float), Description: "close price of the period"},
+			"open":      {Type: graphql.NewNonNull(graphql.Float)},
+			"high":      {Type: graphql.NewNonNull(graphql.Float)},
+			"low":       {Type: graphql.NewNonNull(graphql.Float)},
+			// the volume may exceed the 32-bit GraphQL Int
+			"volume": {Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+
+	// the symbol is an argument of the top-level fields only, the fields of Symbol take it from their parent
+	withSymbol := func(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+		args[symbol] = &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)}
+		return args
+	}
+	timeSliceArgs := func(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+		args[begin] = &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.DateTime)}
+		args[end] = &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.DateTime)}
+		return args
+	}
+	maxProfitArgs := func() graphql.FieldConfigArgument {
+		return timeSliceArgs(graphql.FieldConfigArgument{
+			direction: {Type: directionEnum, DefaultValue: entity.DirectionLong},
+			field:     {Type: priceFieldEnum, DefaultValue: entity.FieldClose},
+			adjusted:  {Type: graphql.Boolean, DefaultValue: false},
+		})
+	}
+	quotesArgs := func() graphql.FieldConfigArgument {
+		return timeSliceArgs(graphql.FieldConfigArgument{
+			firstArg: {Type: graphql.Int, DefaultValue: defaultListLength, Description: "max number of quotes returned, at most 1000"},
+		})
+	}
+
+	symbolType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Symbol",
+		Fields: graphql.Fields{
+			"symbol":         {Type: graphql.NewNonNull(graphql.String)},
+			"name":           {Type: graphql.NewNonNull(graphql.String)},
+			"exchange":       {Type: graphql.NewNonNull(graphql.String)},
+			"currency":       {Type: graphql.NewNonNull(graphql.String)},
+			"timezone":       {Type: graphql.NewNonNull(graphql.String)},
+			"status":         {Type: graphql.NewNonNull(symbolStatusEnum)},
+			"firstDatapoint": {Type: graphql.DateTime, Description: "null if the symbol has no quotes"},
+			"lastDatapoint":  {Type: graphql.DateTime, Description: "null if the symbol has no quotes"},
+			"quotes": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(quoteType))),
+				Args: quotesArgs(),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return resolveQuotes(p, p.Source.(entity.Symbol).Symbol)
+				},
+			},
+			"maxProfit": {
+				Type: maxProfitType,
+				Args: maxProfitArgs(),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return resolveMaxProfit(p, p.Source.(entity.Symbol).Symbol)
+				},
+			},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"symbols": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(symbolType))),
+				Description: "The symbol catalog in alphabetical order",
+				Args: graphql.FieldConfigArgument{
+					firstArg: {Type: graphql.Int, DefaultValue: defaultListLength, Description: "max number of symbols returned, at most 1000"},
+				},
+				Resolve: resolveSymbols,
+			},
+			"symbol": {
+				Type: symbolType,
+				Args: withSymbol(graphql.FieldConfigArgument{}),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					s, err := rootHandler(p).describeSymbol(p, p.Args[symbol].(string))
+					if err != nil {
+						return nil, err
+					}
+					return s, nil
+				},
+			},
+			"quotes": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(quoteType))),
+				Description: "The stored quotes of the time slice ordered by date point",
+				Args:        withSymbol(quotesArgs()),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					s, err := rootHandler(p).describeSymbol(p, p.Args[symbol].(string))
+					if err != nil {
+						return nil, err
+					}
+					return resolveQuotes(p, s.Symbol)
+				},
+			},
+			"maxProfit": {
+				Type: maxProfitType,
+				Args: withSymbol(maxProfitArgs()),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					s, err := rootHandler(p).describeSymbol(p, p.Args[symbol].(string))
+					if err != nil {
+						return nil, err
+					}
+					return resolveMaxProfit(p, s.Symbol)
+				},
+			},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+	if err != nil {
+		panic(fmt.Sprintf("invalid GraphQL schema: %v", err))
+	}
+	return schema
+}
+
+// describeSymbol validates the symbol and reports entity.ErrNotFound if it isn't in the catalog
+func (h StockPriceHandler) describeSymbol(p graphql.ResolveParams, stockSymbol string) (entity.Symbol, error) {
+	if err := validateSymbol(stockSymbol); err != nil {
+		return entity.Symbol{}, resolverError(err)
+	}
+
+	s, err := h.Controller.DescribeSymbol(p.Context, stockSymbol)
+	if err != nil {
+		return entity.Symbol{}, resolverError(err)
+	}
+	return s, nil
+}
+
+func resolveSymbols(p graphql.ResolveParams) (interface{}, error) {
+	first, err := listLength(p)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	catalog, err := rootHandler(p).Controller.SymbolCatalog(p.Context)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	if len(catalog.Symbols) > first {
+		catalog.Symbols = catalog.Symbols[:first]
+	}
+	return catalog.Symbols, nil
+}
+
+// resolveQuotes reads the first quotes of the time slice, the query is limited to them so the rest isn't loaded at all
+func resolveQuotes(p graphql.ResolveParams, stockSymbol string) (interface{}, error) {
+	first, err := listLength(p)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	timeSlice, err := timeSliceArgument(p, stockSymbol)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	timeSlice.Limit = first
+	quotes := []entity.StockQuote{}
+	err = rootHandler(p).Controller.ExportStockQuotes(p.Context, timeSlice, func(quote entity.StockQuote) error {
+		quotes = append(quotes, quote)
+		return nil
+	})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return quotes, nil
+}
+
+func resolveMaxProfit(p graphql.ResolveParams, stockSymbol string) (interface{}, error) {
+	timeSlice, err := timeSliceArgument(p, stockSymbol)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	timeSlice.Adjusted = p.Args[adjusted].(bool)
+	timeSlice.Field = p.Args[field].(entity.PriceField)
+
+	points, err := rootHandler(p).maxProfitForPosition(p.Context, timeSlice, p.Args[direction].(entity.Direction), entity.Position{}, false)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return points, nil
+}
+
+// timeSliceArgument returns the time slice of the symbol passed by the begin and end arguments
+func timeSliceArgument(p graphql.ResolveParams, stockSymbol string) (entity.StockQuoteRequest, error) {
+	timeSlice := entity.StockQuoteRequest{Symbol: stockSymbol, Begin: p.Args[begin].(time.Time), End: p.Args[end].(time.Time)}
+	if timeSlice.Begin.After(timeSlice.End) {
+		return entity.StockQuoteRequest{}, fmt.Errorf("begin period is after the end period: %w", entity.ErrBadRequest)
+	}
+	return timeSlice, nil
+}
+
+// listLength returns the first argument of the list field
+func listLength(p graphql.ResolveParams) (int, error) {
+	first := p.Args[firstArg].(int)
+	if first < 1 || first > maxListLength {
+		return 0, fmt.Errorf("%s argument must be between 1 and %d: %w", firstArg, maxListLength, entity.ErrBadRequest)
+	}
+	return first, nil
+}
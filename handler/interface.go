@@ -4,4 +4,18 @@ import "net/http"
 
 type Handler interface {
 	MaxProfitForPeriod(w http.ResponseWriter, r *http.Request)
+	TopTradeWindows(w http.ResponseWriter, r *http.Request)
+	SymbolLeaderboard(w http.ResponseWriter, r *http.Request)
+	MaxProfitStream(w http.ResponseWriter, r *http.Request)
+	QuoteStream(w http.ResponseWriter, r *http.Request)
+	SaveStockQuotes(w http.ResponseWriter, r *http.Request)
+	ExportStockQuotes(w http.ResponseWriter, r *http.Request)
+	Quotes(w http.ResponseWriter, r *http.Request)
+	Symbols(w http.ResponseWriter, r *http.Request)
+	SymbolCatalog(w http.ResponseWriter, r *http.Request)
+	AddSymbol(w http.ResponseWriter, r *http.Request)
+	DescribeSymbol(w http.ResponseWriter, r *http.Request)
+	GraphQL(w http.ResponseWriter, r *http.Request)
+	OpenAPI(w http.ResponseWriter, r *http.Request)
+	Router() http.Handler
 }
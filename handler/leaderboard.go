@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"stockpricews/entity"
+	"strings"
+)
+
+const (
+	symbols = "symbols"
+
+	// upper bound of the symbols a single leaderboard can be requested for
+	maxLeaderboardSymbols = 50
+)
+
+// SymbolLeaderboard is HTTP handler that returns to client the given symbols (or all stored symbols if none is given)
+// ranked by the percentage return of their max profit within given time slice.
+// Usage: curl GET /maxprofit/leaderboard?begin=<begin_time>&end=<end_time>[&symbols=<SYMBOL_1>,<SYMBOL_2>]
+// Result status codes are the same as the ones of MaxProfitForPeriod, on success the body contains
+// entity.Leaderboard as json. Symbols that failed carry an error message in their entry rather than failing the request.
+func (h StockPriceHandler) SymbolLeaderboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	timeSlice, err := parseTimeSlice(r)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	symbolList, err := parseSymbols(r)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	leaderboard, err := h.Controller.SymbolLeaderboard(ctx, symbolList, timeSlice)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	for i := range leaderboard.Entries {
+		if entryErr := leaderboard.Entries[i].Err; entryErr != nil {
+			// log the error at the server log for debug purposes, the client gets the safe message only
+			fmt.Println(fmt.Errorf("%s: %w", leaderboard.Entries[i].Symbol, entryErr))
+			_, leaderboard.Entries[i].Error = errorStatus(entryErr)
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(leaderboard)
+}
+
+// parseSymbols returns the deduplicated comma separated list of symbols, nil if symbols param is not passed
+func parseSymbols(r *http.Request) ([]string, error) {
+	if !r.URL.Query().Has(symbols) {
+		return nil, nil
+	}
+
+	var symbolList []string
+	seen := make(map[string]bool)
+	for _, s := range strings.Split(r.URL.Query().Get(symbols), ",") {
+		s = strings.TrimSpace(s)
+		if err := validateSymbol(s); err != nil {
+			return nil, err
+		}
+		if !seen[s] {
+			seen[s] = true
+			symbolList = append(symbolList, s)
+		}
+	}
+
+	if len(symbolList) > maxLeaderboardSymbols {
+		return nil, fmt.Errorf("%s param can't contain more than %d symbols: %w", symbols, maxLeaderboardSymbols, entity.ErrBadRequest)
+	}
+
+	return symbolList, nil
+}
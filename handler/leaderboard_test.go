@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbolLeaderboard_StatusCodes(t *testing.T) {
+	testCases := []struct {
+		name               string
+		controller         MockController
+		url                string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "Successfull GET request with per symbol errors",
+			url:                "maxprofit/leaderboard?begin=1699228800&end=2699228800&symbols=UBER,TSLA,UBER",
+			expectedStatusCode: http.StatusOK,
+			expectedBody: "{\"entries\":[" +
+				"{\"rank\":1,\"symbol\":\"UBER\",\"profit\":1,\"return\":10}," +
+				"{\"rank\":2,\"symbol\":\"TSLA\",\"profit\":1,\"return\":10}," +
+				"{\"symbol\":\"NOPE\",\"profit\":0,\"return\":0,\"error\":\"no records found: not found\"}," +
+				"{\"symbol\":\"FAIL\",\"profit\":0,\"return\":0,\"error\":\"Internal server error\"}]}\n",
+		},
+		{
+			name:               "Invalid symbol in the list",
+			url:                "maxprofit/leaderboard?begin=1699228800&end=2699228800&symbols=UBER,TES%20LA",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"stock symbol \\\"TES LA\\\" may contain letters, digits, '.', '-', '=' and '^' only: bad request\"}\n",
+		},
+		{
+			name:               "End param is missing",
+			url:                "maxprofit/leaderboard?begin=1699228800",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"end param is missing: bad request\"}\n",
+		},
+		{
+			name:               "Symbols can't be listed",
+			controller:         MockController{err: errors.New("connection refused")},
+			url:                "maxprofit/leaderboard?begin=1699228800&end=2699228800",
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       "{\"message\":\"Internal server error\"}\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.url, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			StockPriceHandler{Controller: tt.controller}.SymbolLeaderboard(rr, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,529 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"stockpricews/controller"
+	"stockpricews/entity"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// openAPIVersion is the version of the API described by the document, bumped with every change of the /v1 contract
+const openAPIVersion = "1.0.0"
+
+// openAPIDocument is the subset of OpenAPI 3.0 the API is described with
+type openAPIDocument struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       openAPIInfo                            `json:"info"`
+	Servers    []openAPIServer                        `json:"servers"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components openAPIComponents                      `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary"`
+	Description string                     `json:"description,omitempty"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+}
+
+type openAPIParameter struct {
+	Ref         string         `json:"$ref,omitempty"`
+	Name        string         `json:"name,omitempty"`
+	In          string         `json:"in,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema,omitempty"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                    `json:"required"`
+	Content  map[string]openAPIMedia `json:"content"`
+}
+
+type openAPIResponse struct {
+	Ref         string                  `json:"$ref,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	Content     map[string]openAPIMedia `json:"content,omitempty"`
+}
+
+type openAPIMedia struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Pattern              string                    `json:"pattern,omitempty"`
+	MinLength            *int                      `json:"minLength,omitempty"`
+	MaxLength            *int                      `json:"maxLength,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty"`
+	Maximum              *float64                  `json:"maximum,omitempty"`
+	Default              interface{}               `json:"default,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *bool                     `json:"additionalProperties,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	OneOf                []*openAPISchema          `json:"oneOf,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema        `json:"schemas"`
+	Parameters      map[string]openAPIParameter      `json:"parameters"`
+	Responses       map[string]openAPIResponse       `json:"responses"`
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme"`
+	Description string `json:"description"`
+}
+
+// schemaEnums are the values of the string types of the entities, reflection can't list the constants
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeOf(entity.Direction("")):    {string(entity.DirectionLong), string(entity.DirectionShort)},
+	reflect.TypeOf(entity.RankBy("")):       {string(entity.RankByAbsolute), string(entity.RankByPercent)},
+	reflect.TypeOf(entity.SymbolStatus("")): {string(entity.SymbolListed), string(entity.SymbolDelisted)},
+}
+
+// schemaGenerator derives the schemas of the components from the json tags of the Go types, so the document can't
+// drift from the responses
+type schemaGenerator struct {
+	schemas map[string]*openAPISchema
+}
+
+// define registers the schema of the struct type of v under the name and returns the reference to it
+func (g schemaGenerator) define(name string, v interface{}) *openAPISchema {
+	return g.named(name, reflect.TypeOf(v))
+}
+
+func (g schemaGenerator) named(name string, t reflect.Type) *openAPISchema {
+	ref := &openAPISchema{Ref: "#/components/schemas/" + name}
+	if _, ok := g.schemas[name]; ok {
+		return ref
+	}
+
+	closed := false
+	s := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}, AdditionalProperties: &closed}
+	// registered before the fields, so recursive types refer to themselves
+	g.schemas[name] = s
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if !f.IsExported() || tag == "-" || tag == "" {
+			continue
+		}
+		fieldName, options, _ := strings.Cut(tag, ",")
+		s.Properties[fieldName] = g.schema(f.Type)
+		if !strings.Contains(options, "omitempty") {
+			s.Required = append(s.Required, fieldName)
+		}
+	}
+	return ref
+}
+
+func (g schemaGenerator) schema(t reflect.Type) *openAPISchema {
+	if values, ok := schemaEnums[t]; ok {
+		return &openAPISchema{Type: "string", Enum: values}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			return &openAPISchema{Type: "string", Format: "date-time"}
+		}
+		return g.named(t.Name(), t)
+	case reflect.Slice:
+		return &openAPISchema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int32:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number", Format: "double"}
+	case reflect.Map:
+		return &openAPISchema{Type: "object"}
+	case reflect.Interface:
+		return &openAPISchema{Description: "any json value"}
+	default:
+		panic(fmt.Sprintf("no OpenAPI schema for %s", t))
+	}
+}
+
+// OpenAPI is HTTP handler that returns the OpenAPI 3 document describing the /v1 endpoints.
+// Usage: curl GET /v1/openapi.json
+func (h StockPriceHandler) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if !(r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions) {
+		respondWithError(fmt.Errorf("method %s not allowed: %w", r.Method, entity.ErrMethodNotAllowed), w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(apiDocument())
+}
+
+// apiDocument describes every route of the API, the paths are relative to the /v1 server
+func apiDocument() openAPIDocument {
+	g := schemaGenerator{schemas: map[string]*openAPISchema{}}
+	errorMessage := g.define("ErrorMessage", entity.ErrorMessage{})
+	maxProfit := g.define("MaxProfitPoints", entity.MaxProfitPoints{})
+	multiTrade := g.define("MultiTradeProfit", entity.MultiTradeProfit{})
+	topWindows := g.define("TopTradeWindows", entity.TopTradeWindows{})
+	leaderboard := g.define("Leaderboard", entity.Leaderboard{})
+	ingestion := g.define("QuoteIngestion", entity.QuoteIngestion{})
+	quote := g.define("Quote", exportedQuote{})
+	symbolSchema := g.define("Symbol", entity.Symbol{})
+	catalog := g.define("SymbolCatalog", entity.SymbolCatalog{})
+	newSymbol := g.define("NewSymbol", postedSymbol{})
+	g.schemas["NewSymbol"].Required = []string{"symbol"}
+	g.schemas["PostedQuote"] = postedQuoteSchema()
+	postedQuote := &openAPISchema{Ref: "#/components/schemas/PostedQuote"}
+	graphQLQuery := g.define("GraphQLRequest", graphQLRequest{})
+	graphQLResult := g.define("GraphQLResult", graphql.Result{})
+
+	jsonContent := func(s *openAPISchema) map[string]openAPIMedia {
+		return map[string]openAPIMedia{"application/json": {Schema: s}}
+	}
+	responseRef := func(name string) openAPIResponse {
+		return openAPIResponse{Ref: "#/components/responses/" + name}
+	}
+	paramRef := func(name string) openAPIParameter {
+		return openAPIParameter{Ref: "#/components/parameters/" + name}
+	}
+	queryParam := func(name, description string, s *openAPISchema) openAPIParameter {
+		return openAPIParameter{Name: name, In: "query", Description: description, Schema: s}
+	}
+	intRange := func(min, max, def int) *openAPISchema {
+		lower, upper := float64(min), float64(max)
+		return &openAPISchema{Type: "integer", Minimum: &lower, Maximum: &upper, Default: def}
+	}
+	zero := 0.0
+	nonNegative := &openAPISchema{Type: "number", Format: "double", Minimum: &zero}
+	// errors shared by the endpoints computing over a time slice
+	computeErrors := func(responses map[string]openAPIResponse) map[string]openAPIResponse {
+		for code, name := range map[string]string{"400": "BadRequest", "404": "NotFound", "405": "MethodNotAllowed",
+			"429": "TooManyRequests", "500": "InternalServerError", "504": "GatewayTimeout"} {
+			responses[code] = responseRef(name)
+		}
+		return responses
+	}
+	graphQLResponses := func() map[string]openAPIResponse {
+		return map[string]openAPIResponse{
+			"200": {Description: "the result, the fields that failed are null and their errors are listed with extensions.code",
+				Content: jsonContent(graphQLResult)},
+			"400": {Description: "the request can't be read, the query isn't valid or it's too complex", Content: jsonContent(graphQLResult)},
+			"405": responseRef("MethodNotAllowed"),
+			"429": responseRef("TooManyRequests"),
+		}
+	}
+	ingestToken := []map[string][]string{{"ingestToken": {}}}
+
+	minSymbol, maxSymbol := 1, controller.MaxSymbolLength
+	symbolParam := &openAPISchema{Type: "string", MinLength: &minSymbol, MaxLength: &maxSymbol, Pattern: `^[A-Za-z0-9^][A-Za-z0-9.=^-]*$`}
+	timeParam := &openAPISchema{Type: "string", Description: "unix seconds, RFC 3339, YYYY-MM-DD[ HH:MM:SS] in the tz zone, now, " +
+		"today, ytd or relative to now: -<n>h, -<n>d, -<n>w, -<n>mo or -<n>y"}
+
+	return openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title: "Stock Price WS",
+			Description: "Computes the maximum profit that could have been realized trading a stock within a historical " +
+				"time slice, and stores and exports the quotes it's computed from.",
+			Version: openAPIVersion,
+		},
+		Servers: []openAPIServer{{URL: apiVersion}},
+		Paths: map[string]map[string]openAPIOperation{
+			"/maxprofit": {"get": {
+				OperationID: "maxProfit",
+				Summary:     "Maximum profit within a time slice",
+				Description: "Returns MaxProfitPoints of the single best trade, or MultiTradeProfit if more than one " +
+					"transaction (k > 1) is allowed or trading costs are passed.",
+				Parameters: []openAPIParameter{
+					paramRef("symbol"), paramRef("begin"), paramRef("end"), paramRef("tz"),
+					queryParam(transactions, "max number of non-overlapping buy/sell transactions", intRange(1, maxTransactions, 1)),
+					queryParam(fee, "fee charged for every trade, computes the optimal schedule with unlimited transactions, can't be combined with k", nonNegative),
+					queryParam(feeType, "absolute charges the fee once per round trip, percent charges fee percent of the traded value on both sides",
+						&openAPISchema{Type: "string", Enum: []string{feeTypeAbsolute, feeTypePercent}, Default: feeTypeAbsolute}),
+					queryParam(cooldown, "minimum time between a sell and the next buy in seconds", &openAPISchema{Type: "integer", Format: "int64", Minimum: &zero}),
+					queryParam(direction, "long buys first, short sells first, single transaction only",
+						&openAPISchema{Type: "string", Enum: schemaEnums[reflect.TypeOf(entity.Direction(""))], Default: string(entity.DirectionLong)}),
+					queryParam(shares, "number of shares the position profit is reported for, single transaction only", nonNegative),
+					queryParam(capital, "amount invested in the position the profit is reported for, can't be combined with shares", nonNegative),
+					paramRef("adjusted"), paramRef("field"),
+				},
+				Responses: computeErrors(map[string]openAPIResponse{
+					"200": {Description: "the max profit", Content: jsonContent(&openAPISchema{OneOf: []*openAPISchema{maxProfit, multiTrade}})},
+				}),
+			}},
+			"/maxprofit/top": {"get": {
+				OperationID: "topTradeWindows",
+				Summary:     "Most profitable non-overlapping trade windows within a time slice",
+				Parameters: []openAPIParameter{
+					paramRef("symbol"), paramRef("begin"), paramRef("end"), paramRef("tz"),
+					queryParam(windows, "number of windows", intRange(1, maxWindows, defaultWindows)),
+					queryParam(rankBy, "windows are ranked by their absolute profit or percentage return",
+						&openAPISchema{Type: "string", Enum: schemaEnums[reflect.TypeOf(entity.RankBy(""))], Default: string(entity.RankByAbsolute)}),
+					paramRef("adjusted"), paramRef("field"),
+				},
+				Responses: computeErrors(map[string]openAPIResponse{
+					"200": {Description: "the trade windows", Content: jsonContent(topWindows)},
+				}),
+			}},
+			"/maxprofit/leaderboard": {"get": {
+				OperationID: "symbolLeaderboard",
+				Summary:     "Symbols ranked by the return of their max profit within a time slice",
+				Description: "Symbols that failed carry an error message in their entry rather than failing the request.",
+				Parameters: []openAPIParameter{
+					paramRef("begin"), paramRef("end"), paramRef("tz"),
+					queryParam(symbols, fmt.Sprintf("comma separated symbols, at most %d, all stored symbols if missing", maxLeaderboardSymbols),
+						&openAPISchema{Type: "string"}),
+					paramRef("adjusted"), paramRef("field"),
+				},
+				Responses: computeErrors(map[string]openAPIResponse{
+					"200": {Description: "the ranked symbols", Content: jsonContent(leaderboard)},
+				}),
+			}},
+			"/maxprofit/stream": {"get": {
+				OperationID: "maxProfitStream",
+				Summary:     "WebSocket pushing the running max profit of a symbol",
+				Description: "Upgrades the connection to WebSocket, every message is MaxProfitPoints as json. Errors before " +
+					"the upgrade are reported as json.",
+				Parameters: []openAPIParameter{
+					paramRef("symbol"),
+					queryParam(begin, "seeds the computation with the stored quotes after the time point, otherwise only new quotes count",
+						timeParam), paramRef("tz"),
+				},
+				Responses: computeErrors(map[string]openAPIResponse{
+					"101": {Description: "the connection is upgraded to WebSocket, the messages are MaxProfitPoints"},
+				}),
+			}},
+			"/stream/quotes": {"get": {
+				OperationID: "quoteStream",
+				Summary:     "Server-Sent Events pushing every new quote of a symbol",
+				Description: "Every quote is a quote event with the date point in unix nanoseconds as its id. The quotes dated " +
+					"after the Last-Event-ID header, or stored after the begin param, are replayed first. Quotes " +
+					"backfilled before the last event aren't replayed on resume. A client that can't keep up " +
+					"gets a dropped event and the stream ends. Errors before the stream starts are reported as json.",
+				Parameters: []openAPIParameter{
+					paramRef("symbol"),
+					queryParam(begin, "replays the stored quotes after the time point, otherwise only new quotes are pushed",
+						timeParam), paramRef("tz"),
+					{Name: lastEventID, In: "header", Description: "id of the last event received, the stream resumes after it",
+						Schema: &openAPISchema{Type: "integer", Format: "int64"}},
+				},
+				Responses: computeErrors(map[string]openAPIResponse{
+					"200": {Description: "the event stream, the data of every quote event is a Quote", Content: map[string]openAPIMedia{
+						"text/event-stream": {Schema: &openAPISchema{Type: "string"}},
+					}},
+				}),
+			}},
+			"/quotes": {
+				"get": {
+					OperationID: "exportQuotes",
+					Summary:     "Stored quotes of a time slice ordered by date point",
+					Description: "The format is negotiated by the Accept header, the format param overrides it. The quotes " +
+						"are streamed, if the export fails midway the connection is aborted.",
+					Parameters: []openAPIParameter{
+						paramRef("symbol"), paramRef("begin"), paramRef("end"), paramRef("tz"),
+						queryParam(exportFormat, "format of the export, overrides the Accept header",
+							&openAPISchema{Type: "string", Enum: []string{"json", "csv", "ndjson"}}),
+					},
+					Responses: map[string]openAPIResponse{
+						"200": {Description: "the quotes, an array of json objects, CSV with a header row or a json object per line", Content: map[string]openAPIMedia{
+							"application/json":     {Schema: &openAPISchema{Type: "array", Items: quote}},
+							"text/csv":             {Schema: &openAPISchema{Type: "string", Description: "header row: symbol,datepoint,price,open,high,low,volume"}},
+							"application/x-ndjson": {Schema: &openAPISchema{Ref: quote.Ref, Description: "every line holds a quote"}},
+						}},
+						"400": responseRef("BadRequest"),
+						"404": responseRef("NotFound"),
+						"405": responseRef("MethodNotAllowed"),
+						"406": responseRef("NotAcceptable"),
+						"429": responseRef("TooManyRequests"),
+						"500": responseRef("InternalServerError"),
+					},
+				},
+				"post": {
+					OperationID: "saveQuotes",
+					Summary:     "Stores quotes, a quote replaces the stored one of the same symbol and date point",
+					Description: fmt.Sprintf("At most %d quotes are stored at once. The valid quotes are stored in a "+
+						"single transaction, the invalid ones are reported by their 1-based position.", maxIngestQuotes),
+					RequestBody: &openAPIRequestBody{Required: true, Content: map[string]openAPIMedia{
+						"application/json": {Schema: &openAPISchema{OneOf: []*openAPISchema{postedQuote, {Type: "array", Items: postedQuote}}}},
+						"text/csv": {Schema: &openAPISchema{Type: "string",
+							Description: "header row naming the columns: symbol,datepoint,price[,open,high,low,volume]"}},
+					}},
+					Responses: map[string]openAPIResponse{
+						"200": {Description: "the valid quotes were stored", Content: jsonContent(ingestion)},
+						"400": responseRef("BadRequest"),
+						"401": responseRef("Unauthorized"),
+						"429": responseRef("TooManyRequests"),
+						"500": responseRef("InternalServerError"),
+						"504": responseRef("GatewayTimeout"),
+					},
+					Security: ingestToken,
+				},
+			},
+			"/symbols": {
+				"get": {
+					OperationID: "symbolCatalog",
+					Summary:     "All known symbols in alphabetical order",
+					Responses: map[string]openAPIResponse{
+						"200": {Description: "the symbol catalog", Content: jsonContent(catalog)},
+						"405": responseRef("MethodNotAllowed"),
+						"429": responseRef("TooManyRequests"),
+						"500": responseRef("InternalServerError"),
+						"504": responseRef("GatewayTimeout"),
+					},
+				},
+				"post": {
+					OperationID: "addSymbol",
+					Summary:     "Adds a symbol to the catalog",
+					Description: "The timezone defaults to UTC and the status to listed.",
+					RequestBody: &openAPIRequestBody{Required: true, Content: jsonContent(newSymbol)},
+					Responses: map[string]openAPIResponse{
+						"201": {Description: "the stored symbol", Content: jsonContent(symbolSchema)},
+						"400": responseRef("BadRequest"),
+						"401": responseRef("Unauthorized"),
+						"409": responseRef("Conflict"),
+						"429": responseRef("TooManyRequests"),
+						"500": responseRef("InternalServerError"),
+						"504": responseRef("GatewayTimeout"),
+					},
+					Security: ingestToken,
+				},
+			},
+			"/symbols/{symbol}": {"get": {
+				OperationID: "describeSymbol",
+				Summary:     "Metadata of a single symbol",
+				Parameters: []openAPIParameter{{Name: symbol, In: "path", Required: true,
+					Description: "the symbol, index tickers are escaped like %5EGSPC", Schema: symbolParam}},
+				Responses: map[string]openAPIResponse{
+					"200": {Description: "the symbol", Content: jsonContent(symbolSchema)},
+					"400": responseRef("BadRequest"),
+					"404": responseRef("NotFound"),
+					"405": responseRef("MethodNotAllowed"),
+					"429": responseRef("TooManyRequests"),
+					"500": responseRef("InternalServerError"),
+					"504": responseRef("GatewayTimeout"),
+				},
+			}},
+			"/graphql": {
+				"get": {
+					OperationID: "graphQLQuery",
+					Summary:     "Executes a GraphQL query passed as query params",
+					Parameters: []openAPIParameter{
+						{Name: query, In: "query", Required: true, Description: "the GraphQL query", Schema: &openAPISchema{Type: "string"}},
+						queryParam(operationName, "the operation of the query to execute", &openAPISchema{Type: "string"}),
+						queryParam(variables, "the variables of the query as a json object", &openAPISchema{Type: "string"}),
+					},
+					Responses: graphQLResponses(),
+				},
+				"post": {
+					OperationID: "graphQL",
+					Summary:     "Executes a GraphQL query",
+					Description: fmt.Sprintf("Fetches the symbols, their quotes and max profits in a single round trip. "+
+						"Queries with an estimated cost above %d are rejected before they're executed.", maxQueryComplexity),
+					RequestBody: &openAPIRequestBody{Required: true, Content: jsonContent(graphQLQuery)},
+					Responses:   graphQLResponses(),
+				},
+			},
+			"/openapi.json": {"get": {
+				OperationID: "openAPI",
+				Summary:     "This document",
+				Responses: map[string]openAPIResponse{
+					"200": {Description: "the OpenAPI document", Content: jsonContent(&openAPISchema{Type: "object"})},
+					"405": responseRef("MethodNotAllowed"),
+				},
+			}},
+		},
+		Components: openAPIComponents{
+			Schemas: g.schemas,
+			Parameters: map[string]openAPIParameter{
+				"symbol": {Name: symbol, In: "query", Required: true, Description: "the symbol of the stock, e.g. UBER, BRK.B or VOD.L", Schema: symbolParam},
+				"begin": {Name: begin, In: "query", Required: true, Description: "quotes after the time point count",
+					Schema: timeParam},
+				"end": {Name: end, In: "query", Required: true, Description: "quotes before the time point count, not before begin",
+					Schema: timeParam},
+				"tz": {Name: tz, In: "query", Description: "IANA time zone the dates without an offset, today, ytd and the relative days are read in, the responses are in UTC",
+					Schema: &openAPISchema{Type: "string", Default: "UTC"}},
+				"adjusted": {Name: adjusted, In: "query", Description: "computes on prices back-adjusted for splits and dividends",
+					Schema: &openAPISchema{Type: "boolean", Default: false}},
+				"field": {Name: field, In: "query", Description: "price of the quotes the trades are made at, optimistic buys at the low and sells at the high, pessimistic the other way round",
+					Schema: &openAPISchema{Type: "string", Default: string(entity.FieldClose), Enum: []string{string(entity.FieldOpen), string(entity.FieldHigh),
+						string(entity.FieldLow), string(entity.FieldClose), string(entity.FieldOptimistic), string(entity.FieldPessimistic)}}},
+			},
+			Responses: map[string]openAPIResponse{
+				"BadRequest":          {Description: "a param or the body is missing or invalid", Content: jsonContent(errorMessage)},
+				"Unauthorized":        {Description: "the ingestion token is missing or wrong", Content: jsonContent(errorMessage)},
+				"NotFound":            {Description: "the symbol is unknown or there's no quote to compute from", Content: jsonContent(errorMessage)},
+				"MethodNotAllowed":    {Description: "the method isn't supported by the endpoint", Content: jsonContent(errorMessage)},
+				"NotAcceptable":       {Description: "none of the accepted media types is supported", Content: jsonContent(errorMessage)},
+				"Conflict":            {Description: "the resource exists already", Content: jsonContent(errorMessage)},
+				"TooManyRequests":     {Description: "the client got rate limited", Content: jsonContent(errorMessage)},
+				"InternalServerError": {Description: "an unexpected error occurred", Content: jsonContent(errorMessage)},
+				"GatewayTimeout":      {Description: "the request couldn't be computed within the request timeout", Content: jsonContent(errorMessage)},
+			},
+			SecuritySchemes: map[string]openAPISecurityScheme{
+				"ingestToken": {Type: "http", Scheme: "bearer", Description: "the token the service is started with (-ingest.token)"},
+			},
+		},
+	}
+}
+
+// postedQuoteSchema describes the posted quotes by hand, their date points take either a string or unix seconds the
+// same as in the files of the import subcommand
+func postedQuoteSchema() *openAPISchema {
+	datepoint := &openAPISchema{
+		Description: "RFC3339, YYYY-MM-DD[ HH:MM:SS] in UTC or unix seconds",
+		OneOf:       []*openAPISchema{{Type: "string"}, {Type: "integer", Format: "int64"}},
+	}
+	number := func(description string) *openAPISchema {
+		return &openAPISchema{Type: "number", Format: "double", Description: description}
+	}
+	return &openAPISchema{
+		Type: "object",
+		Properties: map[string]*openAPISchema{
+			"symbol":    {Type: "string"},
+			"datepoint": datepoint,
+			"date":      {Description: "alias of datepoint", OneOf: datepoint.OneOf},
+			"price":     number("close price of the period"),
+			"close":     number("alias of price"),
+			"open":      number(""),
+			"high":      number(""),
+			"low":       number(""),
+			"volume":    {Type: "integer", Format: "int64"},
+		},
+		Required: []string{"symbol"},
+	}
+}
@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fetchAPIDocument reads the OpenAPI document the router serves, so the contract is checked against what the clients get
+func fetchAPIDocument(t *testing.T, router http.Handler) openAPIDocument {
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/v1/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var doc openAPIDocument
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &doc))
+	return doc
+}
+
+func TestOpenAPI_Document(t *testing.T) {
+	h := StockPriceHandler{Controller: MockController{}}
+	doc := fetchAPIDocument(t, h.Router())
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.Equal(t, []openAPIServer{{URL: "/v1"}}, doc.Servers)
+
+	// every route is described and nothing else
+	var routes, paths []string
+	for _, rt := range h.routes() {
+		routes = append(routes, strings.Replace(rt.path, "/symbols/", "/symbols/{symbol}", 1))
+	}
+	routes = append(routes, "/openapi.json")
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(routes)
+	sort.Strings(paths)
+	assert.Equal(t, routes, paths)
+
+	// every reference resolves
+	raw, err := json.Marshal(doc)
+	assert.NoError(t, err)
+	for _, match := range regexp.MustCompile(`"\$ref":"#/components/(\w+)/(\w+)"`).FindAllStringSubmatch(string(raw), -1) {
+		var found bool
+		switch match[1] {
+		case "schemas":
+			_, found = doc.Components.Schemas[match[2]]
+		case "parameters":
+			_, found = doc.Components.Parameters[match[2]]
+		case "responses":
+			_, found = doc.Components.Responses[match[2]]
+		}
+		assert.True(t, found, match[0])
+	}
+
+	operationIDs := map[string]bool{}
+	for path, operations := range doc.Paths {
+		for method, operation := range operations {
+			assert.False(t, operationIDs[operation.OperationID], "duplicated operationId %s", operation.OperationID)
+			operationIDs[operation.OperationID] = true
+			assert.NotEmpty(t, operation.Responses, "%s %s", method, path)
+		}
+	}
+}
+
+func TestOpenAPI_Contract(t *testing.T) {
+	testCases := []struct {
+		name       string
+		controller MockController
+		method     string
+		url        string
+		header     http.Header
+		body       string
+		// path is the path of the operation in the document
+		path               string
+		expectedStatusCode int
+	}{
+		{name: "Max profit", url: "/v1/maxprofit?begin=1699228800&end=2699228800&symbol=UBER", path: "/maxprofit", expectedStatusCode: http.StatusOK},
+		{name: "Max profit with transactions", url: "/v1/maxprofit?begin=1699228800&end=2699228800&symbol=UBER&k=3", path: "/maxprofit", expectedStatusCode: http.StatusOK},
+		{name: "Max profit of short position", url: "/v1/maxprofit?begin=1699228800&end=2699228800&symbol=BRK.B&direction=short&shares=10", path: "/maxprofit", expectedStatusCode: http.StatusOK},
+		{name: "Max profit of dates in time zone", url: "/v1/maxprofit?begin=2023-11-06&end=-1d&symbol=UBER&tz=America/New_York", path: "/maxprofit", expectedStatusCode: http.StatusOK},
+		{name: "Max profit in unknown time zone", url: "/v1/maxprofit?begin=ytd&end=now&symbol=UBER&tz=Mars/Olympus", path: "/maxprofit", expectedStatusCode: http.StatusBadRequest},
+		{name: "Max profit with invalid param", url: "/v1/maxprofit?begin=1699228800&end=2699228800&symbol=UBER&k=0", path: "/maxprofit", expectedStatusCode: http.StatusBadRequest},
+		{name: "Max profit of unknown symbol", url: "/v1/maxprofit?begin=1699228800&end=2699228800&symbol=UNKN", path: "/maxprofit", expectedStatusCode: http.StatusNotFound},
+		{name: "Max profit failed", controller: MockController{err: errors.New("connection refused")}, url: "/v1/maxprofit?begin=1699228800&end=2699228800&symbol=UBER", path: "/maxprofit", expectedStatusCode: http.StatusInternalServerError},
+		{name: "Max profit by POST", method: "POST", url: "/v1/maxprofit?begin=1699228800&end=2699228800&symbol=UBER", path: "/maxprofit", expectedStatusCode: http.StatusMethodNotAllowed},
+		{name: "Top windows", url: "/v1/maxprofit/top?begin=1699228800&end=2699228800&symbol=UBER&n=3&rank=percent", path: "/maxprofit/top", expectedStatusCode: http.StatusOK},
+		{name: "Leaderboard", url: "/v1/maxprofit/leaderboard?begin=1699228800&end=2699228800&symbols=UBER,TSLA", path: "/maxprofit/leaderboard", expectedStatusCode: http.StatusOK},
+		{name: "Stream with invalid param", url: "/v1/maxprofit/stream?symbol=UBER&begin=yesterday", path: "/maxprofit/stream", expectedStatusCode: http.StatusBadRequest},
+		{name: "Export as JSON", url: "/v1/quotes?begin=1699228000&end=1699401600&symbol=UBER", path: "/quotes", expectedStatusCode: http.StatusOK},
+		{name: "Export as NDJSON", url: "/v1/quotes?begin=1699228000&end=1699401600&symbol=UBER&format=ndjson", path: "/quotes", expectedStatusCode: http.StatusOK},
+		{name: "Export as CSV", url: "/v1/quotes?begin=1699228000&end=1699401600&symbol=UBER", header: http.Header{"Accept": {"text/csv"}}, path: "/quotes", expectedStatusCode: http.StatusOK},
+		{name: "Export not acceptable", url: "/v1/quotes?begin=1699228000&end=1699401600&symbol=UBER", header: http.Header{"Accept": {"application/xml"}}, path: "/quotes", expectedStatusCode: http.StatusNotAcceptable},
+		{name: "Save quotes", method: "POST", url: "/v1/quotes", header: http.Header{"Authorization": {"Bearer secret"}},
+			body: `[{"symbol":"UBER","datepoint":"2023-11-08","price":50.1},{"symbol":"UBER","datepoint":1699488000,"price":5000}]`,
+			path: "/quotes", expectedStatusCode: http.StatusOK},
+		{name: "Save quotes without token", method: "POST", url: "/v1/quotes", body: `{"symbol":"UBER","datepoint":"2023-11-08","price":50.1}`, path: "/quotes", expectedStatusCode: http.StatusUnauthorized},
+		{name: "Symbol catalog", url: "/v1/symbols", path: "/symbols", expectedStatusCode: http.StatusOK},
+		{name: "Add symbol", method: "POST", url: "/v1/symbols", header: http.Header{"Authorization": {"Bearer secret"}},
+			body: `{"symbol":"VOD.L","name":"Vodafone Group","exchange":"LSE","currency":"GBP","timezone":"Europe/London"}`, path: "/symbols", expectedStatusCode: http.StatusCreated},
+		{name: "Add known symbol", method: "POST", url: "/v1/symbols", header: http.Header{"Authorization": {"Bearer secret"}}, body: `{"symbol":"UBER"}`, path: "/symbols", expectedStatusCode: http.StatusConflict},
+		{name: "Describe symbol", url: "/v1/symbols/%5EGSPC", path: "/symbols/{symbol}", expectedStatusCode: http.StatusOK},
+		{name: "Describe unknown symbol", url: "/v1/symbols/UNKN", path: "/symbols/{symbol}", expectedStatusCode: http.StatusNotFound},
+		{name: "GraphQL", method: "POST", url: "/v1/graphql",
+			body: `{"query":"{ symbols { symbol firstDatapoint maxProfit(begin: \"2023-11-06T00:00:00Z\", end: \"2023-11-08T00:00:00Z\") { profit } } }"}`,
+			path: "/graphql", expectedStatusCode: http.StatusOK},
+		{name: "GraphQL by GET", url: "/v1/graphql?query=%7B%20symbol(symbol%3A%20%22UNKN%22)%20%7B%20name%20%7D%20%7D", path: "/graphql", expectedStatusCode: http.StatusOK},
+		{name: "GraphQL too complex", method: "POST", url: "/v1/graphql",
+			body: `{"query":"{ symbols(first: 1000) { quotes(begin: \"2023-11-06T00:00:00Z\", end: \"2023-11-08T00:00:00Z\") { price } } }"}`,
+			path: "/graphql", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			router := StockPriceHandler{Controller: tt.controller, IngestToken: "secret"}.Router()
+			doc := fetchAPIDocument(t, router)
+
+			method := tt.method
+			if method == "" {
+				method = "GET"
+			}
+			operation, ok := doc.Paths[tt.path][strings.ToLower(method)]
+			if !ok {
+				// the methods an endpoint rejects aren't operations of the document, the status is checked against GET
+				operation, ok = doc.Paths[tt.path]["get"]
+				assert.Equal(t, http.StatusMethodNotAllowed, tt.expectedStatusCode)
+			}
+			assert.True(t, ok, "%s %s isn't described", method, tt.path)
+
+			req := httptest.NewRequest(method, tt.url, strings.NewReader(tt.body))
+			for key, values := range tt.header {
+				req.Header[key] = values
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code, rr.Body.String())
+
+			response, ok := operation.Responses[strconv.Itoa(rr.Code)]
+			assert.True(t, ok, "status %d isn't documented", rr.Code)
+			if response.Ref != "" {
+				response = doc.Components.Responses[strings.TrimPrefix(response.Ref, "#/components/responses/")]
+			}
+
+			mediaType, _, err := mime.ParseMediaType(rr.Header().Get("Content-Type"))
+			assert.NoError(t, err)
+			media, ok := response.Content[mediaType]
+			assert.True(t, ok, "content type %s of status %d isn't documented", mediaType, rr.Code)
+			if !ok {
+				return
+			}
+
+			switch mediaType {
+			case "application/json":
+				assert.NoError(t, validateJSON(doc, media.Schema, rr.Body.Bytes()))
+			case "application/x-ndjson":
+				scanner := bufio.NewScanner(rr.Body)
+				for scanner.Scan() {
+					assert.NoError(t, validateJSON(doc, media.Schema, scanner.Bytes()))
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAPI_Versioning(t *testing.T) {
+	router := StockPriceHandler{Controller: MockController{}}.Router()
+	for _, url := range []string{"/v1/symbols/BRK.B", "/symbols/BRK.B", "/v1/maxprofit?begin=1699228800&end=2699228800&symbol=UBER",
+		"/maxprofit?begin=1699228800&end=2699228800&symbol=UBER"} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", url, nil))
+		assert.Equal(t, http.StatusOK, rr.Code, url)
+	}
+
+	// the document describes the versioned API only
+	for _, url := range []string{"/openapi.json", "/v2/maxprofit?begin=1699228800&end=2699228800&symbol=UBER"} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", url, nil))
+		assert.Equal(t, http.StatusNotFound, rr.Code, url)
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	doc := apiDocument()
+	symbol := &openAPISchema{Ref: "#/components/schemas/Symbol"}
+	assert.NoError(t, validateJSON(doc, symbol, []byte(`{"symbol":"UBER","name":"","exchange":"","currency":"","timezone":"UTC","status":"listed"}`)))
+	assert.EqualError(t, validateJSON(doc, symbol, []byte(`{"symbol":"UBER","name":"","exchange":"","currency":"","timezone":"UTC"}`)),
+		"$: property status is missing")
+	assert.EqualError(t, validateJSON(doc, symbol, []byte(`{"symbol":"UBER","name":"","exchange":"","currency":"","timezone":"UTC","status":"halted"}`)),
+		"$.status: \"halted\" isn't one of [listed delisted]")
+	assert.EqualError(t, validateJSON(doc, symbol, []byte(`{"symbol":"UBER","name":"","exchange":"","currency":"","timezone":"UTC","status":"listed","isin":""}`)),
+		"$: property isin isn't documented")
+	assert.EqualError(t, validateJSON(doc, symbol, []byte(`{"symbol":"UBER","name":"","exchange":"","currency":"","timezone":"UTC","status":"listed","firstDatapoint":"today"}`)),
+		"$.firstDatapoint: \"today\" isn't a date-time")
+	assert.EqualError(t, validateJSON(doc, &openAPISchema{Ref: "#/components/schemas/QuoteIngestion"}, []byte(`{"stored":1.5,"rejected":[]}`)),
+		"$.stored: 1.5 isn't an integer")
+}
+
+// validateJSON checks the document against the schema, the subset of JSON schema the API document uses is supported
+func validateJSON(doc openAPIDocument, schema *openAPISchema, data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return validateValue(doc, schema, value, "$")
+}
+
+func validateValue(doc openAPIDocument, schema *openAPISchema, value interface{}, path string) error {
+	if schema.Ref != "" {
+		resolved, ok := doc.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		if !ok {
+			return fmt.Errorf("%s: unknown schema %s", path, schema.Ref)
+		}
+		schema = resolved
+	}
+
+	if len(schema.OneOf) > 0 {
+		matched := 0
+		for _, option := range schema.OneOf {
+			if validateValue(doc, option, value, path) == nil {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return fmt.Errorf("%s: %d of oneOf schemas match", path, matched)
+		}
+		return nil
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %v isn't an object", path, value)
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%s: property %s is missing", path, name)
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := schema.Properties[name]
+			if !ok {
+				if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+					return fmt.Errorf("%s: property %s isn't documented", path, name)
+				}
+				continue
+			}
+			if err := validateValue(doc, property, object[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %v isn't an array", path, value)
+		}
+		for i, item := range array {
+			if err := validateValue(doc, schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: %v isn't a string", path, value)
+		}
+		if len(schema.Enum) > 0 && !containsString(schema.Enum, s) {
+			return fmt.Errorf("%s: %q isn't one of %v", path, s, schema.Enum)
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				return fmt.Errorf("%s: %q isn't a date-time", path, s)
+			}
+		}
+	case "number", "integer":
+		n, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("%s: %v isn't a number", path, value)
+		}
+		if schema.Type == "integer" && n != math.Trunc(n) {
+			return fmt.Errorf("%s: %v isn't an integer", path, n)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: %v isn't a boolean", path, value)
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"stockpricews/entity"
+	"stockpricews/repository"
+	"strings"
+)
+
+const (
+	// upper bounds of a single POST /quotes request
+	maxIngestQuotes = 10000
+	maxIngestBytes  = 10 << 20
+)
+
+// SaveStockQuotes is HTTP handler that stores the posted quotes, a quote replaces the stored one of the same symbol
+// and date point. The client has to authenticate with the ingestion token: 'Authorization: Bearer <token>'.
+// Usage: curl -X POST /quotes -H 'Content-Type: application/json' -d '[{"symbol":"UBER","datepoint":"2023-11-08T00:00:00Z","price":50.1}]'
+// or a single JSON object, or CSV with a header row: symbol,datepoint,price[,open,high,low,volume]
+// The date points are RFC3339, 'YYYY-MM-DD[ HH:MM:SS]' in UTC or unix seconds.
+// Result status codes:
+//   - 200 OK - the valid quotes were stored. Body contains entity.QuoteIngestion as json with the rejected quotes
+//   - 400 Bad Request - if the body can't be read or none of the quotes is valid
+//   - 401 Unauthorized - if the token is missing or wrong, or no token is configured at all
+//   - 500 Internal Server Error - if the valid quotes couldn't be stored, none of them is stored then
+func (h StockPriceHandler) SaveStockQuotes(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Method != http.MethodPost {
+		respondWithError(fmt.Errorf("method %s not allowed: %w", r.Method, entity.ErrMethodNotAllowed), w)
+		return
+	}
+
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		respondWithError(fmt.Errorf("missing or invalid ingestion token: %w", entity.ErrUnauthorized), w)
+		return
+	}
+
+	quotes, rows, rejected, err := parseQuotes(r, http.MaxBytesReader(w, r.Body, maxIngestBytes))
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	result, err := h.Controller.SaveStockQuotes(ctx, quotes)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	// the controller numbers the quotes it got, the client needs their position in the body
+	for i := range result.Rejected {
+		result.Rejected[i].Row = rows[result.Rejected[i].Row-1]
+	}
+	result.Rejected = append(rejected, result.Rejected...)
+	sort.Slice(result.Rejected, func(i, j int) bool { return result.Rejected[i].Row < result.Rejected[j].Row })
+	for i := range result.Rejected {
+		_, result.Rejected[i].Error = errorStatus(result.Rejected[i].Err)
+	}
+
+	if result.Stored == 0 && len(result.Rejected) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// authorized checks the bearer token of the request in constant time, nobody is authorized if no token is configured
+func (h StockPriceHandler) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return h.IngestToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.IngestToken)) == 1
+}
+
+// parseQuotes reads the quotes of a JSON or CSV body. The quotes that can't be parsed are returned as rejected, rows
+// holds the 1-based position in the body of every parsed quote.
+func parseQuotes(r *http.Request, body io.Reader) ([]entity.StockQuote, []int, []entity.RejectedQuote, error) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "" {
+		var err error
+		if contentType, _, err = mime.ParseMediaType(contentType); err != nil {
+			return nil, nil, nil, fmt.Errorf("content type %q can't be parsed: %w", r.Header.Get("Content-Type"), entity.ErrBadRequest)
+		}
+	}
+
+	var records []func() (entity.StockQuote, error)
+	var err error
+	switch contentType {
+	case "", "application/json":
+		records, err = readJSONQuotes(body)
+	case "text/csv":
+		records, err = readCSVQuotes(body)
+	default:
+		return nil, nil, nil, fmt.Errorf("content type %s not supported, application/json or text/csv expected: %w", contentType, entity.ErrBadRequest)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, nil, nil, fmt.Errorf("no quotes posted: %w", entity.ErrBadRequest)
+	}
+	if len(records) > maxIngestQuotes {
+		return nil, nil, nil, fmt.Errorf("at most %d quotes can be posted at once: %w", maxIngestQuotes, entity.ErrBadRequest)
+	}
+
+	quotes := make([]entity.StockQuote, 0, len(records))
+	rows := make([]int, 0, len(records))
+	rejected := []entity.RejectedQuote{}
+	for i, record := range records {
+		quote, err := record()
+		if err != nil {
+			rejected = append(rejected, entity.RejectedQuote{Row: i + 1, Err: fmt.Errorf("%v: %w", err, entity.ErrBadRequest)})
+			continue
+		}
+		quotes = append(quotes, quote)
+		rows = append(rows, i+1)
+	}
+
+	return quotes, rows, rejected, nil
+}
+
+// readJSONQuotes splits a JSON array of quotes or a single quote into records parsed one by one, so a malformed
+// quote doesn't reject the rest
+func readJSONQuotes(body io.Reader) ([]func() (entity.StockQuote, error), error) {
+	reader := bufio.NewReader(body)
+	first, err := peekNonSpace(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", entity.ErrBadRequest)
+	}
+
+	var raw []json.RawMessage
+	decoder := json.NewDecoder(reader)
+	if first == '[' {
+		err = decoder.Decode(&raw)
+	} else {
+		raw = make([]json.RawMessage, 1)
+		err = decoder.Decode(&raw[0])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("body is not valid json: %w", entity.ErrBadRequest)
+	}
+
+	records := make([]func() (entity.StockQuote, error), len(raw))
+	for i := range raw {
+		message := raw[i]
+		records[i] = func() (entity.StockQuote, error) {
+			return repository.ParseJSONQuote(message)
+		}
+	}
+
+	return records, nil
+}
+
+// readCSVQuotes reads the records of a CSV body with a header row the same way the import subcommand reads CSV files,
+// see SaveStockQuotes for the columns
+func readCSVQuotes(body io.Reader) ([]func() (entity.StockQuote, error), error) {
+	reader, err := repository.NewQuoteReader(body, entity.FormatCSV)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, entity.ErrBadRequest)
+	}
+
+	var records []func() (entity.StockQuote, error)
+	for {
+		quote, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		var recordErr *repository.RecordError
+		if err != nil && !errors.As(err, &recordErr) {
+			// quoting errors leave the reader in an unknown state, so the body is rejected as a whole
+			return nil, fmt.Errorf("body is not valid csv: %v: %w", err, entity.ErrBadRequest)
+		}
+
+		records = append(records, func() (entity.StockQuote, error) {
+			if recordErr != nil {
+				return entity.StockQuote{}, recordErr.Err
+			}
+			return quote, nil
+		})
+	}
+}
+
+// peekNonSpace returns the first non-whitespace byte of the reader without consuming it
+func peekNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			return b[0], nil
+		}
+		reader.Discard(1)
+	}
+}
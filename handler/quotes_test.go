@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveStockQuotes_StatusCodes(t *testing.T) {
+	testCases := []struct {
+		name               string
+		controller         MockController
+		method             string
+		token              string
+		contentType        string
+		body               string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "Single JSON quote",
+			body:               `{"symbol":"UBER","datepoint":"2023-11-08T00:00:00Z","price":50.1}`,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "{\"stored\":1,\"rejected\":[]}\n",
+		},
+		{
+			name:        "JSON batch with per-row errors",
+			contentType: "application/json; charset=utf-8",
+			body: `[{"symbol":"UBER","datepoint":1699401600,"close":50.1},
+				{"symbol":"UBER","datepoint":"yesterday","price":50.1},
+				{"symbol":"UBER","date":"2023-11-09","price":5000},
+				{"symbol":"UBER","datepoint":"2023-11-10"},
+				"UBER"]`,
+			expectedStatusCode: http.StatusOK,
+			expectedBody: "{\"stored\":1,\"rejected\":[" +
+				"{\"row\":2,\"error\":\"datepoint \\\"yesterday\\\" has unknown format: bad request\"}," +
+				"{\"row\":3,\"error\":\"price too high: bad request\"}," +
+				"{\"row\":4,\"error\":\"price is missing: bad request\"}," +
+				"{\"row\":5,\"error\":\"quote is not a valid json object: bad request\"}]}\n",
+		},
+		{
+			name:        "CSV batch",
+			contentType: "text/csv",
+			body: "symbol,date,open,high,low,close,volume\n" +
+				"UBER,2023-11-08,49,51,48.5,50.1,1000\n" +
+				"UBER,2023-11-09,49,51,48.5,fifty,1000\n",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "{\"stored\":1,\"rejected\":[{\"row\":2,\"error\":\"price \\\"fifty\\\" is not a number: bad request\"}]}\n",
+		},
+		{
+			name:               "All quotes rejected",
+			body:               `[{"symbol":"UBER","datepoint":"2023-11-08","price":5000}]`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"stored\":0,\"rejected\":[{\"row\":1,\"error\":\"price too high: bad request\"}]}\n",
+		},
+		{
+			name:        "CSV with the same records as imported files",
+			contentType: "text/csv",
+			body: "Symbol,Datepoint,Price\n" +
+				"UBER,1699401600,50.1\n" +
+				",2023-11-09,50.1\n" +
+				"UBER,2023-11-10\n",
+			expectedStatusCode: http.StatusOK,
+			expectedBody: "{\"stored\":1,\"rejected\":[" +
+				"{\"row\":2,\"error\":\"symbol is empty: bad request\"}," +
+				"{\"row\":3,\"error\":\"price \\\"\\\" is not a number: bad request\"}]}\n",
+		},
+		{
+			name:               "CSV without price column",
+			contentType:        "text/csv",
+			body:               "symbol,date\nUBER,2023-11-08\n",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"price column is missing: bad request\"}\n",
+		},
+		{
+			name:               "Unsupported content type",
+			contentType:        "application/xml",
+			body:               "<quote/>",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"content type application/xml not supported, application/json or text/csv expected: bad request\"}\n",
+		},
+		{
+			name:               "Empty batch",
+			body:               "[]",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"no quotes posted: bad request\"}\n",
+		},
+		{
+			name:               "Wrong token",
+			token:              "guess",
+			body:               `{"symbol":"UBER","datepoint":"2023-11-08","price":50.1}`,
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedBody:       "{\"message\":\"missing or invalid ingestion token: unauthorized\"}\n",
+		},
+		{
+			name:               "Non POST request",
+			method:             "GET",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedBody:       "{\"message\":\"method GET not allowed: method not allowed\"}\n",
+		},
+		{
+			name:               "Storing failed",
+			controller:         MockController{err: errors.New("connection refused")},
+			body:               `{"symbol":"UBER","datepoint":"2023-11-08","price":50.1}`,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       "{\"message\":\"Internal server error\"}\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			method, token := tt.method, tt.token
+			if method == "" {
+				method = "POST"
+			}
+			if token == "" {
+				token = "secret"
+			}
+
+			req, err := http.NewRequest(method, "quotes", strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+token)
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			rr := httptest.NewRecorder()
+			StockPriceHandler{Controller: tt.controller, IngestToken: "secret"}.SaveStockQuotes(rr, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestSaveStockQuotes_NoTokenConfigured(t *testing.T) {
+	req, err := http.NewRequest("POST", "quotes", strings.NewReader(`{"symbol":"UBER","datepoint":"2023-11-08","price":50.1}`))
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer ")
+
+	rr := httptest.NewRecorder()
+	StockPriceHandler{Controller: MockController{}}.SaveStockQuotes(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, "Bearer", rr.Header().Get("WWW-Authenticate"))
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"stockpricews/entity"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// heartbeatInterval is the time after which an idle event stream gets a comment, so the proxies in between don't
+	// close the connection
+	heartbeatInterval = 15 * time.Second
+	// reconnectDelay is the time the browsers wait before they reconnect a closed event stream
+	reconnectDelay = 3 * time.Second
+
+	lastEventID = "Last-Event-ID"
+	// maxSecondsEventID is the largest Last-Event-ID taken for unix seconds, the ids issued before they got nanosecond
+	// precision; the ids in nanoseconds are larger for any date point after 1970-01-01T00:16:40Z
+	maxSecondsEventID = 1e12
+)
+
+// QuoteStream is Server-Sent Events HTTP handler that pushes to client every new quote of a symbol as it's stored.
+// Usage: EventSource GET /stream/quotes?symbol=<STOCK_SYMBOL>[&begin=<begin_time>]
+// Every quote is a 'quote' event with the date point in unix nanoseconds as its id and the quote as json data. A client
+// reconnecting with the Last-Event-ID header, as browsers do on their own, gets the quotes dated after that one replayed
+// from the repository first; the optional begin param replays the quotes stored after it the same way. As the quotes are
+// pushed as they're stored, whatever their date point, the ids aren't monotonic: a quote backfilled before the last event
+// isn't replayed on resume, while the quotes after it are replayed again. A client that
+// can't keep up is dropped with a 'dropped' event rather than stalling the others, it resumes from its last event when it
+// reconnects. Errors before the stream starts are reported with the same status codes as MaxProfitForPeriod, the symbol
+// must be in the catalog even if it has no quotes yet.
+func (h StockPriceHandler) QuoteStream(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	req, err := parseQuoteStreamRequest(r)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondWithError(fmt.Errorf("response writer can't be flushed"), w)
+		return
+	}
+
+	// the deadline bounds loading the stored quotes only, the subscription lasts as long as the connection
+	ctx, cancelLoad := h.requestContext(r)
+	err = h.checkSymbol(ctx, req.Symbol)
+	var quotes <-chan entity.StockQuote
+	var cancel func()
+	if err == nil {
+		quotes, cancel, err = h.Controller.QuoteUpdates(ctx, req)
+	}
+	cancelLoad()
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// nginx and the proxies following its convention buffer the responses unless told otherwise
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", reconnectDelay.Milliseconds())
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case quote, ok := <-quotes:
+			if !ok {
+				// the subscription was dropped as the client can't keep up
+				fmt.Fprint(w, "event: dropped\ndata: {\"message\":\"too slow\"}\n\n")
+				flusher.Flush()
+				return
+			}
+			data, err := json.Marshal(exported(quote))
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: quote\ndata: %s\n\n", quote.Datepoint.UnixNano(), data)
+			heartbeat.Reset(heartbeatInterval)
+		}
+		flusher.Flush()
+	}
+}
+
+// parseQuoteStreamRequest returns the symbol of the stream and the time point the stored quotes are replayed after,
+// the Last-Event-ID header takes precedence over the begin param
+func parseQuoteStreamRequest(r *http.Request) (entity.StockQuoteRequest, error) {
+	req, err := parseStreamRequest(r)
+	if err != nil {
+		return entity.StockQuoteRequest{}, err
+	}
+
+	if id := strings.TrimSpace(r.Header.Get(lastEventID)); id != "" {
+		nanos, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return entity.StockQuoteRequest{}, fmt.Errorf("%s header can't be parsed as nanoseconds: %w", lastEventID, entity.ErrBadRequest)
+		}
+		if nanos < maxSecondsEventID {
+			req.Begin = time.Unix(nanos, 0).UTC()
+		} else {
+			req.Begin = time.Unix(0, nanos).UTC()
+		}
+	}
+
+	return req, nil
+}
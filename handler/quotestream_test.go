@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"stockpricews/controller"
+	"stockpricews/entity"
+	"stockpricews/repository"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readEvent reads the lines of the next event of the stream up to the blank line ending it
+func readEvent(t *testing.T, reader *bufio.Reader) []string {
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		assert.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestQuoteStream(t *testing.T) {
+	quotes := make(chan entity.StockQuote, 1)
+	handler := StockPriceHandler{Controller: MockController{quotes: quotes}}
+	server := httptest.NewServer(http.HandlerFunc(handler.QuoteStream))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/stream/quotes?symbol=UBER")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+
+	reader := bufio.NewReader(resp.Body)
+	assert.Equal(t, []string{"retry: 3000"}, readEvent(t, reader))
+
+	quotes <- entity.StockQuote{Symbol: "UBER", Datepoint: time.Unix(1699228800, 0), Price: 48.14, Open: 47, High: 50.5, Low: 46.8, Volume: 1000}
+	assert.Equal(t, []string{
+		"id: 1699228800000000000",
+		"event: quote",
+		`data: {"symbol":"UBER","datepoint":"2023-11-06T00:00:00Z","price":48.14,"open":47,"high":50.5,"low":46.8,"volume":1000}`,
+	}, readEvent(t, reader))
+
+	// the stream ends once the subscription is dropped
+	close(quotes)
+	assert.Equal(t, []string{"event: dropped", `data: {"message":"too slow"}`}, readEvent(t, reader))
+	_, err = reader.ReadString('\n')
+	assert.Error(t, err)
+}
+
+func TestQuoteStream_Resume(t *testing.T) {
+	day := time.Now().Add(-72 * time.Hour).Truncate(time.Second)
+	c := controller.New(repository.NewMemory(
+		entity.StockQuote{Symbol: "UBER", Datepoint: day, Price: 10},
+		entity.StockQuote{Symbol: "UBER", Datepoint: day.AddDate(0, 0, 1), Price: 8},
+		entity.StockQuote{Symbol: "UBER", Datepoint: day.AddDate(0, 0, 2), Price: 12},
+	))
+	c.Feed = controller.NewQuoteFeed()
+	server := httptest.NewServer(http.HandlerFunc(StockPriceHandler{Controller: c}.QuoteStream))
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL+"/stream/quotes?symbol=UBER", nil)
+	assert.NoError(t, err)
+	// the browsers resume the stream with the id of the last event they got
+	req.Header.Set("Last-Event-ID", fmt.Sprint(day.UnixNano()))
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent(t, reader)
+	for _, expected := range []time.Time{day.AddDate(0, 0, 1), day.AddDate(0, 0, 2)} {
+		assert.Equal(t, fmt.Sprintf("id: %d", expected.UnixNano()), readEvent(t, reader)[0])
+	}
+
+	// the quotes stored from now on follow the replayed ones
+	ingestion, err := c.SaveStockQuotes(req.Context(), []entity.StockQuote{{Symbol: "UBER", Datepoint: time.Now(), Price: 13}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ingestion.Stored)
+	event := readEvent(t, reader)
+	assert.Equal(t, "event: quote", event[1])
+	assert.Contains(t, event[2], `"price":13`)
+}
+
+func TestQuoteStream_StatusCodes(t *testing.T) {
+	testCases := []struct {
+		name               string
+		controller         MockController
+		method             string
+		url                string
+		lastEventID        string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "Symbol param is missing",
+			url:                "/stream/quotes",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"stock symbol must be between 1 and 16 chars long: bad request\"}\n",
+		},
+		{
+			name:               "Last event id can't be parsed",
+			url:                "/stream/quotes?symbol=UBER",
+			lastEventID:        "2023-11-06",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"Last-Event-ID header can't be parsed as nanoseconds: bad request\"}\n",
+		},
+		{
+			name:               "Unknown symbol",
+			url:                "/stream/quotes?symbol=UNKN",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "{\"message\":\"unknown symbol UNKN: not found\"}\n",
+		},
+		{
+			name:               "Streaming is not enabled",
+			controller:         MockController{err: entity.ErrNotFound},
+			url:                "/stream/quotes?symbol=UBER",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "{\"message\":\"not found\"}\n",
+		},
+		{
+			name:               "Method not allowed",
+			method:             "POST",
+			url:                "/stream/quotes?symbol=UBER",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedBody:       "{\"message\":\"method POST not allowed: method not allowed\"}\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = "GET"
+			}
+			req := httptest.NewRequest(method, tt.url, nil)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+
+			rr := httptest.NewRecorder()
+			StockPriceHandler{Controller: tt.controller}.QuoteStream(rr, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestParseQuoteStreamRequest_LastEventID(t *testing.T) {
+	testCases := []struct {
+		name        string
+		lastEventID string
+		expected    time.Time
+	}{
+		// quotes within the same second resume after the exact date point
+		{name: "Nanoseconds", lastEventID: "1699228800500000000", expected: time.Date(2023, 11, 6, 0, 0, 0, 500000000, time.UTC)},
+		{name: "Seconds of earlier versions", lastEventID: "1699228800", expected: time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/stream/quotes?symbol=UBER", nil)
+			req.Header.Set("Last-Event-ID", tt.lastEventID)
+			got, err := parseQuoteStreamRequest(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got.Begin)
+		})
+	}
+}
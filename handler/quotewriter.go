@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"stockpricews/entity"
+	"strconv"
+	"time"
+)
+
+// exportedQuote is a written quote, the fields are named like the columns of the imported files so exports can be
+// imported again
+type exportedQuote struct {
+	Symbol    string    `json:"symbol"`
+	Datepoint time.Time `json:"datepoint"`
+	Price     float64   `json:"price"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Volume    int64     `json:"volume"`
+}
+
+// QuoteWriter writes quotes one at a time in CSV, JSON lines or JSON format, the date points are written as RFC3339
+// in UTC. Close must be called once all quotes are written to complete the document.
+type QuoteWriter struct {
+	write func(entity.StockQuote) error
+	close func() error
+}
+
+// NewQuoteWriter starts writing the quotes in the given format, the header row of CSV files is written with the
+// first quote or on Close
+func NewQuoteWriter(w io.Writer, format entity.QuoteFormat) (*QuoteWriter, error) {
+	switch format {
+	case entity.FormatCSV:
+		return newCSVQuoteWriter(w), nil
+	case entity.FormatJSONLines:
+		encoder := json.NewEncoder(w)
+		return &QuoteWriter{
+			write: func(quote entity.StockQuote) error { return encoder.Encode(exported(quote)) },
+			close: func() error { return nil },
+		}, nil
+	case entity.FormatJSON:
+		return newJSONQuoteWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported quote format %s, csv, jsonl or json expected", format)
+	}
+}
+
+// Write writes the quote
+func (w *QuoteWriter) Write(quote entity.StockQuote) error {
+	return w.write(quote)
+}
+
+// Close completes the document, it doesn't close the underlying writer
+func (w *QuoteWriter) Close() error {
+	return w.close()
+}
+
+func exported(quote entity.StockQuote) exportedQuote {
+	return exportedQuote{Symbol: quote.Symbol, Datepoint: quote.Datepoint.UTC(), Price: quote.Price, Open: quote.Open,
+		High: quote.High, Low: quote.Low, Volume: quote.Volume}
+}
+
+func newCSVQuoteWriter(w io.Writer) *QuoteWriter {
+	writer := csv.NewWriter(w)
+	header := false
+	writeHeader := func() error {
+		if header {
+			return nil
+		}
+		header = true
+		return writer.Write([]string{"symbol", "datepoint", "price", "open", "high", "low", "volume"})
+	}
+	price := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	return &QuoteWriter{
+		write: func(quote entity.StockQuote) error {
+			if err := writeHeader(); err != nil {
+				return err
+			}
+			return writer.Write([]string{quote.Symbol, quote.Datepoint.UTC().Format(time.RFC3339), price(quote.Price),
+				price(quote.Open), price(quote.High), price(quote.Low), strconv.FormatInt(quote.Volume, 10)})
+		},
+		close: func() error {
+			if err := writeHeader(); err != nil {
+				return err
+			}
+			writer.Flush()
+			return writer.Error()
+		},
+	}
+}
+
+func newJSONQuoteWriter(w io.Writer) *QuoteWriter {
+	separator := "["
+	return &QuoteWriter{
+		write: func(quote entity.StockQuote) error {
+			content, err := json.Marshal(exported(quote))
+			if err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, separator); err != nil {
+				return err
+			}
+			separator = ","
+			_, err = w.Write(content)
+			return err
+		},
+		close: func() error {
+			end := "]\n"
+			// an empty export is an empty array rather than no document at all
+			if separator == "[" {
+				end = "[]\n"
+			}
+			_, err := io.WriteString(w, end)
+			return err
+		},
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimits holds a token bucket per endpoint, shared by the REST and gRPC APIs so an endpoint has the same capacity
+// whichever API it's called by. Reading and writing the same path are separate endpoints, so heavy readers don't
+// throttle the writers and vice versa.
+type RateLimits struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewRateLimits initializes RateLimits without any bucket, they're created on the first use of their endpoint
+func NewRateLimits() *RateLimits {
+	return &RateLimits{limiters: make(map[string]*rate.Limiter)}
+}
+
+// Limiter returns the token bucket of the endpoint, named by the method and the path of the REST API like
+// GET /quotes, see Endpoint
+func (l *RateLimits) Limiter(endpoint string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.limiters[endpoint]
+	if !ok {
+		limiter = rate.NewLimiter(2, 4)
+		l.limiters[endpoint] = limiter
+	}
+	return limiter
+}
+
+// Endpoint names the endpoint the request of the method to the path counts against. POST requests write, any other
+// method shares the bucket of the reads, so the clients can't create buckets at will.
+func Endpoint(method, path string) string {
+	if method == http.MethodPost {
+		return http.MethodPost + " " + path
+	}
+	return http.MethodGet + " " + path
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimits(t *testing.T) {
+	limits := NewRateLimits()
+	router := StockPriceHandler{Controller: MockController{}, IngestToken: "secret", Limits: limits}.Router()
+	serve := func(method, url string) int {
+		req := httptest.NewRequest(method, url, strings.NewReader(`[]`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// the exports used up the bucket of the reads, posting quotes has its own
+	for limits.Limiter(Endpoint(http.MethodGet, "/quotes")).Allow() {
+	}
+	assert.Equal(t, http.StatusTooManyRequests, serve("GET", "/v1/quotes?symbol=UBER&begin=1699228800&end=1699401600"))
+	assert.Equal(t, http.StatusUnauthorized, serve("POST", "/v1/quotes"))
+
+	// the other methods count as reads
+	assert.Equal(t, http.StatusTooManyRequests, serve("DELETE", "/quotes"))
+	assert.Equal(t, "GET /quotes", Endpoint("PROPFIND", "/quotes"))
+}
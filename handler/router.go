@@ -0,0 +1,52 @@
+package handler
+
+import "net/http"
+
+// apiVersion prefixes the routes of the current version of the API
+const apiVersion = "/v1"
+
+// route is an endpoint of the API, path is relative to the version prefix
+type route struct {
+	path    string
+	handler func(w http.ResponseWriter, r *http.Request)
+}
+
+// routes lists every endpoint of the API, the OpenAPI document describes the same paths
+func (h StockPriceHandler) routes() []route {
+	return []route{
+		{"/maxprofit", h.MaxProfitForPeriod},
+		{"/maxprofit/top", h.TopTradeWindows},
+		{"/maxprofit/leaderboard", h.SymbolLeaderboard},
+		{"/maxprofit/stream", h.MaxProfitStream},
+		{"/quotes", h.Quotes},
+		{"/symbols", h.Symbols},
+		{"/symbols/", h.DescribeSymbol},
+		{"/stream/quotes", h.QuoteStream},
+		{"/graphql", h.GraphQL},
+	}
+}
+
+// Router returns the handler of the API: the endpoints under the /v1 prefix and the OpenAPI document describing them
+// at /v1/openapi.json. The endpoints are served without the prefix as well, so the clients predating the versioned API
+// keep working. Every endpoint is rate limited on its own, whichever path or API it's called by, the reads and the
+// writes of a path separately.
+func (h StockPriceHandler) Router() http.Handler {
+	limits := h.Limits
+	if limits == nil {
+		limits = NewRateLimits()
+	}
+
+	api := http.NewServeMux()
+	for _, rt := range h.routes() {
+		api.Handle(rt.path, rateLimiter(limits, rt.path, rt.handler))
+	}
+
+	v1 := http.NewServeMux()
+	v1.Handle("/", api)
+	v1.HandleFunc("/openapi.json", h.OpenAPI)
+
+	router := http.NewServeMux()
+	router.Handle(apiVersion+"/", http.StripPrefix(apiVersion, v1))
+	router.Handle("/", api)
+	return router
+}
@@ -1,12 +1,13 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"golang.org/x/time/rate"
 	_ "golang.org/x/time/rate"
 	"net/http"
+	"net/url"
 	"stockpricews/controller"
 	"stockpricews/entity"
 	"strconv"
@@ -14,31 +15,67 @@ import (
 )
 
 const (
-	begin  = "begin"
-	end    = "end"
-	symbol = "symbol"
+	begin        = "begin"
+	end          = "end"
+	symbol       = "symbol"
+	transactions = "k"
+	fee          = "fee"
+	feeType      = "feeType"
+	cooldown     = "cooldown"
+	direction    = "direction"
+	shares       = "shares"
+	adjusted     = "adjusted"
+	capital      = "capital"
+	field        = "field"
+
+	feeTypeAbsolute = "absolute"
+	feeTypePercent  = "percent"
+
+	// upper bound of the k param as the computation needs O(n*k) memory
+	maxTransactions = 100
 )
 
 type StockPriceHandler struct {
 	Controller controller.Controller
+	// Timeout bounds the computation of a single request, zero means no deadline besides the client going away
+	Timeout time.Duration
+	// IngestToken is the bearer token the clients posting quotes authenticate with, empty disables posting quotes
+	IngestToken string
+	// Limits are the token buckets of the endpoints, shared with the gRPC API. The router creates its own if nil.
+	Limits *RateLimits
 }
 
-// New initializes new StockPriceHandler that currently provides just one REST endpoint 'GET /maxprofit'
-func New(controller controller.Controller, port int) (StockPriceHandler, error) {
-	handerImpl := StockPriceHandler{Controller: controller}
-	http.Handle("/maxprofit", rateLimiter(handerImpl.MaxProfitForPeriod))
-	err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
+// New initializes new StockPriceHandler and serves its Router: the REST endpoints 'GET /v1/maxprofit',
+// 'GET /v1/maxprofit/top', 'GET /v1/maxprofit/leaderboard', 'GET /v1/quotes', 'POST /v1/quotes', 'GET /v1/symbols',
+// 'POST /v1/symbols' and 'GET /v1/symbols/{symbol}', the GraphQL endpoint '/v1/graphql', the WebSocket endpoint
+// 'GET /v1/maxprofit/stream', the Server-Sent Events endpoint 'GET /v1/stream/quotes' and the OpenAPI document
+// 'GET /v1/openapi.json'. Every request is computed within the given timeout, quotes and symbols are accepted
+// from the clients authenticated with the ingestion token. The endpoints are rate limited by the given limits. The debug
+// endpoints registered on http.DefaultServeMux, like the expvar counters at /debug/vars, are served as well.
+func New(controller controller.Controller, port int, timeout time.Duration, ingestToken string, limits *RateLimits) (StockPriceHandler, error) {
+	handerImpl := StockPriceHandler{Controller: controller, Timeout: timeout, IngestToken: ingestToken, Limits: limits}
+	mux := http.NewServeMux()
+	mux.Handle("/", handerImpl.Router())
+	mux.Handle("/debug/", http.DefaultServeMux)
+	err := http.ListenAndServe(fmt.Sprintf(":%d", port), mux)
 	return handerImpl, err
 }
 
 // MaxProfitForPeriod is HTTP handler that returns to client the maximum profit that could be realized within given time slice.
-// Usage: curl GET /maxprofit?begin=<begin_time_in_seconds>&end=<end_time_in_seconds>&symbol=<STOCK_SYMBOL>
+// Usage: curl GET /maxprofit?begin=<begin_time>&end=<end_time>&symbol=<STOCK_SYMBOL>[&k=<max_transactions>][&direction=long|short]
+// [&shares=<number_of_shares>|&capital=<invested_amount>][&adjusted=true][&field=open|high|low|close|optimistic|pessimistic]
+// or with trading costs and unlimited transactions:
+// curl GET /maxprofit?begin=<..>&end=<..>&symbol=<..>&fee=<fee>[&feeType=absolute|percent][&cooldown=<seconds>]
+// The begin and end times are unix seconds, RFC 3339, dates, now, today, ytd or relative to now like -30d, the dates
+// without an offset are read in the optional tz param zone (UTC by default). The response dates are in UTC.
 // Result status codes:
 //  - 200 OK - when a profit can be realized within the given time slice. Body contains entity.MaxProfitPoints as json
-//  - 400 Bad Request - if any of the query params is not passed or doesn't have a correct format (seconds). Body contains entity.ErrorMessage as json so the client can handle it accordingly
-//  - 404 Not Found - if stock quote data can't be found for the given time slice or it's not possible to realize a profit. Body contains entity.ErrorMessage as json so the client can handle it accordingly
+//    or entity.MultiTradeProfit if more than one transaction (k > 1) is allowed or trading costs are passed
+//  - 400 Bad Request - if any of the query params is not passed or doesn't have a correct format (see parseTimeParam). Body contains entity.ErrorMessage as json so the client can handle it accordingly
+//  - 404 Not Found - if the symbol isn't in the catalog, stock quote data can't be found for the given time slice or it's not possible to realize a profit. Body contains entity.ErrorMessage as json so the client can handle it accordingly
 //  - 429 Too Many Requests if the client got rate limited.
 //  - 500 Intenal Server Error - if any expected error occur.
+//  - 504 Gateway Timeout - if the stock quotes couldn't be loaded within the request timeout.
 func (h StockPriceHandler) MaxProfitForPeriod(w http.ResponseWriter, r *http.Request) {
 	// Access-Control-Allow-Origin is set as the client might run in a separate machine
 	w.Header().Set("Content-Type", "application/json")
@@ -51,8 +88,63 @@ func (h StockPriceHandler) MaxProfitForPeriod(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	k, err := parseTransactions(r)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	costs, withCosts, err := parseTradeCosts(r)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	if withCosts && r.URL.Query().Has(transactions) {
+		respondWithError(fmt.Errorf("%s param can't be combined with trading costs: %w", transactions, entity.ErrBadRequest), w)
+		return
+	}
+
+	tradeDirection, err := parseDirection(r)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	if tradeDirection == entity.DirectionShort && (withCosts || k > 1) {
+		respondWithError(fmt.Errorf("short %s supports a single transaction only: %w", direction, entity.ErrBadRequest), w)
+		return
+	}
+
+	position, withPosition, err := parsePosition(r)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	if withPosition && (withCosts || k > 1) {
+		respondWithError(fmt.Errorf("position size supports a single transaction only: %w", entity.ErrBadRequest), w)
+		return
+	}
+
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	if err := h.checkSymbol(ctx, timeSlice.Symbol); err != nil {
+		respondWithError(err, w)
+		return
+	}
+
 	// Calculate max profit for the given time slice and report error if any
-	maxProfitPrices, err := h.Controller.MaxProfitForPeriod(timeSlice)
+	var maxProfit interface{}
+	switch {
+	case withCosts:
+		maxProfit, err = h.Controller.MaxProfitWithCosts(ctx, timeSlice, costs)
+	case k > 1:
+		maxProfit, err = h.Controller.MaxProfitForTransactions(ctx, timeSlice, k)
+	default:
+		maxProfit, err = h.maxProfitForPosition(ctx, timeSlice, tradeDirection, position, withPosition)
+	}
 	if err != nil {
 		respondWithError(err, w)
 		return
@@ -60,15 +152,50 @@ func (h StockPriceHandler) MaxProfitForPeriod(w http.ResponseWriter, r *http.Req
 
 	// Marshal the response to JSON and report successful execution
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(maxProfitPrices)
+	json.NewEncoder(w).Encode(maxProfit)
+}
+
+// maxProfitForPosition calculates the single transaction max profit in the given direction and the profit of the
+// position if its size is passed
+func (h StockPriceHandler) maxProfitForPosition(ctx context.Context, timeSlice entity.StockQuoteRequest, tradeDirection entity.Direction,
+	position entity.Position, withPosition bool) (entity.MaxProfitPoints, error) {
+	var points entity.MaxProfitPoints
+	var err error
+	if tradeDirection == entity.DirectionShort {
+		points, err = h.Controller.MaxShortProfitForPeriod(ctx, timeSlice)
+	} else {
+		points, err = h.Controller.MaxProfitForPeriod(ctx, timeSlice)
+	}
+	if err != nil || !withPosition {
+		return points, err
+	}
+
+	return h.Controller.WithPosition(points, position)
+}
+
+// checkSymbol reports entity.ErrNotFound if the symbol isn't in the catalog, so the unknown symbols are told apart
+// from the known ones without quotes in the time slice before any quote is loaded. The lookups are served by the
+// repository cache, unknown symbols included.
+func (h StockPriceHandler) checkSymbol(ctx context.Context, stockSymbol string) error {
+	_, err := h.Controller.DescribeSymbol(ctx, stockSymbol)
+	return err
+}
+
+// requestContext returns the context of the request bounded by the handler timeout, so the computation and the queries
+// behind it stop once the client goes away or the deadline expires
+func (h StockPriceHandler) requestContext(r *http.Request) (context.Context, context.CancelFunc) {
+	if h.Timeout <= 0 {
+		return context.WithCancel(r.Context())
+	}
+	return context.WithTimeout(r.Context(), h.Timeout)
 }
 
-// Simple rate limiting using Token Bucket
-func rateLimiter(next func(w http.ResponseWriter, r *http.Request)) http.Handler {
-	limiter := rate.NewLimiter(2, 4)
+// Simple rate limiting using Token Bucket, the bucket is picked by the method of the request to the path
+func rateLimiter(limits *RateLimits, path string, next func(w http.ResponseWriter, r *http.Request)) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !limiter.Allow() {
+		if !limits.Limiter(Endpoint(r.Method, path)).Allow() {
 			errMsg := entity.ErrorMessage{Message: "The API is at capacity, try again later."}
+			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusTooManyRequests)
 			json.NewEncoder(w).Encode(errMsg)
 			return
@@ -78,7 +205,34 @@ func rateLimiter(next func(w http.ResponseWriter, r *http.Request)) http.Handler
 	})
 }
 
+// ParseQuoteRequest validates the symbol, begin and end params the same way as the REST endpoints do, so the
+// subcommands taking them accept the same values
+func ParseQuoteRequest(params url.Values) (entity.StockQuoteRequest, error) {
+	return parseRequestData(&http.Request{Method: http.MethodGet, URL: &url.URL{RawQuery: params.Encode()}})
+}
+
 func parseRequestData(r *http.Request) (entity.StockQuoteRequest, error) {
+	timeSlice, err := parseTimeSlice(r)
+	if err != nil {
+		return entity.StockQuoteRequest{}, err
+	}
+
+	if !r.URL.Query().Has(symbol) {
+		return entity.StockQuoteRequest{}, fmt.Errorf("%s param is missing: %w", symbol, entity.ErrBadRequest)
+	}
+
+	stockSymbol := r.URL.Query().Get(symbol)
+	if err := validateSymbol(stockSymbol); err != nil {
+		return entity.StockQuoteRequest{}, err
+	}
+	timeSlice.Symbol = stockSymbol
+
+	return timeSlice, nil
+}
+
+// parseTimeSlice parses the begin and end params of the request in any of the formats of parseTimeParam, the dates
+// without an offset are read in the tz param zone. The returned time slice is in UTC, its symbol is left empty.
+func parseTimeSlice(r *http.Request) (entity.StockQuoteRequest, error) {
 	if r == nil || r.URL == nil {
 		return entity.StockQuoteRequest{}, fmt.Errorf("failed to read request URL: %w", entity.ErrBadRequest)
 	}
@@ -95,52 +249,175 @@ func parseRequestData(r *http.Request) (entity.StockQuoteRequest, error) {
 		return entity.StockQuoteRequest{}, fmt.Errorf("%s param is missing: %w", end, entity.ErrBadRequest)
 	}
 
-	if !r.URL.Query().Has(symbol) {
-		return entity.StockQuoteRequest{}, fmt.Errorf("%s param is missing: %w", symbol, entity.ErrBadRequest)
+	loc, err := parseLocation(r)
+	if err != nil {
+		return entity.StockQuoteRequest{}, err
 	}
 
-	beginSecs, err := strconv.ParseInt(r.URL.Query().Get(begin), 10, 64)
-	if err != nil {
-		return entity.StockQuoteRequest{}, fmt.Errorf("%s param can't be parsed as seconds: %w", begin, entity.ErrBadRequest)
+	// both boundaries are relative to the same now, so '-1d' and 'now' make a day long time slice
+	now := time.Now()
+	var timeSlice entity.StockQuoteRequest
+	if timeSlice.Begin, err = parseTimeParam(begin, r.URL.Query().Get(begin), loc, now); err != nil {
+		return entity.StockQuoteRequest{}, err
 	}
 
-	endSecs, err := strconv.ParseInt(r.URL.Query().Get("end"), 10, 64)
-	if err != nil {
-		return entity.StockQuoteRequest{}, fmt.Errorf("%s param can't be parsed as seconds: %w", end, entity.ErrBadRequest)
+	if timeSlice.End, err = parseTimeParam(end, r.URL.Query().Get(end), loc, now); err != nil {
+		return entity.StockQuoteRequest{}, err
 	}
 
-	timeSlice := entity.StockQuoteRequest{Begin: time.Unix(beginSecs, 0), End: time.Unix(endSecs, 0)}
 	if timeSlice.Begin.After(timeSlice.End) {
 		return entity.StockQuoteRequest{}, fmt.Errorf("begin period is after the end period: %w", entity.ErrBadRequest)
 	}
 
-	stockSymbol := r.URL.Query().Get(symbol)
-	if len(stockSymbol) < 1 || len(stockSymbol) > 4 {
-		return entity.StockQuoteRequest{}, fmt.Errorf("stock symbol must be between 1 and 4 chars long: %w", entity.ErrBadRequest)
+	if r.URL.Query().Has(adjusted) {
+		if timeSlice.Adjusted, err = strconv.ParseBool(r.URL.Query().Get(adjusted)); err != nil {
+			return entity.StockQuoteRequest{}, fmt.Errorf("%s param can't be parsed as boolean: %w", adjusted, entity.ErrBadRequest)
+		}
+	}
+
+	if r.URL.Query().Has(field) {
+		timeSlice.Field = entity.PriceField(r.URL.Query().Get(field))
+		switch timeSlice.Field {
+		case entity.FieldOpen, entity.FieldHigh, entity.FieldLow, entity.FieldClose, entity.FieldOptimistic, entity.FieldPessimistic:
+		default:
+			return entity.StockQuoteRequest{}, fmt.Errorf("%s param must be one of open, high, low, close, optimistic or pessimistic: %w",
+				field, entity.ErrBadRequest)
+		}
 	}
-	timeSlice.Symbol = stockSymbol
 
 	return timeSlice, nil
 }
 
+func validateSymbol(stockSymbol string) error {
+	return controller.ValidateSymbol(stockSymbol)
+}
+
+// parseTransactions returns the max number of transactions requested by the client, defaults to 1 if k is not passed
+func parseTransactions(r *http.Request) (int, error) {
+	if !r.URL.Query().Has(transactions) {
+		return 1, nil
+	}
+
+	k, err := strconv.Atoi(r.URL.Query().Get(transactions))
+	if err != nil {
+		return 0, fmt.Errorf("%s param can't be parsed as integer: %w", transactions, entity.ErrBadRequest)
+	}
+
+	if k < 1 || k > maxTransactions {
+		return 0, fmt.Errorf("%s param must be between 1 and %d: %w", transactions, maxTransactions, entity.ErrBadRequest)
+	}
+
+	return k, nil
+}
+
+// parseTradeCosts returns the fee and cooldown passed by the client and whether any of them is present at all
+func parseTradeCosts(r *http.Request) (entity.TradeCosts, bool, error) {
+	query := r.URL.Query()
+	if query.Has(feeType) && !query.Has(fee) {
+		return entity.TradeCosts{}, false, fmt.Errorf("%s param requires the %s param: %w", feeType, fee, entity.ErrBadRequest)
+	}
+	if !query.Has(fee) && !query.Has(cooldown) {
+		return entity.TradeCosts{}, false, nil
+	}
+
+	costs := entity.TradeCosts{}
+	if query.Has(fee) {
+		feeValue, err := strconv.ParseFloat(query.Get(fee), 64)
+		if err != nil || feeValue < 0 {
+			return entity.TradeCosts{}, false, fmt.Errorf("%s param must be a non-negative number: %w", fee, entity.ErrBadRequest)
+		}
+		costs.Fee = feeValue
+	}
+
+	switch query.Get(feeType) {
+	case "", feeTypeAbsolute:
+	case feeTypePercent:
+		if costs.Fee >= 100 {
+			return entity.TradeCosts{}, false, fmt.Errorf("%s param must be less than 100 percent: %w", fee, entity.ErrBadRequest)
+		}
+		costs.FeePercent = true
+	default:
+		return entity.TradeCosts{}, false, fmt.Errorf("%s param must be either %s or %s: %w", feeType, feeTypeAbsolute, feeTypePercent, entity.ErrBadRequest)
+	}
+
+	if query.Has(cooldown) {
+		cooldownSecs, err := strconv.ParseInt(query.Get(cooldown), 10, 64)
+		if err != nil || cooldownSecs < 0 {
+			return entity.TradeCosts{}, false, fmt.Errorf("%s param must be a non-negative number of seconds: %w", cooldown, entity.ErrBadRequest)
+		}
+		costs.Cooldown = time.Duration(cooldownSecs) * time.Second
+	}
+
+	return costs, true, nil
+}
+
+// parseDirection returns the trade direction requested by the client, defaults to long if direction is not passed
+func parseDirection(r *http.Request) (entity.Direction, error) {
+	switch d := entity.Direction(r.URL.Query().Get(direction)); d {
+	case "", entity.DirectionLong:
+		return entity.DirectionLong, nil
+	case entity.DirectionShort:
+		return d, nil
+	default:
+		return "", fmt.Errorf("%s param must be either %s or %s: %w", direction, entity.DirectionLong, entity.DirectionShort, entity.ErrBadRequest)
+	}
+}
+
+// parsePosition returns the position size passed by the client either as number of shares or capital
+func parsePosition(r *http.Request) (entity.Position, bool, error) {
+	query := r.URL.Query()
+	if !query.Has(shares) && !query.Has(capital) {
+		return entity.Position{}, false, nil
+	}
+
+	if query.Has(shares) && query.Has(capital) {
+		return entity.Position{}, false, fmt.Errorf("%s and %s params can't be combined: %w", shares, capital, entity.ErrBadRequest)
+	}
+
+	param := shares
+	if query.Has(capital) {
+		param = capital
+	}
+
+	size, err := strconv.ParseFloat(query.Get(param), 64)
+	if err != nil || size <= 0 {
+		return entity.Position{}, false, fmt.Errorf("%s param must be a positive number: %w", param, entity.ErrBadRequest)
+	}
+
+	if param == shares {
+		return entity.Position{Shares: size}, true, nil
+	}
+	return entity.Position{Capital: size}, true, nil
+}
+
 func respondWithError(err error, w http.ResponseWriter) {
 	// log the error at the server log for debug purposes
 	fmt.Println(err)
 
-	statusCode := 200
-	errMsg := err.Error()
-	if errors.Is(err, entity.ErrBadRequest) {
-		statusCode = http.StatusBadRequest
-	} else if errors.Is(err, entity.ErrNotFound) {
-		statusCode = http.StatusNotFound
-	} else if errors.Is(err, entity.ErrMethodNotAllowed) {
-		statusCode = http.StatusMethodNotAllowed
-	} else {
-		// we don't want to leak internal messages to the client
-		statusCode = http.StatusInternalServerError
-		errMsg = "Internal server error"
-	}
-
+	statusCode, errMsg := errorStatus(err)
 	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(entity.ErrorMessage{Message: errMsg})
 }
+
+// errorStatus maps the error to HTTP status code and the message that is safe to be reported to the client
+func errorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, entity.ErrBadRequest):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, entity.ErrNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, entity.ErrMethodNotAllowed):
+		return http.StatusMethodNotAllowed, err.Error()
+	case errors.Is(err, entity.ErrUnauthorized):
+		return http.StatusUnauthorized, err.Error()
+	case errors.Is(err, entity.ErrNotAcceptable):
+		return http.StatusNotAcceptable, err.Error()
+	case errors.Is(err, entity.ErrConflict):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, "Request timed out"
+	default:
+		// we don't want to leak internal messages to the client
+		return http.StatusInternalServerError, "Internal server error"
+	}
+}
@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http/httptest"
 	"time"
 
@@ -9,7 +12,9 @@ import (
 
 	"net/http"
 	"net/url"
+	"stockpricews/controller"
 	"stockpricews/entity"
+	"stockpricews/repository"
 
 	"testing"
 )
@@ -71,10 +76,47 @@ func TestParseRequestData(t *testing.T) {
 			req:         &http.Request{URL: &url.URL{RawQuery: "begin=2699228800&end=1699228800&symbol=TESLA"}, Method: "GET"},
 			expectedErr: entity.ErrBadRequest,
 		},
+		{
+			name:        "Adjusted param can't be parsed",
+			req:         &http.Request{URL: &url.URL{RawQuery: "begin=1699228800&end=2699228800&symbol=UBER&adjusted=maybe"}, Method: "GET"},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:     "URL with adjusted prices successfully parsed",
+			req:      &http.Request{URL: &url.URL{RawQuery: "begin=1699228800&end=2699228800&symbol=UBER&adjusted=true"}, Method: "GET"},
+			expected: entity.StockQuoteRequest{Symbol: "UBER", Begin: time.Unix(1699228800, 0).UTC(), End: time.Unix(2699228800, 0).UTC(), Adjusted: true},
+		},
+		{
+			name:        "Unknown price field",
+			req:         &http.Request{URL: &url.URL{RawQuery: "begin=1699228800&end=2699228800&symbol=UBER&field=vwap"}, Method: "GET"},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:     "URL with price field successfully parsed",
+			req:      &http.Request{URL: &url.URL{RawQuery: "begin=1699228800&end=2699228800&symbol=UBER&field=optimistic"}, Method: "GET"},
+			expected: entity.StockQuoteRequest{Symbol: "UBER", Begin: time.Unix(1699228800, 0).UTC(), End: time.Unix(2699228800, 0).UTC(), Field: entity.FieldOptimistic},
+		},
 		{
 			name:     "URL successfully parsed",
 			req:      &http.Request{URL: &url.URL{RawQuery: "begin=1699228800&end=2699228800&symbol=UBER"}, Method: "GET"},
-			expected: entity.StockQuoteRequest{Symbol: "UBER", Begin: time.Unix(1699228800, 0), End: time.Unix(2699228800, 0)},
+			expected: entity.StockQuoteRequest{Symbol: "UBER", Begin: time.Unix(1699228800, 0).UTC(), End: time.Unix(2699228800, 0).UTC()},
+		},
+		{
+			name: "Dates in time zone normalized to UTC",
+			req: &http.Request{URL: &url.URL{RawQuery: "begin=2023-11-06&end=2023-11-08T16:00:00%2B01:00&symbol=UBER&tz=America/New_York"},
+				Method: "GET"},
+			expected: entity.StockQuoteRequest{Symbol: "UBER", Begin: time.Date(2023, 11, 6, 5, 0, 0, 0, time.UTC),
+				End: time.Date(2023, 11, 8, 15, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:        "Unknown time zone",
+			req:         &http.Request{URL: &url.URL{RawQuery: "begin=2023-11-06&end=2023-11-08&symbol=UBER&tz=Mars/Olympus"}, Method: "GET"},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:        "Relative begin after end",
+			req:         &http.Request{URL: &url.URL{RawQuery: "begin=now&end=-1d&symbol=UBER"}, Method: "GET"},
+			expectedErr: entity.ErrBadRequest,
 		},
 	}
 	for _, tt := range testCases {
@@ -91,13 +133,137 @@ func TestParseRequestData(t *testing.T) {
 }
 
 type MockController struct {
-	err error
+	err     error
+	updates chan entity.MaxProfitPoints
+	quotes  chan entity.StockQuote
+	// wait makes MaxProfitForPeriod block until the context is done, like a slow query
+	wait bool
 }
 
-func (c MockController) MaxProfitForPeriod(req entity.StockQuoteRequest) (entity.MaxProfitPoints, error) {
+func (c MockController) MaxProfitForPeriod(ctx context.Context, req entity.StockQuoteRequest) (entity.MaxProfitPoints, error) {
+	if c.wait {
+		<-ctx.Done()
+		return entity.MaxProfitPoints{}, fmt.Errorf("failed to load stock quotes: %w", ctx.Err())
+	}
 	return entity.MaxProfitPoints{}, c.err
 }
 
+func (c MockController) MaxShortProfitForPeriod(ctx context.Context, req entity.StockQuoteRequest) (entity.MaxProfitPoints, error) {
+	return entity.MaxProfitPoints{Direction: entity.DirectionShort}, c.err
+}
+
+func (c MockController) WithPosition(points entity.MaxProfitPoints, position entity.Position) (entity.MaxProfitPoints, error) {
+	points.Position = &entity.PositionProfit{Shares: position.Shares, Capital: position.Capital}
+	return points, nil
+}
+
+func (c MockController) TopTradeWindows(ctx context.Context, req entity.StockQuoteRequest, n int, rankBy entity.RankBy) (entity.TopTradeWindows, error) {
+	return entity.TopTradeWindows{RankBy: rankBy, Windows: make([]entity.TradeWindow, 0, n)}, c.err
+}
+
+func (c MockController) SymbolLeaderboard(ctx context.Context, symbols []string, timeSlice entity.StockQuoteRequest) (entity.Leaderboard, error) {
+	if c.err != nil {
+		return entity.Leaderboard{}, c.err
+	}
+
+	leaderboard := entity.Leaderboard{Entries: []entity.SymbolPerformance{}}
+	for i, s := range symbols {
+		leaderboard.Entries = append(leaderboard.Entries, entity.SymbolPerformance{Rank: i + 1, Symbol: s, Profit: 1, Return: 10})
+	}
+	leaderboard.Entries = append(leaderboard.Entries,
+		entity.SymbolPerformance{Symbol: "NOPE", Err: fmt.Errorf("no records found: %w", entity.ErrNotFound)},
+		entity.SymbolPerformance{Symbol: "FAIL", Err: errors.New("connection refused")},
+	)
+	return leaderboard, nil
+}
+
+func (c MockController) MaxProfitUpdates(ctx context.Context, req entity.StockQuoteRequest) (<-chan entity.MaxProfitPoints, func(), error) {
+	return c.updates, func() {}, c.err
+}
+
+func (c MockController) QuoteUpdates(ctx context.Context, req entity.StockQuoteRequest) (<-chan entity.StockQuote, func(), error) {
+	return c.quotes, func() {}, c.err
+}
+
+func (c MockController) MaxProfitForTransactions(ctx context.Context, req entity.StockQuoteRequest, k int) (entity.MultiTradeProfit, error) {
+	return entity.MultiTradeProfit{Trades: []entity.Trade{}}, c.err
+}
+
+func (c MockController) SaveStockQuotes(ctx context.Context, quotes []entity.StockQuote) (entity.QuoteIngestion, error) {
+	if c.err != nil {
+		return entity.QuoteIngestion{}, c.err
+	}
+
+	result := entity.QuoteIngestion{Rejected: []entity.RejectedQuote{}}
+	for i, quote := range quotes {
+		if quote.Price > 1000 {
+			result.Rejected = append(result.Rejected, entity.RejectedQuote{Row: i + 1, Err: fmt.Errorf("price too high: %w", entity.ErrBadRequest)})
+		} else {
+			result.Stored++
+		}
+	}
+	return result, nil
+}
+
+// ExportStockQuotes exports two quotes of the symbol, the export of FAIL fails after the first one
+func (c MockController) ExportStockQuotes(ctx context.Context, req entity.StockQuoteRequest, fn func(entity.StockQuote) error) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	for i, price := range []float64{48.14, 49.92} {
+		if req.Limit > 0 && i == req.Limit {
+			break
+		}
+		if i == 1 && req.Symbol == "FAIL" {
+			return errors.New("connection reset")
+		}
+		quote := entity.StockQuote{ID: int64(i + 1), Symbol: req.Symbol, Datepoint: time.Unix(1699228800+int64(i)*86400, 0),
+			Price: price, Open: 47, High: 50.5, Low: 46.8, Volume: 1000}
+		if err := fn(quote); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DescribeSymbol knows every symbol but UNKN, whatever the error of the other methods so they can be tested past it
+func (c MockController) DescribeSymbol(ctx context.Context, symbol string) (entity.Symbol, error) {
+	if symbol == "UNKN" {
+		return entity.Symbol{}, fmt.Errorf("unknown symbol %s: %w", symbol, entity.ErrNotFound)
+	}
+	return entity.Symbol{Symbol: symbol, Timezone: "UTC", Status: entity.SymbolListed}, nil
+}
+
+func (c MockController) SymbolCatalog(ctx context.Context) (entity.SymbolCatalog, error) {
+	if c.err != nil {
+		return entity.SymbolCatalog{}, c.err
+	}
+	first, last := time.Unix(1699228800, 0).UTC(), time.Unix(1699315200, 0).UTC()
+	return entity.SymbolCatalog{Symbols: []entity.Symbol{
+		{Symbol: "BRK.B", Name: "Berkshire Hathaway Inc. Class B", Exchange: "NYSE", Currency: "USD", Timezone: "America/New_York", Status: entity.SymbolListed},
+		{Symbol: "UBER", Timezone: "UTC", Status: entity.SymbolListed, FirstDatapoint: &first, LastDatapoint: &last},
+	}}, nil
+}
+
+// AddSymbol adds any symbol but UBER, which is known already
+func (c MockController) AddSymbol(ctx context.Context, symbol entity.Symbol) (entity.Symbol, error) {
+	if c.err != nil {
+		return entity.Symbol{}, c.err
+	}
+	if symbol.Symbol == "UBER" {
+		return entity.Symbol{}, fmt.Errorf("symbol %s exists already: %w", symbol.Symbol, entity.ErrConflict)
+	}
+	if symbol.Status == "" {
+		symbol.Status = entity.SymbolListed
+	}
+	return symbol, nil
+}
+
+func (c MockController) MaxProfitWithCosts(ctx context.Context, req entity.StockQuoteRequest, costs entity.TradeCosts) (entity.MultiTradeProfit, error) {
+	return entity.MultiTradeProfit{Trades: []entity.Trade{}, TotalProfit: costs.Fee}, c.err
+}
+
 func TestMaxProfitForPeriod_StatusCodes(t *testing.T) {
 	testCases := []struct {
 		name               string
@@ -112,7 +278,105 @@ func TestMaxProfitForPeriod_StatusCodes(t *testing.T) {
 			method:             "GET",
 			url:                "maxprofit?begin=1699228800&end=2699228800&symbol=UBER",
 			expectedStatusCode: http.StatusOK,
-			expectedBody:       "{\"buyPoint\":{\"price\":0,\"date\":\"0001-01-01T00:00:00Z\"},\"sellPoint\":{\"price\":0,\"date\":\"0001-01-01T00:00:00Z\"}}\n",
+			expectedBody:       "{\"buyPoint\":{\"price\":0,\"date\":\"0001-01-01T00:00:00Z\"},\"sellPoint\":{\"price\":0,\"date\":\"0001-01-01T00:00:00Z\"},\"profit\":0,\"return\":0,\"holdingDays\":0}\n",
+		},
+		{
+			name:               "Successfull GET request with multiple transactions",
+			method:             "GET",
+			url:                "maxprofit?begin=1699228800&end=2699228800&symbol=UBER&k=3",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "{\"trades\":[],\"totalProfit\":0,\"totalNetProfit\":0}\n",
+		},
+		{
+			name:               "Successfull GET request with trading costs",
+			method:             "GET",
+			url:                "maxprofit?begin=1699228800&end=2699228800&symbol=UBER&fee=0.5&feeType=percent&cooldown=86400",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "{\"trades\":[],\"totalProfit\":0.5,\"totalNetProfit\":0}\n",
+		},
+		{
+			name:               "Unknown symbol",
+			method:             "GET",
+			url:                "maxprofit?begin=1699228800&end=2699228800&symbol=UNKN",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "{\"message\":\"unknown symbol UNKN: not found\"}\n",
+		},
+		{
+			name:               "Class share symbol",
+			method:             "GET",
+			url:                "maxprofit?begin=1699228800&end=2699228800&symbol=BRK.B",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "{\"buyPoint\":{\"price\":0,\"date\":\"0001-01-01T00:00:00Z\"},\"sellPoint\":{\"price\":0,\"date\":\"0001-01-01T00:00:00Z\"},\"profit\":0,\"return\":0,\"holdingDays\":0}\n",
+		},
+		{
+			name:               "Invalid fee type",
+			method:             "GET",
+			url:                "maxprofit?begin=1699228800&end=2699228800&symbol=UBER&fee=1&feeType=flat",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"feeType param must be either absolute or percent: bad request\"}\n",
+		},
+		{
+			name:               "Fee type without fee",
+			method:             "GET",
+			url:                "maxprofit?begin=1699228800&end=2699228800&symbol=UBER&feeType=bogus",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"feeType param requires the fee param: bad request\"}\n",
+		},
+		{
+			name:               "Trading costs combined with transactions",
+			method:             "GET",
+			url:                "maxprofit?begin=1699228800&end=2699228800&symbol=UBER&k=2&cooldown=60",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"k param can't be combined with trading costs: bad request\"}\n",
+		},
+		{
+			name:               "Explicit single transaction keeps the response shape",
+			method:             "GET",
+			url:                "maxprofit?begin=1699228800&end=2699228800&symbol=UBER&k=1",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "{\"buyPoint\":{\"price\":0,\"date\":\"0001-01-01T00:00:00Z\"},\"sellPoint\":{\"price\":0,\"date\":\"0001-01-01T00:00:00Z\"},\"profit\":0,\"return\":0,\"holdingDays\":0}\n",
+		},
+		{
+			name:               "Successfull GET request in short direction",
+			method:             "GET",
+			url:                "maxprofit?begin=1699228800&end=2699228800&symbol=UBER&direction=short",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "{\"buyPoint\":{\"price\":0,\"date\":\"0001-01-01T00:00:00Z\"},\"sellPoint\":{\"price\":0,\"date\":\"0001-01-01T00:00:00Z\"},\"direction\":\"short\",\"profit\":0,\"return\":0,\"holdingDays\":0}\n",
+		},
+		{
+			name:               "Short direction with multiple transactions",
+			method:             "GET",
+			url:                "maxprofit?begin=1699228800&end=2699228800&symbol=UBER&direction=short&k=2",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"short direction supports a single transaction only: bad request\"}\n",
+		},
+		{
+			name:               "Successfull GET request with position size",
+			method:             "GET",
+			url:                "maxprofit?begin=1699228800&end=2699228800&symbol=UBER&shares=10",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "{\"buyPoint\":{\"price\":0,\"date\":\"0001-01-01T00:00:00Z\"},\"sellPoint\":{\"price\":0,\"date\":\"0001-01-01T00:00:00Z\"},\"profit\":0,\"return\":0,\"holdingDays\":0,\"position\":{\"shares\":10,\"capital\":0,\"profit\":0}}\n",
+		},
+		{
+			name:               "Both shares and capital",
+			method:             "GET",
+			url:                "maxprofit?begin=1699228800&end=2699228800&symbol=UBER&shares=10&capital=100",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"shares and capital params can't be combined: bad request\"}\n",
+		},
+		{
+			name:               "Position size with multiple transactions",
+			method:             "GET",
+			url:                "maxprofit?begin=1699228800&end=2699228800&symbol=UBER&capital=100&k=2",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"position size supports a single transaction only: bad request\"}\n",
+		},
+		{
+			name:               "Invalid number of transactions",
+			method:             "GET",
+			url:                "maxprofit?begin=1699228800&end=2699228800&symbol=UBER&k=0",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"k param must be between 1 and 100: bad request\"}\n",
 		},
 		{
 			name:               "Bad request",
@@ -152,3 +416,43 @@ func TestMaxProfitForPeriod_StatusCodes(t *testing.T) {
 		})
 	}
 }
+
+func TestMaxProfitForPeriod_Timeout(t *testing.T) {
+	handler := StockPriceHandler{Controller: MockController{wait: true}, Timeout: time.Millisecond * 10}
+
+	req, err := http.NewRequest("GET", "maxprofit?begin=1699228800&end=2699228800&symbol=UBER", nil)
+	assert.NoError(t, err)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(handler.MaxProfitForPeriod).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, rr.Code)
+	assert.Equal(t, "{\"message\":\"Request timed out\"}\n", rr.Body.String())
+}
+
+func TestMaxProfitForPeriod_MemoryRepository(t *testing.T) {
+	day := time.Unix(1699228800, 0)
+	repo := repository.NewMemory(
+		entity.StockQuote{Symbol: "UBER", Datepoint: day, Price: 10},
+		entity.StockQuote{Symbol: "UBER", Datepoint: day.Add(time.Hour * 24), Price: 8},
+		entity.StockQuote{Symbol: "UBER", Datepoint: day.Add(time.Hour * 48), Price: 12},
+	)
+	handler := StockPriceHandler{Controller: controller.New(repo)}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("maxprofit?begin=%d&end=%d&symbol=UBER", day.Unix()-1, day.Unix()+3*24*3600), nil)
+	assert.NoError(t, err)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(handler.MaxProfitForPeriod).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var got entity.MaxProfitPoints
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, 8.0, got.BuyPoint.Price)
+	assert.Equal(t, 12.0, got.SellPoint.Price)
+	assert.Equal(t, 4.0, got.Profit)
+
+	req, err = http.NewRequest("GET", fmt.Sprintf("maxprofit?begin=%d&end=%d&symbol=TSLA", day.Unix()-1, day.Unix()+3*24*3600), nil)
+	assert.NoError(t, err)
+	rr = httptest.NewRecorder()
+	http.HandlerFunc(handler.MaxProfitForPeriod).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"stockpricews/entity"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// writeTimeout bounds the time a single update may take to reach a client
+const writeTimeout = 10 * time.Second
+
+var upgrader = websocket.Upgrader{
+	// the API is open to clients running on other origins, same as Access-Control-Allow-Origin of the REST endpoints
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// MaxProfitStream is WebSocket HTTP handler that pushes to client the running best buy/sell pair of a symbol every
+// time it changes because of a new quote.
+// Usage: websocket GET /maxprofit/stream?symbol=<STOCK_SYMBOL>[&begin=<begin_time>]
+// The optional begin param seeds the computation with the stored quotes after it, otherwise only new quotes count.
+// Every message is entity.MaxProfitPoints as json. Errors before the connection is upgraded are reported with the same
+// status codes as MaxProfitForPeriod, the symbol must be in the catalog even if it has no quotes yet.
+func (h StockPriceHandler) MaxProfitStream(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	req, err := parseStreamRequest(r)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	// the deadline bounds loading the stored quotes only, the subscription lasts as long as the connection
+	ctx, cancelLoad := h.requestContext(r)
+	err = h.checkSymbol(ctx, req.Symbol)
+	var updates <-chan entity.MaxProfitPoints
+	var cancel func()
+	if err == nil {
+		updates, cancel, err = h.Controller.MaxProfitUpdates(ctx, req)
+	}
+	cancelLoad()
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+	defer cancel()
+
+	// the upgrader replies to the client on its own if the handshake fails
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer conn.Close()
+
+	// the client isn't expected to send anything, reading just detects when it goes away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case update, ok := <-updates:
+			if !ok {
+				// the subscription was dropped as the client can't keep up
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too slow"), time.Now().Add(writeTimeout))
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteJSON(update); err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+	}
+}
+
+func parseStreamRequest(r *http.Request) (entity.StockQuoteRequest, error) {
+	if r == nil || r.URL == nil {
+		return entity.StockQuoteRequest{}, fmt.Errorf("failed to read request URL: %w", entity.ErrBadRequest)
+	}
+
+	if r.Method != http.MethodGet {
+		return entity.StockQuoteRequest{}, fmt.Errorf("method %s not allowed: %w", r.Method, entity.ErrMethodNotAllowed)
+	}
+
+	stockSymbol := r.URL.Query().Get(symbol)
+	if err := validateSymbol(stockSymbol); err != nil {
+		return entity.StockQuoteRequest{}, err
+	}
+
+	loc, err := parseLocation(r)
+	if err != nil {
+		return entity.StockQuoteRequest{}, err
+	}
+
+	now := time.Now()
+	req := entity.StockQuoteRequest{Symbol: stockSymbol, Begin: now.UTC()}
+	if r.URL.Query().Has(begin) {
+		if req.Begin, err = parseTimeParam(begin, r.URL.Query().Get(begin), loc, now); err != nil {
+			return entity.StockQuoteRequest{}, err
+		}
+	}
+
+	return req, nil
+}
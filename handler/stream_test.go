@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"stockpricews/entity"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxProfitStream(t *testing.T) {
+	updates := make(chan entity.MaxProfitPoints, 2)
+	handler := StockPriceHandler{Controller: MockController{updates: updates}}
+	server := httptest.NewServer(http.HandlerFunc(handler.MaxProfitStream))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/maxprofit/stream?symbol=UBER&begin=1699228800"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	first := entity.MaxProfitPoints{
+		BuyPoint:  entity.TradePoint{Price: 1, Date: time.Unix(1699228800, 0).UTC()},
+		SellPoint: entity.TradePoint{Price: 2, Date: time.Unix(1699315200, 0).UTC()},
+		Direction: entity.DirectionLong,
+	}
+	updates <- first
+
+	var got entity.MaxProfitPoints
+	assert.NoError(t, conn.ReadJSON(&got))
+	assert.Equal(t, first, got)
+
+	// the connection is closed once the subscription ends
+	close(updates)
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseTryAgainLater))
+}
+
+func TestMaxProfitStream_StatusCodes(t *testing.T) {
+	testCases := []struct {
+		name               string
+		controller         MockController
+		url                string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "Symbol param is missing",
+			url:                "maxprofit/stream",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"stock symbol must be between 1 and 16 chars long: bad request\"}\n",
+		},
+		{
+			name:               "Begin param can't be parsed",
+			url:                "maxprofit/stream?symbol=UBER&begin=asd",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"begin param must be unix seconds, an RFC 3339 time, a date, now, today, ytd or relative to now like -30d: bad request\"}\n",
+		},
+		{
+			name:               "Streaming is not enabled",
+			controller:         MockController{err: entity.ErrNotFound},
+			url:                "maxprofit/stream?symbol=UBER",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "{\"message\":\"not found\"}\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.url, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			StockPriceHandler{Controller: tt.controller}.MaxProfitStream(rr, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"stockpricews/entity"
+	"strings"
+)
+
+// maxSymbolBytes is the upper bound of a POST /symbols body
+const maxSymbolBytes = 64 << 10
+
+// postedSymbol is the body of POST /symbols, the date points are taken from the quotes so they can't be posted
+type postedSymbol struct {
+	Symbol   string              `json:"symbol"`
+	Name     string              `json:"name"`
+	Exchange string              `json:"exchange"`
+	Currency string              `json:"currency"`
+	Timezone string              `json:"timezone"`
+	Status   entity.SymbolStatus `json:"status"`
+}
+
+// Symbols is HTTP handler of /symbols, GET lists the catalog and POST adds a symbol to it
+func (h StockPriceHandler) Symbols(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		h.AddSymbol(w, r)
+		return
+	}
+	h.SymbolCatalog(w, r)
+}
+
+// SymbolCatalog is HTTP handler that returns to client all the known symbols in alphabetical order. The symbols are
+// registered with POST /symbols or by their first stored quote.
+// Usage: curl GET /symbols
+// Result status codes:
+//   - 200 OK - body contains entity.SymbolCatalog as json, the first and last date points are missing for the symbols
+//     without quotes
+//   - 405 Method Not Allowed - if the method is neither GET nor POST
+//   - 500 Internal Server Error - if the catalog can't be loaded
+func (h StockPriceHandler) SymbolCatalog(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if !(r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions) {
+		respondWithError(fmt.Errorf("method %s not allowed: %w", r.Method, entity.ErrMethodNotAllowed), w)
+		return
+	}
+
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	catalog, err := h.Controller.SymbolCatalog(ctx)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(catalog)
+}
+
+// AddSymbol is HTTP handler that adds a symbol to the catalog. The client has to authenticate with the ingestion
+// token: 'Authorization: Bearer <token>'.
+// Usage: curl -X POST /symbols -d '{"symbol":"BRK.B","name":"Berkshire Hathaway Inc. Class B","exchange":"NYSE","currency":"USD","timezone":"America/New_York"}'
+// The timezone defaults to UTC and the status (listed or delisted) to listed.
+// Result status codes:
+//   - 201 Created - body contains the stored entity.Symbol as json
+//   - 400 Bad Request - if the body can't be read or any of the fields isn't valid
+//   - 401 Unauthorized - if the token is missing or wrong, or no token is configured at all
+//   - 409 Conflict - if the symbol is in the catalog already
+//   - 500 Internal Server Error - if the symbol couldn't be stored
+func (h StockPriceHandler) AddSymbol(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Method != http.MethodPost {
+		respondWithError(fmt.Errorf("method %s not allowed: %w", r.Method, entity.ErrMethodNotAllowed), w)
+		return
+	}
+
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		respondWithError(fmt.Errorf("missing or invalid ingestion token: %w", entity.ErrUnauthorized), w)
+		return
+	}
+
+	var posted postedSymbol
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSymbolBytes)).Decode(&posted); err != nil {
+		respondWithError(fmt.Errorf("body is not a valid symbol: %w", entity.ErrBadRequest), w)
+		return
+	}
+
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	stored, err := h.Controller.AddSymbol(ctx, entity.Symbol{
+		Symbol:   posted.Symbol,
+		Name:     posted.Name,
+		Exchange: posted.Exchange,
+		Currency: posted.Currency,
+		Timezone: posted.Timezone,
+		Status:   posted.Status,
+	})
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(stored)
+}
+
+// DescribeSymbol is HTTP handler that returns to client the metadata of a single symbol.
+// Usage: curl GET /symbols/<STOCK_SYMBOL>
+// Result status codes:
+//   - 200 OK - body contains entity.Symbol as json
+//   - 400 Bad Request - if the symbol doesn't have a correct format
+//   - 404 Not Found - if the symbol isn't in the catalog
+//   - 405 Method Not Allowed - if the method isn't GET
+//   - 500 Internal Server Error - if the symbol can't be loaded
+func (h StockPriceHandler) DescribeSymbol(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if !(r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions) {
+		respondWithError(fmt.Errorf("method %s not allowed: %w", r.Method, entity.ErrMethodNotAllowed), w)
+		return
+	}
+
+	// the path is unescaped already, so the index tickers can be passed as /symbols/%5EGSPC
+	stockSymbol := strings.TrimPrefix(r.URL.Path, "/symbols/")
+	if err := validateSymbol(stockSymbol); err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	s, err := h.Controller.DescribeSymbol(ctx, stockSymbol)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(s)
+}
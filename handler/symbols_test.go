@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbols_StatusCodes(t *testing.T) {
+	testCases := []struct {
+		name               string
+		controller         MockController
+		method             string
+		url                string
+		token              string
+		body               string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "Catalog",
+			method:             "GET",
+			url:                "/symbols",
+			expectedStatusCode: http.StatusOK,
+			expectedBody: "{\"symbols\":[{\"symbol\":\"BRK.B\",\"name\":\"Berkshire Hathaway Inc. Class B\",\"exchange\":\"NYSE\",\"currency\":\"USD\",\"timezone\":\"America/New_York\",\"status\":\"listed\"}," +
+				"{\"symbol\":\"UBER\",\"name\":\"\",\"exchange\":\"\",\"currency\":\"\",\"timezone\":\"UTC\",\"status\":\"listed\",\"firstDatapoint\":\"2023-11-06T00:00:00Z\",\"lastDatapoint\":\"2023-11-07T00:00:00Z\"}]}\n",
+		},
+		{
+			name:               "Catalog failed",
+			controller:         MockController{err: errors.New("connection refused")},
+			method:             "GET",
+			url:                "/symbols",
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       "{\"message\":\"Internal server error\"}\n",
+		},
+		{
+			name:               "Add symbol",
+			method:             "POST",
+			url:                "/symbols",
+			token:              "secret",
+			body:               `{"symbol":"VOD.L","name":"Vodafone Group","exchange":"LSE","currency":"GBP","timezone":"Europe/London"}`,
+			expectedStatusCode: http.StatusCreated,
+			expectedBody:       "{\"symbol\":\"VOD.L\",\"name\":\"Vodafone Group\",\"exchange\":\"LSE\",\"currency\":\"GBP\",\"timezone\":\"Europe/London\",\"status\":\"listed\"}\n",
+		},
+		{
+			name:               "Add known symbol",
+			method:             "POST",
+			url:                "/symbols",
+			token:              "secret",
+			body:               `{"symbol":"UBER"}`,
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       "{\"message\":\"symbol UBER exists already: conflict\"}\n",
+		},
+		{
+			name:               "Add symbol without token",
+			method:             "POST",
+			url:                "/symbols",
+			body:               `{"symbol":"VOD.L"}`,
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedBody:       "{\"message\":\"missing or invalid ingestion token: unauthorized\"}\n",
+		},
+		{
+			name:               "Add symbol with invalid body",
+			method:             "POST",
+			url:                "/symbols",
+			token:              "secret",
+			body:               `["VOD.L"]`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"body is not a valid symbol: bad request\"}\n",
+		},
+		{
+			name:               "Describe symbol",
+			method:             "GET",
+			url:                "/symbols/BRK.B",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "{\"symbol\":\"BRK.B\",\"name\":\"\",\"exchange\":\"\",\"currency\":\"\",\"timezone\":\"UTC\",\"status\":\"listed\"}\n",
+		},
+		{
+			name:               "Describe index symbol",
+			method:             "GET",
+			url:                "/symbols/%5EGSPC",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "{\"symbol\":\"^GSPC\",\"name\":\"\",\"exchange\":\"\",\"currency\":\"\",\"timezone\":\"UTC\",\"status\":\"listed\"}\n",
+		},
+		{
+			name:               "Describe unknown symbol",
+			method:             "GET",
+			url:                "/symbols/UNKN",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "{\"message\":\"unknown symbol UNKN: not found\"}\n",
+		},
+		{
+			name:               "Describe invalid symbol",
+			method:             "GET",
+			url:                "/symbols/ABCDEFGHIJKLMNOPQ",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"stock symbol must be between 1 and 16 chars long: bad request\"}\n",
+		},
+		{
+			name:               "Describe with non GET request",
+			method:             "DELETE",
+			url:                "/symbols/UBER",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedBody:       "{\"message\":\"method DELETE not allowed: method not allowed\"}\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			h := StockPriceHandler{Controller: tt.controller, IngestToken: "secret"}
+			handlerF := h.Symbols
+			if strings.HasPrefix(tt.url, "/symbols/") {
+				handlerF = h.DescribeSymbol
+			}
+			rr := httptest.NewRecorder()
+			handlerF(rr, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"stockpricews/entity"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tz = "tz"
+
+	timeNow   = "now"
+	timeToday = "today"
+	timeYTD   = "ytd"
+
+	// upper bound of the number of units of a relative time
+	maxRelativeTime = 1000000
+)
+
+// localTimeLayouts are the accepted formats of the time params without an offset, they're read in the tz param zone
+var localTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// parseLocation returns the time zone of the tz param the dates without an offset are read in, UTC if it's not passed
+func parseLocation(r *http.Request) (*time.Location, error) {
+	if !r.URL.Query().Has(tz) {
+		return time.UTC, nil
+	}
+
+	name := r.URL.Query().Get(tz)
+	// LoadLocation reads the zone from a file, the names must not walk out of the zone database
+	if name == "" || name == "Local" || strings.Contains(name, "..") {
+		return nil, fmt.Errorf("%s param must be an IANA time zone name, e.g. America/New_York: %w", tz, entity.ErrBadRequest)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s param must be an IANA time zone name, e.g. America/New_York: %w", tz, entity.ErrBadRequest)
+	}
+	return loc, nil
+}
+
+// parseTimeParam parses the value of a time param and returns it in UTC. The value is either unix seconds, an RFC 3339
+// time, a date with an optional time of day read in loc, 'now', 'today' (midnight in loc), 'ytd' (the first day of the
+// year in loc) or a time relative to now like '-12h', '-30d', '-2w', '-6mo' or '-1y'; the days, months and years are
+// calendar ones in loc.
+func parseTimeParam(name, value string, loc *time.Location, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(secs, 0).UTC(), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	local := now.In(loc)
+	switch strings.ToLower(value) {
+	case timeNow:
+		return now.UTC(), nil
+	case timeToday:
+		return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc).UTC(), nil
+	case timeYTD:
+		return time.Date(local.Year(), time.January, 1, 0, 0, 0, 0, loc).UTC(), nil
+	}
+
+	if t, ok := relativeTime(value, local); ok {
+		return t.UTC(), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%s param must be unix seconds, an RFC 3339 time, a date, %s, %s, %s or relative to now like -30d: %w",
+		name, timeNow, timeToday, timeYTD, entity.ErrBadRequest)
+}
+
+// relativeTime parses a time in the past relative to now, i.e. a minus sign followed by a number and one of the units
+// h, d, w, mo or y
+func relativeTime(value string, now time.Time) (time.Time, bool) {
+	if !strings.HasPrefix(value, "-") {
+		return time.Time{}, false
+	}
+
+	unit := strings.TrimLeft(value[1:], "0123456789")
+	n, err := strconv.Atoi(value[1 : len(value)-len(unit)])
+	// the bound keeps the hours within time.Duration
+	if err != nil || n > maxRelativeTime {
+		return time.Time{}, false
+	}
+
+	switch unit {
+	case "h":
+		return now.Add(-time.Duration(n) * time.Hour), true
+	case "d":
+		return now.AddDate(0, 0, -n), true
+	case "w":
+		return now.AddDate(0, 0, -7*n), true
+	case "mo":
+		return now.AddDate(0, -n, 0), true
+	case "y":
+		return now.AddDate(-n, 0, 0), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimeParam(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	// 2024-03-15 22:30 in New York is the next day in UTC
+	now := time.Date(2024, 3, 16, 2, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		value         string
+		loc           *time.Location
+		expected      time.Time
+		expectedError string
+	}{
+		{name: "Unix seconds", value: "1699228800", loc: newYork, expected: time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC)},
+		{name: "RFC 3339 keeps its offset", value: "2023-11-06T09:30:00-05:00", loc: time.UTC, expected: time.Date(2023, 11, 6, 14, 30, 0, 0, time.UTC)},
+		{name: "RFC 3339 in UTC", value: "2023-11-06T09:30:00Z", loc: newYork, expected: time.Date(2023, 11, 6, 9, 30, 0, 0, time.UTC)},
+		{name: "Date", value: "2023-11-06", loc: time.UTC, expected: time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC)},
+		{name: "Date in time zone", value: "2023-11-06", loc: newYork, expected: time.Date(2023, 11, 6, 5, 0, 0, 0, time.UTC)},
+		{name: "Date and time in time zone", value: "2023-07-06 09:30:00", loc: newYork, expected: time.Date(2023, 7, 6, 13, 30, 0, 0, time.UTC)},
+		{name: "Date and time with T", value: "2023-07-06T09:30:00", loc: newYork, expected: time.Date(2023, 7, 6, 13, 30, 0, 0, time.UTC)},
+		{name: "Now", value: "now", loc: newYork, expected: now},
+		{name: "Today", value: "today", loc: time.UTC, expected: time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{name: "Today in time zone", value: "TODAY", loc: newYork, expected: time.Date(2024, 3, 15, 4, 0, 0, 0, time.UTC)},
+		{name: "Year to date", value: "ytd", loc: time.UTC, expected: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "Year to date in time zone", value: "ytd", loc: newYork, expected: time.Date(2024, 1, 1, 5, 0, 0, 0, time.UTC)},
+		{name: "Hours ago", value: "-12h", loc: newYork, expected: time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC)},
+		// the days are calendar ones, New York switched to daylight saving time on 2024-03-10
+		{name: "Days ago", value: "-30d", loc: newYork, expected: time.Date(2024, 2, 15, 3, 30, 0, 0, time.UTC)},
+		{name: "Weeks ago", value: "-2w", loc: time.UTC, expected: time.Date(2024, 3, 2, 2, 30, 0, 0, time.UTC)},
+		{name: "Months ago", value: "-6mo", loc: time.UTC, expected: time.Date(2023, 9, 16, 2, 30, 0, 0, time.UTC)},
+		{name: "Years ago", value: "-1y", loc: time.UTC, expected: time.Date(2023, 3, 16, 2, 30, 0, 0, time.UTC)},
+		{
+			name:          "Unknown unit",
+			value:         "-30m",
+			loc:           time.UTC,
+			expectedError: "begin param must be unix seconds, an RFC 3339 time, a date, now, today, ytd or relative to now like -30d: bad request",
+		},
+		{
+			name:          "Relative time in the future",
+			value:         "+1d",
+			loc:           time.UTC,
+			expectedError: "begin param must be unix seconds, an RFC 3339 time, a date, now, today, ytd or relative to now like -30d: bad request",
+		},
+		{
+			name:          "Relative time without number",
+			value:         "-d",
+			loc:           time.UTC,
+			expectedError: "begin param must be unix seconds, an RFC 3339 time, a date, now, today, ytd or relative to now like -30d: bad request",
+		},
+		{
+			name:          "Relative time too far",
+			value:         "-99999999999h",
+			loc:           time.UTC,
+			expectedError: "begin param must be unix seconds, an RFC 3339 time, a date, now, today, ytd or relative to now like -30d: bad request",
+		},
+		{
+			name:          "Invalid date",
+			value:         "2023-02-30",
+			loc:           time.UTC,
+			expectedError: "begin param must be unix seconds, an RFC 3339 time, a date, now, today, ytd or relative to now like -30d: bad request",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTimeParam(begin, tt.value, tt.loc, now)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+			assert.Equal(t, time.UTC, got.Location())
+		})
+	}
+}
+
+func TestParseLocation(t *testing.T) {
+	testCases := []struct {
+		name          string
+		url           string
+		expected      string
+		expectedError string
+	}{
+		{name: "UTC by default", url: "/maxprofit", expected: "UTC"},
+		{name: "IANA name", url: "/maxprofit?tz=Europe/London", expected: "Europe/London"},
+		{name: "Unknown name", url: "/maxprofit?tz=Mars/Olympus", expectedError: "tz param must be an IANA time zone name, e.g. America/New_York: bad request"},
+		{name: "Server time zone", url: "/maxprofit?tz=Local", expectedError: "tz param must be an IANA time zone name, e.g. America/New_York: bad request"},
+		{name: "Empty name", url: "/maxprofit?tz=", expectedError: "tz param must be an IANA time zone name, e.g. America/New_York: bad request"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := parseLocation(httptest.NewRequest("GET", tt.url, nil))
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, loc.String())
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"stockpricews/entity"
+	"strconv"
+)
+
+const (
+	windows = "n"
+	rankBy  = "rank"
+
+	// default and max number of windows returned by TopTradeWindows
+	defaultWindows = 5
+	maxWindows     = 100
+)
+
+// TopTradeWindows is HTTP handler that returns to client the N most profitable non-overlapping buy/sell windows within
+// given time slice.
+// Usage: curl GET /maxprofit/top?begin=<begin_time>&end=<end_time>&symbol=<STOCK_SYMBOL>[&n=<windows>][&rank=absolute|percent]
+// Result status codes are the same as the ones of MaxProfitForPeriod, on success the body contains
+// entity.TopTradeWindows as json.
+func (h StockPriceHandler) TopTradeWindows(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	timeSlice, err := parseRequestData(r)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	n, rank, err := parseTopWindows(r)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	if err := h.checkSymbol(ctx, timeSlice.Symbol); err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	top, err := h.Controller.TopTradeWindows(ctx, timeSlice, n, rank)
+	if err != nil {
+		respondWithError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(top)
+}
+
+func parseTopWindows(r *http.Request) (int, entity.RankBy, error) {
+	n := defaultWindows
+	if r.URL.Query().Has(windows) {
+		var err error
+		if n, err = strconv.Atoi(r.URL.Query().Get(windows)); err != nil {
+			return 0, "", fmt.Errorf("%s param can't be parsed as integer: %w", windows, entity.ErrBadRequest)
+		}
+		if n < 1 || n > maxWindows {
+			return 0, "", fmt.Errorf("%s param must be between 1 and %d: %w", windows, maxWindows, entity.ErrBadRequest)
+		}
+	}
+
+	switch rank := entity.RankBy(r.URL.Query().Get(rankBy)); rank {
+	case "", entity.RankByAbsolute:
+		return n, entity.RankByAbsolute, nil
+	case entity.RankByPercent:
+		return n, rank, nil
+	default:
+		return 0, "", fmt.Errorf("%s param must be either %s or %s: %w", rankBy, entity.RankByAbsolute, entity.RankByPercent, entity.ErrBadRequest)
+	}
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"stockpricews/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopTradeWindows_StatusCodes(t *testing.T) {
+	testCases := []struct {
+		name               string
+		controller         MockController
+		url                string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "Successfull GET request with defaults",
+			url:                "maxprofit/top?begin=1699228800&end=2699228800&symbol=UBER",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "{\"rankBy\":\"absolute\",\"windows\":[]}\n",
+		},
+		{
+			name:               "Successfull GET request ranked by percentage",
+			url:                "maxprofit/top?begin=1699228800&end=2699228800&symbol=UBER&n=10&rank=percent",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "{\"rankBy\":\"percent\",\"windows\":[]}\n",
+		},
+		{
+			name:               "Invalid number of windows",
+			url:                "maxprofit/top?begin=1699228800&end=2699228800&symbol=UBER&n=101",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"n param must be between 1 and 100: bad request\"}\n",
+		},
+		{
+			name:               "Invalid rank criteria",
+			url:                "maxprofit/top?begin=1699228800&end=2699228800&symbol=UBER&rank=volume",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "{\"message\":\"rank param must be either absolute or percent: bad request\"}\n",
+		},
+		{
+			name:               "No windows found",
+			controller:         MockController{err: entity.ErrNotFound},
+			url:                "maxprofit/top?begin=1699228800&end=2699228800&symbol=UBER",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "{\"message\":\"not found\"}\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.url, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			StockPriceHandler{Controller: tt.controller}.TopTradeWindows(rr, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
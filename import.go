@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"stockpricews/controller"
+	"stockpricews/entity"
+	"stockpricews/repository"
+	"strings"
+	"time"
+)
+
+// importCheckpoint is the progress of an import saved after every stored batch, so a failed import resumes after the
+// last stored batch rather than from the start
+type importCheckpoint struct {
+	Source string `json:"source"`
+	Size   int64  `json:"size"`
+	entity.ImportProgress
+}
+
+// importQuotes runs the import subcommand: import [-format=csv|jsonl] [-batch=<n>] [-db.* params] <file>
+func importQuotes(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	db := registerDBFlags(flags)
+	format := flags.String("format", "", "format of the file: csv or jsonl, by default it's taken from the file extension")
+	batchSize := flags.Int("batch", controller.DefaultImportBatchSize, "number of quotes stored in a single transaction")
+	checkpoint := flags.String("checkpoint", "", "file the progress is saved to after every batch, <file>.checkpoint by default")
+	progressInterval := flags.Duration("progress", 5*time.Second, "interval the progress is reported at")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: import [-format=csv|jsonl] [-batch=<n>] [-checkpoint=<file>] [-db.driver=<driver> ...] <file>")
+	}
+	source := flags.Arg(0)
+	if *checkpoint == "" {
+		*checkpoint = source + ".checkpoint"
+	}
+	if *format == "" {
+		*format = quoteFormat(source)
+	}
+
+	file, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	resume, err := loadCheckpoint(*checkpoint, source, info.Size())
+	if err != nil {
+		return err
+	}
+	if resume.Records > 0 {
+		fmt.Printf("resuming after %d records from %s\n", resume.Records, *checkpoint)
+	}
+
+	reader, err := repository.NewQuoteReader(file, entity.QuoteFormat(*format))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", source, err)
+	}
+
+	r, err := newImportRepository(db)
+	if err != nil {
+		return err
+	}
+
+	// an interrupted import stops after the current batch is stored or rolled back, the checkpoint stays consistent
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	start := time.Now()
+	lastReport := start
+	importer := controller.Importer{
+		Repository: r,
+		BatchSize:  *batchSize,
+		Resume:     resume.ImportProgress,
+		Progress: func(progress entity.ImportProgress) {
+			if err := saveCheckpoint(*checkpoint, importCheckpoint{Source: source, Size: info.Size(), ImportProgress: progress}); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to save checkpoint: %v\n", err)
+			}
+			if time.Since(lastReport) >= *progressInterval {
+				lastReport = time.Now()
+				printImportProgress(progress, resume.Records, start)
+			}
+		},
+		Reject: func(rejected entity.RejectedQuote) {
+			fmt.Fprintf(os.Stderr, "line %d rejected: %v\n", rejected.Row, rejected.Err)
+		},
+	}
+
+	progress, err := importer.Import(ctx, reader)
+	printImportProgress(progress, resume.Records, start)
+	if err != nil {
+		return fmt.Errorf("%w, run the same command again to resume after the last stored batch", err)
+	}
+
+	if err := os.Remove(*checkpoint); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// quoteFormat takes the format of the quote file from its extension
+func quoteFormat(path string) string {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".jsonl", ".ndjson":
+		return string(entity.FormatJSONLines)
+	default:
+		return strings.TrimPrefix(ext, ".")
+	}
+}
+
+// newImportRepository opens the configured database for the import, the sqlite database is migrated but not seeded
+func newImportRepository(db dbConfig) (repository.Repository, error) {
+	switch *db.driver {
+	case "sqlite":
+		r, err := repository.OpenSQLite(*db.path)
+		if err != nil {
+			return nil, err
+		}
+		return r, migrateUp(r)
+	case "memory":
+		return nil, fmt.Errorf("quotes can't be imported into the memory database, it's gone once the import ends")
+	default:
+		return newRepository(db)
+	}
+}
+
+// loadCheckpoint reads the progress of a previous import of the source, a missing checkpoint starts from scratch
+func loadCheckpoint(path, source string, size int64) (importCheckpoint, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return importCheckpoint{}, nil
+	}
+	if err != nil {
+		return importCheckpoint{}, err
+	}
+
+	var checkpoint importCheckpoint
+	if err := json.Unmarshal(content, &checkpoint); err != nil {
+		return importCheckpoint{}, fmt.Errorf("checkpoint %s is corrupt: %w", path, err)
+	}
+	// the records are skipped by count, so resuming a different or modified file would skip the wrong ones
+	if checkpoint.Source != source || checkpoint.Size != size {
+		return importCheckpoint{}, fmt.Errorf("checkpoint %s belongs to another version of %s, remove it to start over", path, source)
+	}
+
+	return checkpoint, nil
+}
+
+// saveCheckpoint replaces the checkpoint atomically, so a crash leaves either the previous or the new one
+func saveCheckpoint(path string, checkpoint importCheckpoint) error {
+	content, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func printImportProgress(progress entity.ImportProgress, resumed int64, start time.Time) {
+	elapsed := time.Since(start)
+	rate := float64(progress.Records-resumed) / elapsed.Seconds()
+	fmt.Printf("%d records read: %d stored, %d rejected, %d duplicates in %d batches (%.0f records/s, %s)\n",
+		progress.Records, progress.Stored, progress.Rejected, progress.Duplicates, progress.Batches, rate,
+		elapsed.Round(time.Millisecond))
+}
@@ -1,33 +1,163 @@
 package main
 
 import (
+	"context"
+	"expvar"
 	"flag"
 	"fmt"
+	"os"
 	"stockpricews/controller"
 	"stockpricews/handler"
 	"stockpricews/repository"
+	"stockpricews/rpc"
+	"time"
+	// the symbol timezones are validated against the embedded database, so they don't depend on the host
+	_ "time/tzdata"
 )
 
-// In order to run the program the following params must be supplied
-// go run . -server.port=8080 -db.user=<user> -db.pass=<pass> -db.port=8181
+// dbConfig holds the flags selecting and accessing the database, shared by the service and the subcommands
+type dbConfig struct {
+	driver *string
+	path   *string
+	seed   *string
+	user   *string
+	pass   *string
+	port   *int
+	// flags tells the flags passed explicitly apart from the defaults
+	flags *flag.FlagSet
+}
+
+const (
+	defaultSeed = "data/dump.sql"
+	// defaultMemorySeed holds the quotes of defaultSeed as CSV, as the memory repository can't read SQL dumps
+	defaultMemorySeed = "data/quotes.csv"
+)
+
+// subcommands run instead of the service if the first argument names them
+var subcommands = map[string]func(args []string) error{
+	"migrate": migrate,
+	"import":  importQuotes,
+	"export":  exportQuotes,
+}
+
+func registerDBFlags(flags *flag.FlagSet) dbConfig {
+	return dbConfig{
+		driver: flags.String("db.driver", "sqlite", "database the quotes are stored in: sqlite, memory, mysql or postgres"),
+		path:   flags.String("db.path", "stockquote.db", "file of the sqlite database, created if it doesn't exist"),
+		seed:   flags.String("db.seed", defaultSeed, "SQL dump or CSV file to seed the empty sqlite database from (CSV or JSON for memory, "+defaultMemorySeed+" by default), empty disables seeding"),
+		user:   flags.String("db.user", "root", "username to access the local database instance"),
+		pass:   flags.String("db.pass", "", "password to access the local database instance"),
+		port:   flags.Int("db.port", 8181, "port of the local database instance"),
+		flags:  flags,
+	}
+}
+
+// The program runs on an embedded SQLite database seeded from data/dump.sql by default: go run .
+// In order to use MySQL or PostgreSQL the following params must be supplied
+// go run . -server.port=8080 -db.driver=mysql|postgres -db.user=<user> -db.pass=<pass> -db.port=8181
+// The schema of the database is managed with: go run . migrate up|down|status [-db.* params]
+// Quote files are bulk imported with: go run . import [-batch=<n>] [-db.* params] <file.csv|file.jsonl>
+// and exported with: go run . export -symbol=<symbol> -begin=<time> -end=<time> [-tz=<zone>] [-o=<file>] [-db.* params]
 func main() {
+	if len(os.Args) > 1 {
+		if command, ok := subcommands[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	serverPort := flag.Int("server.port", 8080, "port to listen for incoming http requests")
-	dbUser := flag.String("db.user", "root", "username to access the local mysql instance")
-	dbPass := flag.String("db.pass", "", "password to access the local mysql instance")
-	dbPort := flag.Int("db.port", 8181, "port of the local mysql instance")
+	grpcPort := flag.Int("grpc.port", 9090, "port to listen for incoming gRPC requests, 0 disables the gRPC API")
+	db := registerDBFlags(flag.CommandLine)
+	dbMigrate := flag.Bool("db.migrate", false, "apply the pending schema migrations on start, sqlite is always migrated")
+	indexEnabled := flag.Bool("index.enabled", false, "answer max profit queries from an in-memory per-symbol index")
+	indexTTL := flag.Duration("index.ttl", time.Minute, "time the index of a symbol is served before it's reloaded with the quotes stored by other processes, 0 keeps it")
+	workers := flag.Int("leaderboard.workers", 4, "number of symbols computed concurrently for the leaderboard")
+	cacheSize := flag.Int64("cache.size", 64, "memory in MB the quotes cached in front of the database may take, 0 disables the cache")
+	cacheTTL := flag.Duration("cache.ttl", time.Minute, "time the cached quotes are served before they're loaded from the database again")
+	requestTimeout := flag.Duration("request.timeout", 10*time.Second, "time a request may take to compute before it fails with 504, 0 disables the deadline")
+	ingestToken := flag.String("ingest.token", "", "bearer token the clients posting quotes to /quotes authenticate with, empty disables posting quotes")
+	feedPoll := flag.Duration("feed.poll", 5*time.Second, "interval to poll the database for new quotes of streamed symbols, 0 disables polling")
 
 	flag.Parse()
 
 	// init and wire components following Onion Architecture. In a real-life app a DI framework might be used to do the job
-	r, err := repository.New(*dbUser, *dbPass, *dbPort)
+	r, err := newRepository(db)
 	if err != nil {
 		panic(fmt.Errorf("failed to initialize repository %w", err))
 	}
-	c := controller.New(r)
-	_, err = handler.New(c, *serverPort)
+	if *dbMigrate {
+		if err := migrateUp(r); err != nil {
+			panic(fmt.Errorf("failed to migrate database %w", err))
+		}
+	}
+	// the poller looks for new quotes so it always goes to the database, bypassing the cache
+	cached := r
+	if *cacheSize > 0 {
+		cache := repository.NewCache(r, *cacheSize<<20, *cacheTTL)
+		// hit/miss counters are served at /debug/vars
+		expvar.Publish("cache", expvar.Func(func() interface{} { return cache.Stats() }))
+		cached = cache
+	}
+	c := controller.New(cached)
+	c.Workers = *workers
+	if *indexEnabled {
+		// the whole history is reloaded on expiry only, it goes to the database so the cache doesn't delay it further
+		c.Index = controller.NewProfitIndex(r, *indexTTL)
+	}
+	c.Feed = controller.NewQuoteFeed()
+	if *feedPoll > 0 {
+		go c.Feed.PollRepository(context.Background(), r, *feedPoll)
+	}
+	// the endpoints have the same capacity whichever API they're called by
+	limits := handler.NewRateLimits()
+	if *grpcPort > 0 {
+		go func() {
+			if err := rpc.Serve(c, *grpcPort, *requestTimeout, limits); err != nil {
+				panic(fmt.Errorf("failed to serve gRPC %w", err))
+			}
+		}()
+	}
+	_, err = handler.New(c, *serverPort, *requestTimeout, *ingestToken, limits)
 	if err != nil {
-		fmt.Errorf("failed to initialize handler%w", err)
-		panic(err)
+		panic(fmt.Errorf("failed to initialize handler %w", err))
 	}
 
 }
+
+// isSet reports whether the flag was passed explicitly rather than left at its default
+func isSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// newRepository initializes the repository of the configured database driver
+func newRepository(db dbConfig) (repository.Repository, error) {
+	switch *db.driver {
+	case "sqlite":
+		return repository.NewSQLite(*db.path, *db.seed)
+	case "memory":
+		seed := *db.seed
+		if !isSet(db.flags, "db.seed") {
+			seed = defaultMemorySeed
+		}
+		if seed == "" {
+			return repository.NewMemory(), nil
+		}
+		return repository.NewMemoryFromFile(seed)
+	case "mysql":
+		return repository.New(*db.user, *db.pass, *db.port)
+	case "postgres":
+		return repository.NewPostgres(*db.user, *db.pass, *db.port)
+	default:
+		return nil, fmt.Errorf("unknown database driver %s", *db.driver)
+	}
+}
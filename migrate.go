@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"stockpricews/repository"
+	"time"
+)
+
+// migratable is implemented by the repositories backed by a SQL database
+type migratable interface {
+	Migrator() (*repository.Migrator, error)
+}
+
+// migrate runs the migrate subcommand: migrate up|down|status [-steps=<n>] [-db.* params]
+func migrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up|down|status [-steps=<n>] [-db.driver=<driver> ...]")
+	}
+
+	command := args[0]
+	if command != "up" && command != "down" && command != "status" {
+		return fmt.Errorf("unknown migrate command %s, up, down or status expected", command)
+	}
+	flags := flag.NewFlagSet("migrate "+command, flag.ExitOnError)
+	db := registerDBFlags(flags)
+	steps := flags.Int("steps", 1, "number of the latest migrations reverted by down")
+	timeout := flags.Duration("timeout", 5*time.Minute, "time the migrations may take")
+	flags.Parse(args[1:])
+
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return err
+	case "down":
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Println("no migration is applied")
+		}
+		return err
+	default:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied at " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%-30s %s\n", status.Version, status.Name, state)
+		}
+	}
+
+	return nil
+}
+
+// newMigrator opens the configured database as it is, the sqlite database isn't migrated nor seeded on open
+func newMigrator(db dbConfig) (*repository.Migrator, error) {
+	var r migratable
+	var err error
+	switch *db.driver {
+	case "sqlite":
+		r, err = repository.OpenSQLite(*db.path)
+	case "mysql":
+		r, err = repository.New(*db.user, *db.pass, *db.port)
+	case "postgres":
+		r, err = repository.NewPostgres(*db.user, *db.pass, *db.port)
+	default:
+		return nil, fmt.Errorf("database driver %s has no schema to migrate", *db.driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Migrator()
+}
+
+// migrateUp applies the pending migrations of the repository on start, repositories without a schema are skipped
+func migrateUp(r repository.Repository) error {
+	m, ok := r.(migratable)
+	if !ok {
+		return nil
+	}
+
+	migrator, err := m.Migrator()
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(context.Background())
+	for _, migration := range applied {
+		fmt.Printf("applied migration %04d_%s\n", migration.Version, migration.Name)
+	}
+	return err
+}
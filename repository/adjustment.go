@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"stockpricews/entity"
+	"time"
+)
+
+// priceBeforeFunc returns the last raw price of the symbol strictly before the given date point, false if there is none
+type priceBeforeFunc func(datepoint time.Time) (float64, bool, error)
+
+// adjustQuotes back-adjusts the prices (but not the volumes) of the quotes (ordered by date) loaded for the time slice ending at end, so the
+// whole series is comparable with the latest prices. Every quote before the ex-date of an action is multiplied by:
+//   - 1/ratio for splits and ratio for reverse splits
+//   - 1 - amount/close for cash dividends, where close is the last raw price before the ex-date
+//
+// Dividends are reinvested that way, so the adjusted series is the total return series of the stock. Only actions
+// after the first quote have any effect. The close before the ex-dates after the time slice is loaded by priceBefore.
+func adjustQuotes(quotes []entity.StockQuote, actions []entity.CorporateAction, end time.Time, priceBefore priceBeforeFunc) ([]entity.StockQuote, error) {
+	if len(quotes) == 0 || len(actions) == 0 {
+		return quotes, nil
+	}
+
+	// the latest actions are applied first while walking the quotes backwards
+	actions = append([]entity.CorporateAction{}, actions...)
+	sort.Slice(actions, func(i, j int) bool { return actions[i].ExDate.After(actions[j].ExDate) })
+
+	factors := make([]float64, len(actions))
+	for i, action := range actions {
+		if !action.ExDate.After(quotes[0].Datepoint) {
+			break
+		}
+
+		factor, err := adjustmentFactor(quotes, action, end, priceBefore)
+		if err != nil {
+			return nil, err
+		}
+		factors[i] = factor
+	}
+
+	adjusted := make([]entity.StockQuote, len(quotes))
+	cumulative := float64(1)
+	next := 0
+	for i := len(quotes) - 1; i >= 0; i-- {
+		for next < len(actions) && actions[next].ExDate.After(quotes[i].Datepoint) {
+			cumulative *= factors[next]
+			next++
+		}
+		adjusted[i] = quotes[i]
+		adjusted[i].Price *= cumulative
+		adjusted[i].Open *= cumulative
+		adjusted[i].High *= cumulative
+		adjusted[i].Low *= cumulative
+	}
+
+	return adjusted, nil
+}
+
+func adjustmentFactor(quotes []entity.StockQuote, action entity.CorporateAction, end time.Time, priceBefore priceBeforeFunc) (float64, error) {
+	switch action.Type {
+	case entity.ActionSplit:
+		return 1 / action.Ratio, nil
+	case entity.ActionReverseSplit:
+		return action.Ratio, nil
+	case entity.ActionDividend:
+		var close float64
+		if action.ExDate.After(end) {
+			// the close before the ex-date is outside of the loaded time slice
+			price, ok, err := priceBefore(action.ExDate)
+			if err != nil {
+				return 0, err
+			}
+			if !ok {
+				return 1, nil
+			}
+			close = price
+		} else {
+			i := sort.Search(len(quotes), func(i int) bool { return !quotes[i].Datepoint.Before(action.ExDate) })
+			close = quotes[i-1].Price
+		}
+
+		if action.Amount >= close {
+			return 0, fmt.Errorf("dividend %v of %s exceeds the close price %v before %s", action.Amount, action.Symbol, close, action.ExDate)
+		}
+		return 1 - action.Amount/close, nil
+	default:
+		return 0, fmt.Errorf("unknown corporate action type %s", action.Type)
+	}
+}
+
+// validateCorporateAction checks the action can be applied to the prices
+func validateCorporateAction(action entity.CorporateAction) error {
+	if action.Symbol == "" || action.ExDate.IsZero() {
+		return fmt.Errorf("symbol and ex-date of corporate action are mandatory: %w", entity.ErrBadRequest)
+	}
+
+	switch action.Type {
+	case entity.ActionSplit, entity.ActionReverseSplit:
+		if action.Ratio <= 0 {
+			return fmt.Errorf("ratio of %s must be positive: %w", action.Type, entity.ErrBadRequest)
+		}
+	case entity.ActionDividend:
+		if action.Amount <= 0 {
+			return fmt.Errorf("amount of %s must be positive: %w", action.Type, entity.ErrBadRequest)
+		}
+	default:
+		return fmt.Errorf("unknown corporate action type %s: %w", action.Type, entity.ErrBadRequest)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"stockpricews/entity"
+	"testing"
+	"time"
+)
+
+func TestAdjustQuotes(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2023, time.November, d, 0, 0, 0, 0, time.UTC) }
+	history := func(prices ...float64) []entity.StockQuote {
+		quotes := make([]entity.StockQuote, len(prices))
+		for i, p := range prices {
+			quotes[i] = entity.StockQuote{Symbol: "UBER", Datepoint: day(i + 1), Price: p}
+		}
+		return quotes
+	}
+	prices := func(quotes []entity.StockQuote) []float64 {
+		var p []float64
+		for _, q := range quotes {
+			p = append(p, q.Price)
+		}
+		return p
+	}
+	noPriceBefore := func(time.Time) (float64, bool, error) { return 0, false, nil }
+
+	testCases := []struct {
+		name        string
+		history     []entity.StockQuote
+		actions     []entity.CorporateAction
+		end         time.Time
+		priceBefore priceBeforeFunc
+		expected    []float64
+		expectedErr bool
+	}{
+		{
+			name:     "No corporate actions",
+			history:  history(10, 11, 12),
+			end:      day(10),
+			expected: []float64{10, 11, 12},
+		},
+		{
+			name:     "Split removes the phantom crash",
+			history:  history(100, 102, 51, 52),
+			actions:  []entity.CorporateAction{{Type: entity.ActionSplit, ExDate: day(3), Ratio: 2}},
+			end:      day(10),
+			expected: []float64{50, 51, 51, 52},
+		},
+		{
+			name:     "Reverse split removes the phantom rally",
+			history:  history(1, 1.2, 12, 13),
+			actions:  []entity.CorporateAction{{Type: entity.ActionReverseSplit, ExDate: day(3), Ratio: 10}},
+			end:      day(10),
+			expected: []float64{10, 12, 12, 13},
+		},
+		{
+			name:    "Dividend and split are compounded",
+			history: history(200, 100, 50, 50),
+			actions: []entity.CorporateAction{
+				{Type: entity.ActionDividend, ExDate: day(4), Amount: 5},
+				{Type: entity.ActionSplit, ExDate: day(2), Ratio: 2},
+			},
+			end:      day(10),
+			expected: []float64{90, 90, 45, 50},
+		},
+		{
+			name:     "Actions before the first quote have no effect",
+			history:  history(10, 11),
+			actions:  []entity.CorporateAction{{Type: entity.ActionSplit, ExDate: day(1), Ratio: 2}},
+			end:      day(10),
+			expected: []float64{10, 11},
+		},
+		{
+			name:    "Dividend after the time slice uses the close before the ex-date",
+			history: history(10, 20),
+			actions: []entity.CorporateAction{{Type: entity.ActionDividend, ExDate: day(20), Amount: 2.5}},
+			end:     day(3),
+			priceBefore: func(datepoint time.Time) (float64, bool, error) {
+				assert.Equal(t, day(20), datepoint)
+				return 25, true, nil
+			},
+			expected: []float64{9, 18},
+		},
+		{
+			name:        "Dividend exceeding the price",
+			history:     history(10, 2),
+			actions:     []entity.CorporateAction{{Type: entity.ActionDividend, ExDate: day(2), Amount: 10}},
+			end:         day(10),
+			expectedErr: true,
+		},
+		{
+			name:    "Close before the ex-date can't be loaded",
+			history: history(10, 20),
+			actions: []entity.CorporateAction{{Type: entity.ActionDividend, ExDate: day(20), Amount: 1}},
+			end:     day(3),
+			priceBefore: func(time.Time) (float64, bool, error) {
+				return 0, false, errors.New("connection refused")
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			priceBefore := tt.priceBefore
+			if priceBefore == nil {
+				priceBefore = noPriceBefore
+			}
+
+			got, err := adjustQuotes(tt.history, tt.actions, tt.end, priceBefore)
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.InDeltaSlice(t, tt.expected, prices(got), 1e-9)
+			}
+		})
+	}
+}
+
+func TestValidateCorporateAction(t *testing.T) {
+	exDate := time.Unix(1699228800, 0)
+	assert.NoError(t, validateCorporateAction(entity.CorporateAction{Symbol: "UBER", Type: entity.ActionSplit, ExDate: exDate, Ratio: 2}))
+	assert.NoError(t, validateCorporateAction(entity.CorporateAction{Symbol: "UBER", Type: entity.ActionDividend, ExDate: exDate, Amount: 0.5}))
+
+	for _, action := range []entity.CorporateAction{
+		{Type: entity.ActionSplit, ExDate: exDate, Ratio: 2},
+		{Symbol: "UBER", Type: entity.ActionSplit, Ratio: 2},
+		{Symbol: "UBER", Type: entity.ActionReverseSplit, ExDate: exDate},
+		{Symbol: "UBER", Type: entity.ActionDividend, ExDate: exDate, Amount: -1},
+		{Symbol: "UBER", Type: "merger", ExDate: exDate},
+	} {
+		assert.True(t, errors.Is(validateCorporateAction(action), entity.ErrBadRequest))
+	}
+}
@@ -0,0 +1,336 @@
+package repository
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"stockpricews/entity"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// cacheEntryOverhead approximates the memory held by an entry besides its quotes: the key, the list element and the
+// map slots referencing it
+const cacheEntryOverhead = 256
+
+// maxCachedSymbols bounds the number of described symbols kept, the unknown ones are cached as well so they'd grow
+// without bound otherwise
+const maxCachedSymbols = 4096
+
+// quoteSize approximates the memory held by a cached quote
+var quoteSize = int64(unsafe.Sizeof(entity.StockQuote{}))
+
+// CachingRepository is a read-through cache decorating a Repository. The loaded quotes are kept in an LRU bounded by
+// the approximate memory they take and expire after the TTL. Concurrent identical loads are deduplicated, so a popular
+// time slice hits the decorated repository once. Failed loads aren't cached. Streamed quotes go straight to the
+// decorated repository, they'd only evict the hot time slices. Adjusted time slices aren't cached either, as the
+// corporate actions adjusting them don't go through the cache. The symbol catalog and the described symbols, unknown
+// ones included, are cached for the TTL too, so checking the symbol of every request doesn't hit the database.
+type CachingRepository struct {
+	Repository
+
+	maxBytes int64
+	ttl      time.Duration
+	// now is replaced by tests
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	bytes   int64
+	// keys of the cached entries per symbol for the invalidation
+	symbols map[string]map[string]struct{}
+	// generation of every symbol is increased on invalidation, so loads started before it aren't cached
+	generations map[string]uint64
+	// described symbols and the catalog, dropped on invalidation
+	described         map[string]cachedSymbol
+	catalog           []entity.Symbol
+	catalogExpires    time.Time
+	catalogGeneration uint64
+
+	group  singleflight.Group
+	hits   uint64
+	misses uint64
+}
+
+type cacheEntry struct {
+	key     string
+	symbol  string
+	quotes  []entity.StockQuote
+	size    int64
+	expires time.Time
+}
+
+// cachedSymbol is a described symbol or the entity.ErrNotFound of an unknown one
+type cachedSymbol struct {
+	symbol  entity.Symbol
+	err     error
+	expires time.Time
+}
+
+// CacheStats are the counters of the cache used to tune its size and TTL
+type CacheStats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// NewCache decorates the repository with a cache holding up to maxBytes of quotes for ttl at most
+func NewCache(repository Repository, maxBytes int64, ttl time.Duration) *CachingRepository {
+	return &CachingRepository{
+		Repository:  repository,
+		maxBytes:    maxBytes,
+		ttl:         ttl,
+		now:         time.Now,
+		entries:     map[string]*list.Element{},
+		lru:         list.New(),
+		symbols:     map[string]map[string]struct{}{},
+		generations: map[string]uint64{},
+		described:   map[string]cachedSymbol{},
+	}
+}
+
+// StockQuotesPerTimeSlice serves the raw quotes of the time slice from the cache. The adjusted ones are always loaded
+// from the decorated repository, the corporate actions they depend on are written behind the cache.
+func (c *CachingRepository) StockQuotesPerTimeSlice(ctx context.Context, req entity.StockQuoteRequest) ([]entity.StockQuote, error) {
+	if req.Adjusted {
+		return c.Repository.StockQuotesPerTimeSlice(ctx, req)
+	}
+
+	key := fmt.Sprintf("%s|%d|%d", req.Symbol, req.Begin.UnixNano(), req.End.UnixNano())
+	return c.load(ctx, req.Symbol, key, func(ctx context.Context) ([]entity.StockQuote, error) {
+		return c.Repository.StockQuotesPerTimeSlice(ctx, req)
+	})
+}
+
+// StockQuotesPerSymbol loads the whole stock quote history of the given symbol ordered by date
+func (c *CachingRepository) StockQuotesPerSymbol(ctx context.Context, symbol string) ([]entity.StockQuote, error) {
+	return c.load(ctx, symbol, symbol+"|all", func(ctx context.Context) ([]entity.StockQuote, error) {
+		return c.Repository.StockQuotesPerSymbol(ctx, symbol)
+	})
+}
+
+// SaveStockQuotes stores the quotes in the decorated repository and drops the cached quotes of their symbols
+func (c *CachingRepository) SaveStockQuotes(ctx context.Context, quotes []entity.StockQuote) error {
+	err := c.Repository.SaveStockQuotes(ctx, quotes)
+
+	// invalidated even on failure as the outcome of a canceled commit isn't known
+	invalidated := map[string]bool{}
+	for _, quote := range quotes {
+		if !invalidated[quote.Symbol] {
+			invalidated[quote.Symbol] = true
+			c.Invalidate(quote.Symbol)
+		}
+	}
+
+	return err
+}
+
+// SymbolCatalog serves the symbol catalog from the cache or loads it from the decorated repository
+func (c *CachingRepository) SymbolCatalog(ctx context.Context) ([]entity.Symbol, error) {
+	c.mu.Lock()
+	catalog, expires, generation := c.catalog, c.catalogExpires, c.catalogGeneration
+	c.mu.Unlock()
+	if catalog != nil && c.now().Before(expires) {
+		atomic.AddUint64(&c.hits, 1)
+		return append([]entity.Symbol{}, catalog...), nil
+	}
+	atomic.AddUint64(&c.misses, 1)
+
+	catalog, err := c.Repository.SymbolCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// a symbol got invalidated while loading
+	if c.catalogGeneration == generation {
+		c.catalog = append([]entity.Symbol{}, catalog...)
+		c.catalogExpires = c.now().Add(c.ttl)
+	}
+	return catalog, nil
+}
+
+// DescribeSymbol serves the symbol from the cache or loads it from the decorated repository. Unknown symbols are
+// cached as well, any other failure isn't.
+func (c *CachingRepository) DescribeSymbol(ctx context.Context, symbol string) (entity.Symbol, error) {
+	c.mu.Lock()
+	cached, ok := c.described[symbol]
+	generation := c.generations[symbol]
+	c.mu.Unlock()
+	if ok && c.now().Before(cached.expires) {
+		atomic.AddUint64(&c.hits, 1)
+		return cached.symbol, cached.err
+	}
+	atomic.AddUint64(&c.misses, 1)
+
+	s, err := c.Repository.DescribeSymbol(ctx, symbol)
+	if err != nil && !errors.Is(err, entity.ErrNotFound) {
+		return s, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generations[symbol] == generation {
+		if len(c.described) >= maxCachedSymbols {
+			c.described = map[string]cachedSymbol{}
+		}
+		c.described[symbol] = cachedSymbol{symbol: s, err: err, expires: c.now().Add(c.ttl)}
+	}
+	return s, err
+}
+
+// AddSymbol stores the symbol in the decorated repository and drops the cached catalog and the cached lookup of the
+// symbol, as it's most likely cached as unknown
+func (c *CachingRepository) AddSymbol(ctx context.Context, symbol entity.Symbol) error {
+	err := c.Repository.AddSymbol(ctx, symbol)
+	c.Invalidate(symbol.Symbol)
+	return err
+}
+
+// Invalidate drops the cached quotes and metadata of the symbol, the next queries load them from the decorated
+// repository
+func (c *CachingRepository) Invalidate(symbol string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generations[symbol]++
+	for key := range c.symbols[symbol] {
+		c.remove(c.entries[key])
+	}
+	// the date points of the symbol change with its quotes
+	delete(c.described, symbol)
+	c.catalog = nil
+	c.catalogGeneration++
+}
+
+// Stats returns the current counters of the cache
+func (c *CachingRepository) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{
+		Hits:    atomic.LoadUint64(&c.hits),
+		Misses:  atomic.LoadUint64(&c.misses),
+		Entries: len(c.entries),
+		Bytes:   c.bytes,
+	}
+}
+
+// load serves the quotes from the cache or loads them once for all concurrent callers. The shared load runs with the
+// context of the caller that started it, every caller still stops waiting as soon as its own context is done.
+func (c *CachingRepository) load(ctx context.Context, symbol, key string, load func(ctx context.Context) ([]entity.StockQuote, error)) ([]entity.StockQuote, error) {
+	if quotes, ok := c.get(key); ok {
+		atomic.AddUint64(&c.hits, 1)
+		return quotes, nil
+	}
+	atomic.AddUint64(&c.misses, 1)
+
+	c.mu.Lock()
+	generation := c.generations[symbol]
+	c.mu.Unlock()
+
+	results := c.group.DoChan(key, func() (interface{}, error) {
+		quotes, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.put(symbol, key, quotes, generation)
+		return quotes, nil
+	})
+
+	var result singleflight.Result
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result = <-results:
+	}
+
+	if result.Err != nil {
+		if ctx.Err() == nil && (errors.Is(result.Err, context.Canceled) || errors.Is(result.Err, context.DeadlineExceeded)) {
+			// the caller that started the load went away, the rest shouldn't fail because of it
+			return load(ctx)
+		}
+		return nil, result.Err
+	}
+
+	// callers sharing the load get their own copy, same as the cached quotes
+	return copyQuotes(result.Val.([]entity.StockQuote)), nil
+}
+
+func (c *CachingRepository) get(key string) ([]entity.StockQuote, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if !c.now().Before(entry.expires) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(element)
+	return copyQuotes(entry.quotes), true
+}
+
+func (c *CachingRepository) put(symbol, key string, quotes []entity.StockQuote, generation uint64) {
+	entry := &cacheEntry{
+		key:     key,
+		symbol:  symbol,
+		quotes:  copyQuotes(quotes),
+		size:    cacheEntryOverhead + int64(len(quotes))*quoteSize,
+		expires: c.now().Add(c.ttl),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the symbol got invalidated while loading or the quotes don't fit at all
+	if c.generations[symbol] != generation || entry.size > c.maxBytes {
+		return
+	}
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	c.bytes += entry.size
+	if c.symbols[symbol] == nil {
+		c.symbols[symbol] = map[string]struct{}{}
+	}
+	c.symbols[symbol][key] = struct{}{}
+
+	for c.bytes > c.maxBytes {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove drops the entry of the element, the caller must hold the lock
+func (c *CachingRepository) remove(element *list.Element) {
+	entry := element.Value.(*cacheEntry)
+	c.lru.Remove(element)
+	delete(c.entries, entry.key)
+	delete(c.symbols[entry.symbol], entry.key)
+	if len(c.symbols[entry.symbol]) == 0 {
+		delete(c.symbols, entry.symbol)
+	}
+	c.bytes -= entry.size
+}
+
+func copyQuotes(quotes []entity.StockQuote) []entity.StockQuote {
+	if quotes == nil {
+		return nil
+	}
+	return append([]entity.StockQuote{}, quotes...)
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"stockpricews/entity"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingRepository counts the loads reaching the decorated repository and optionally blocks them
+type countingRepository struct {
+	*MemoryRepository
+	loads   int32
+	release chan struct{}
+	err     error
+}
+
+func (r *countingRepository) StockQuotesPerTimeSlice(ctx context.Context, req entity.StockQuoteRequest) ([]entity.StockQuote, error) {
+	atomic.AddInt32(&r.loads, 1)
+	if r.release != nil {
+		<-r.release
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.MemoryRepository.StockQuotesPerTimeSlice(ctx, req)
+}
+
+func (r *countingRepository) DescribeSymbol(ctx context.Context, symbol string) (entity.Symbol, error) {
+	atomic.AddInt32(&r.loads, 1)
+	return r.MemoryRepository.DescribeSymbol(ctx, symbol)
+}
+
+func (r *countingRepository) SymbolCatalog(ctx context.Context) ([]entity.Symbol, error) {
+	atomic.AddInt32(&r.loads, 1)
+	return r.MemoryRepository.SymbolCatalog(ctx)
+}
+
+func TestCachingRepository(t *testing.T) {
+	day := time.Unix(1699228800, 0)
+	repo := &countingRepository{MemoryRepository: NewMemory(
+		entity.StockQuote{Symbol: "UBER", Datepoint: day, Price: 10},
+		entity.StockQuote{Symbol: "TSLA", Datepoint: day, Price: 200},
+	)}
+	cache := NewCache(repo, 1<<20, time.Minute)
+	now := day
+	cache.now = func() time.Time { return now }
+
+	uber := entity.StockQuoteRequest{Symbol: "UBER", Begin: day.Add(-time.Hour), End: day.Add(time.Hour)}
+	tsla := entity.StockQuoteRequest{Symbol: "TSLA", Begin: day.Add(-time.Hour), End: day.Add(time.Hour)}
+
+	t.Run("Hits are served from the cache", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			quotes, err := cache.StockQuotesPerTimeSlice(context.Background(), uber)
+			assert.NoError(t, err)
+			assert.Equal(t, 10.0, quotes[0].Price)
+			// callers can't modify the cached quotes
+			quotes[0].Price = 0
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&repo.loads))
+		stats := cache.Stats()
+		assert.Equal(t, uint64(2), stats.Hits)
+		assert.Equal(t, uint64(1), stats.Misses)
+		assert.Equal(t, 1, stats.Entries)
+	})
+
+	t.Run("Invalidation drops the symbol only", func(t *testing.T) {
+		_, err := cache.StockQuotesPerTimeSlice(context.Background(), tsla)
+		assert.NoError(t, err)
+		repo.Append(entity.StockQuote{Symbol: "UBER", Datepoint: day, Price: 11})
+		cache.Invalidate("UBER")
+
+		loads := atomic.LoadInt32(&repo.loads)
+		quotes, err := cache.StockQuotesPerTimeSlice(context.Background(), uber)
+		assert.NoError(t, err)
+		assert.Equal(t, 11.0, quotes[0].Price)
+		_, err = cache.StockQuotesPerTimeSlice(context.Background(), tsla)
+		assert.NoError(t, err)
+		assert.Equal(t, loads+1, atomic.LoadInt32(&repo.loads))
+	})
+
+	t.Run("Saving quotes invalidates their symbols", func(t *testing.T) {
+		assert.NoError(t, cache.SaveStockQuotes(context.Background(), []entity.StockQuote{{Symbol: "UBER", Datepoint: day, Price: 12}}))
+
+		loads := atomic.LoadInt32(&repo.loads)
+		quotes, err := cache.StockQuotesPerTimeSlice(context.Background(), uber)
+		assert.NoError(t, err)
+		assert.Equal(t, 12.0, quotes[0].Price)
+		assert.Equal(t, loads+1, atomic.LoadInt32(&repo.loads))
+	})
+
+	t.Run("Entries expire after TTL", func(t *testing.T) {
+		loads := atomic.LoadInt32(&repo.loads)
+		now = now.Add(time.Minute)
+		_, err := cache.StockQuotesPerTimeSlice(context.Background(), uber)
+		assert.NoError(t, err)
+		assert.Equal(t, loads+1, atomic.LoadInt32(&repo.loads))
+	})
+
+	t.Run("Errors are not cached", func(t *testing.T) {
+		repo.err = errors.New("connection refused")
+		req := entity.StockQuoteRequest{Symbol: "UBER", Begin: day, End: day.Add(time.Hour)}
+		_, err := cache.StockQuotesPerTimeSlice(context.Background(), req)
+		assert.True(t, errors.Is(err, repo.err))
+		repo.err = nil
+		_, err = cache.StockQuotesPerTimeSlice(context.Background(), req)
+		assert.NoError(t, err)
+	})
+}
+
+func TestCachingRepository_LRU(t *testing.T) {
+	day := time.Unix(1699228800, 0)
+	repo := &countingRepository{MemoryRepository: NewMemory(entity.StockQuote{Symbol: "UBER", Datepoint: day, Price: 10})}
+	entrySize := cacheEntryOverhead + quoteSize
+	cache := NewCache(repo, 2*entrySize, time.Minute)
+
+	req := func(i int) entity.StockQuoteRequest {
+		return entity.StockQuoteRequest{Symbol: "UBER", Begin: day.Add(-time.Hour * time.Duration(i+1)), End: day.Add(time.Hour)}
+	}
+	for i := 0; i < 2; i++ {
+		_, err := cache.StockQuotesPerTimeSlice(context.Background(), req(i))
+		assert.NoError(t, err)
+	}
+	// touch the first one so the second one is the least recently used
+	_, _ = cache.StockQuotesPerTimeSlice(context.Background(), req(0))
+	_, _ = cache.StockQuotesPerTimeSlice(context.Background(), req(2))
+
+	stats := cache.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, 2*entrySize, stats.Bytes)
+
+	loads := atomic.LoadInt32(&repo.loads)
+	_, _ = cache.StockQuotesPerTimeSlice(context.Background(), req(0))
+	assert.Equal(t, loads, atomic.LoadInt32(&repo.loads))
+	_, _ = cache.StockQuotesPerTimeSlice(context.Background(), req(1))
+	assert.Equal(t, loads+1, atomic.LoadInt32(&repo.loads))
+}
+
+func TestCachingRepository_Singleflight(t *testing.T) {
+	day := time.Unix(1699228800, 0)
+	repo := &countingRepository{
+		MemoryRepository: NewMemory(entity.StockQuote{Symbol: "UBER", Datepoint: day, Price: 10}),
+		release:          make(chan struct{}),
+	}
+	cache := NewCache(repo, 1<<20, time.Minute)
+	req := entity.StockQuoteRequest{Symbol: "UBER", Begin: day.Add(-time.Hour), End: day.Add(time.Hour)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			quotes, err := cache.StockQuotesPerTimeSlice(context.Background(), req)
+			assert.NoError(t, err)
+			assert.Len(t, quotes, 1)
+		}()
+	}
+
+	// wait for the first load to reach the repository and give the rest the time to join it
+	for atomic.LoadInt32(&repo.loads) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(time.Millisecond * 20)
+	close(repo.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&repo.loads))
+}
+
+func TestCachingRepository_Symbols(t *testing.T) {
+	day := time.Unix(1699228800, 0)
+	repo := &countingRepository{MemoryRepository: NewMemory(entity.StockQuote{Symbol: "UBER", Datepoint: day, Price: 10})}
+	cache := NewCache(repo, 1<<20, time.Minute)
+	now := day
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		s, err := cache.DescribeSymbol(ctx, "UBER")
+		assert.NoError(t, err)
+		assert.Equal(t, day, *s.LastDatapoint)
+		_, err = cache.DescribeSymbol(ctx, "TSLA")
+		assert.True(t, errors.Is(err, entity.ErrNotFound))
+		catalog, err := cache.SymbolCatalog(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, catalog, 1)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&repo.loads))
+
+	// stored quotes move the date points of their symbol
+	assert.NoError(t, cache.SaveStockQuotes(ctx, []entity.StockQuote{{Symbol: "UBER", Datepoint: day.Add(time.Hour), Price: 11}}))
+	s, err := cache.DescribeSymbol(ctx, "UBER")
+	assert.NoError(t, err)
+	assert.Equal(t, day.Add(time.Hour), *s.LastDatapoint)
+
+	// added symbols aren't reported unknown any more
+	assert.NoError(t, cache.AddSymbol(ctx, entity.Symbol{Symbol: "TSLA", Timezone: "UTC", Status: entity.SymbolListed}))
+	_, err = cache.DescribeSymbol(ctx, "TSLA")
+	assert.NoError(t, err)
+	catalog, err := cache.SymbolCatalog(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, catalog, 2)
+	assert.Equal(t, int32(6), atomic.LoadInt32(&repo.loads))
+
+	// symbols added by other processes are picked up after the TTL
+	_, err = cache.DescribeSymbol(ctx, "AAPL")
+	assert.True(t, errors.Is(err, entity.ErrNotFound))
+	assert.NoError(t, repo.MemoryRepository.AddSymbol(ctx, entity.Symbol{Symbol: "AAPL", Timezone: "UTC", Status: entity.SymbolListed}))
+	_, err = cache.DescribeSymbol(ctx, "AAPL")
+	assert.True(t, errors.Is(err, entity.ErrNotFound))
+	now = now.Add(time.Minute)
+	_, err = cache.DescribeSymbol(ctx, "AAPL")
+	assert.NoError(t, err)
+
+	// other failures aren't cached
+	_, err = cache.DescribeSymbol(ctx, "BRK.B")
+	assert.True(t, errors.Is(err, entity.ErrNotFound))
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = cache.DescribeSymbol(canceled, "MSFT")
+	assert.True(t, errors.Is(err, context.Canceled))
+	_, err = cache.DescribeSymbol(ctx, "MSFT")
+	assert.True(t, errors.Is(err, entity.ErrNotFound))
+}
+
+func TestCachingRepository_Adjusted(t *testing.T) {
+	day := time.Unix(1699228800, 0)
+	repo := &countingRepository{MemoryRepository: NewMemory(
+		entity.StockQuote{Symbol: "UBER", Datepoint: day, Price: 100},
+		entity.StockQuote{Symbol: "UBER", Datepoint: day.AddDate(0, 0, 2), Price: 50},
+	)}
+	cache := NewCache(repo, 1<<20, time.Minute)
+	req := entity.StockQuoteRequest{Symbol: "UBER", Begin: day.Add(-time.Hour), End: day.AddDate(0, 0, 3), Adjusted: true}
+
+	quotes, err := cache.StockQuotesPerTimeSlice(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, quotes[0].Price)
+
+	// a split added behind the cache adjusts the next query right away
+	_, err = repo.AddCorporateAction(context.Background(), entity.CorporateAction{Symbol: "UBER", Type: entity.ActionSplit, ExDate: day.AddDate(0, 0, 1), Ratio: 2})
+	assert.NoError(t, err)
+	quotes, err = cache.StockQuotesPerTimeSlice(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 50.0, quotes[0].Price)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&repo.loads))
+	assert.Equal(t, 0, cache.Stats().Entries)
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"stockpricews/entity"
+	"time"
+)
+
+// Corporate actions are stored in corporate_action table, created by migration 0002_create_corporate_action of
+// repository/migrations/mysql and applied with the migrate subcommand like the rest of the schema.
+const (
+	getCorporateActions      = "SELECT id, symbol, type, ex_date, ratio, amount FROM corporate_action WHERE symbol = ? ORDER BY ex_date ASC"
+	getCorporateActionsAfter = "SELECT id, symbol, type, ex_date, ratio, amount FROM corporate_action WHERE symbol = ? AND ex_date > ? ORDER BY ex_date ASC"
+	insertCorporateAction    = "INSERT INTO corporate_action (symbol, type, ex_date, ratio, amount) VALUES (?, ?, ?, ?, ?)"
+	deleteCorporateAction    = "DELETE FROM corporate_action WHERE id = ?"
+	getPriceBefore           = "SELECT price FROM stock_quote WHERE symbol = ? AND datepoint < ? ORDER BY datepoint DESC LIMIT 1"
+)
+
+// CorporateActions returns all corporate actions of the symbol ordered by ex-date
+func (r DBRepository) CorporateActions(ctx context.Context, symbol string) ([]entity.CorporateAction, error) {
+	rows, err := r.db.QueryContext(ctx, getCorporateActions, symbol)
+	if err != nil {
+		return []entity.CorporateAction{}, err
+	}
+
+	return scanCorporateActions(rows)
+}
+
+// AddCorporateAction stores the corporate action and returns its id
+func (r DBRepository) AddCorporateAction(ctx context.Context, action entity.CorporateAction) (int64, error) {
+	if err := validateCorporateAction(action); err != nil {
+		return 0, err
+	}
+
+	ratio := action.Ratio
+	if action.Type == entity.ActionDividend {
+		ratio = 1
+	}
+
+	res, err := r.db.ExecContext(ctx, insertCorporateAction, action.Symbol, string(action.Type),
+		action.ExDate.UTC().Format("2006-01-02 15:04:05"), ratio, action.Amount)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// DeleteCorporateAction removes the corporate action with the given id
+func (r DBRepository) DeleteCorporateAction(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, deleteCorporateAction, id)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("corporate action %d: %w", id, entity.ErrNotFound)
+	}
+
+	return nil
+}
+
+// adjust back-adjusts the quotes loaded for the given request by the corporate actions of the symbol
+func (r DBRepository) adjust(ctx context.Context, req entity.StockQuoteRequest, quotes []entity.StockQuote) ([]entity.StockQuote, error) {
+	return adjustFromDB(ctx, r.db, getCorporateActionsAfter, getPriceBefore, func(datepoint time.Time) interface{} {
+		return datepoint.UTC().Format("2006-01-02 15:04:05")
+	}, req, quotes)
+}
+
+// adjustFromDB back-adjusts the quotes by the corporate actions loaded with the given queries. The queries take the
+// symbol and a date point converted to the query argument by timeArg, so they can be shared by the SQL dialects.
+func adjustFromDB(ctx context.Context, db *sql.DB, actionsAfter, priceBefore string, timeArg func(time.Time) interface{},
+	req entity.StockQuoteRequest, quotes []entity.StockQuote) ([]entity.StockQuote, error) {
+	if len(quotes) == 0 {
+		return quotes, nil
+	}
+
+	rows, err := db.QueryContext(ctx, actionsAfter, req.Symbol, timeArg(quotes[0].Datepoint))
+	if err != nil {
+		return nil, err
+	}
+
+	actions, err := scanCorporateActions(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return adjustQuotes(quotes, actions, req.End, func(datepoint time.Time) (float64, bool, error) {
+		var price float64
+		err := db.QueryRowContext(ctx, priceBefore, req.Symbol, timeArg(datepoint)).Scan(&price)
+		if err == sql.ErrNoRows {
+			return 0, false, nil
+		}
+		return price, err == nil, err
+	})
+}
+
+func scanCorporateActions(rows *sql.Rows) ([]entity.CorporateAction, error) {
+	defer rows.Close()
+
+	var actions []entity.CorporateAction
+	for rows.Next() {
+		action := entity.CorporateAction{}
+		if err := rows.Scan(&action.ID, &action.Symbol, &action.Type, &action.ExDate, &action.Ratio, &action.Amount); err != nil {
+			return actions, err
+		}
+		action.ExDate = action.ExDate.UTC()
+		actions = append(actions, action)
+	}
+
+	return actions, rows.Err()
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"fmt"
+	"io"
+	"stockpricews/entity"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// quoteTimeLayouts are the accepted formats of the date points in quote files, besides unix seconds
+var quoteTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+// readQuotesCSV reads the quotes of a CSV file with a header row. The symbol, datepoint (or date) and price (or close)
+// columns are mandatory, open, high, low and volume are optional. Columns are matched by name in any order and
+// date points without a time zone are read as UTC.
+func readQuotesCSV(r io.Reader) ([]entity.StockQuote, error) {
+	reader, err := NewQuoteReader(r, entity.FormatCSV)
+	if err != nil {
+		return nil, err
+	}
+
+	var quotes []entity.StockQuote
+	for {
+		quote, err := reader.Read()
+		if err == io.EOF {
+			return quotes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, quote)
+	}
+}
+
+func parseQuoteRecord(record []string, columns map[string]int) (entity.StockQuote, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	quote := entity.StockQuote{Symbol: field("symbol")}
+	if quote.Symbol == "" {
+		return entity.StockQuote{}, fmt.Errorf("symbol is empty")
+	}
+
+	var err error
+	if quote.Datepoint, err = parseQuoteTime(field("datepoint")); err != nil {
+		return entity.StockQuote{}, err
+	}
+
+	prices := []struct {
+		name  string
+		value *float64
+	}{{"price", &quote.Price}, {"open", &quote.Open}, {"high", &quote.High}, {"low", &quote.Low}}
+	for _, price := range prices {
+		value := field(price.name)
+		if value == "" && price.name != "price" {
+			continue
+		}
+		if *price.value, err = strconv.ParseFloat(value, 64); err != nil {
+			return entity.StockQuote{}, fmt.Errorf("%s %q is not a number", price.name, value)
+		}
+	}
+
+	if volume := field("volume"); volume != "" {
+		if quote.Volume, err = strconv.ParseInt(volume, 10, 64); err != nil {
+			return entity.StockQuote{}, fmt.Errorf("volume %q is not an integer", volume)
+		}
+	}
+
+	return quote, nil
+}
+
+// parseQuoteTime parses the date point of a quote file in any of the quoteTimeLayouts or as unix seconds
+func parseQuoteTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("datepoint is missing")
+	}
+
+	for _, layout := range quoteTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(secs, 0).UTC(), nil
+	}
+
+	return time.Time{}, fmt.Errorf("datepoint %q has unknown format", value)
+}
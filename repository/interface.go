@@ -1,10 +1,34 @@
 package repository
 
 import (
+	"context"
 	"stockpricews/entity"
 )
 
-// Repository an interface for loading stock quotes for given time period
+// Repository an interface for loading stock quotes for given time period. The queries are aborted when the context
+// is done, e.g. the client went away or the deadline of the request expired.
 type Repository interface {
-	StockQuotesPerTimeSlice(timeSlice entity.StockQuoteRequest) ([]entity.StockQuote, error)
+	StockQuotesPerTimeSlice(ctx context.Context, timeSlice entity.StockQuoteRequest) ([]entity.StockQuote, error)
+	StockQuotesPerSymbol(ctx context.Context, symbol string) ([]entity.StockQuote, error)
+	Symbols(ctx context.Context) ([]string, error)
+	// EachStockQuote streams the quotes of the time slice ordered by date point to fn straight from the query, without
+	// loading them all. At most timeSlice.Limit quotes are queried if it's set. An error of fn stops the streaming and is
+	// returned.
+	EachStockQuote(ctx context.Context, timeSlice entity.StockQuoteRequest, fn func(entity.StockQuote) error) error
+	// SaveStockQuotes upserts the quotes by symbol and date point in a single transaction, either all of them are
+	// stored or none. The unknown symbols of the quotes are registered in the catalog, so every quoted symbol is described.
+	SaveStockQuotes(ctx context.Context, quotes []entity.StockQuote) error
+	// SymbolCatalog lists the symbols of the catalog in alphabetical order with their first and last date points
+	SymbolCatalog(ctx context.Context) ([]entity.Symbol, error)
+	// DescribeSymbol returns a single symbol of the catalog, entity.ErrNotFound if it isn't in there
+	DescribeSymbol(ctx context.Context, symbol string) (entity.Symbol, error)
+	// AddSymbol adds a symbol to the catalog, entity.ErrConflict if it's in there already
+	AddSymbol(ctx context.Context, symbol entity.Symbol) error
+}
+
+// CorporateActionRepository an interface for managing the corporate actions (splits and dividends) of the symbols
+type CorporateActionRepository interface {
+	CorporateActions(ctx context.Context, symbol string) ([]entity.CorporateAction, error)
+	AddCorporateAction(ctx context.Context, action entity.CorporateAction) (int64, error)
+	DeleteCorporateAction(ctx context.Context, id int64) error
 }
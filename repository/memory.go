@@ -0,0 +1,378 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"stockpricews/entity"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryRepository is the Repository implementation holding the quotes in memory, in per-symbol slices ordered by
+// date point, so time slices are looked up by binary search. It's meant for tests and demos, it's safe for concurrent
+// use and the quotes can be loaded from CSV or JSON files.
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	quotes  map[string][]entity.StockQuote
+	actions map[string][]entity.CorporateAction
+	catalog map[string]entity.Symbol
+	lastID  int64
+}
+
+// jsonQuote is a quote of a JSON file or body, the date point is accepted in the same formats as in CSV files, either
+// as a string or as a number of unix seconds
+type jsonQuote struct {
+	Symbol    string          `json:"symbol"`
+	Datepoint json.RawMessage `json:"datepoint"`
+	Date      json.RawMessage `json:"date"`
+	Price     *float64        `json:"price"`
+	Close     *float64        `json:"close"`
+	Open      float64         `json:"open"`
+	High      float64         `json:"high"`
+	Low       float64         `json:"low"`
+	Volume    int64           `json:"volume"`
+}
+
+func (q jsonQuote) quote() (entity.StockQuote, error) {
+	if q.Symbol == "" {
+		return entity.StockQuote{}, fmt.Errorf("symbol is empty")
+	}
+
+	datepoint := q.Datepoint
+	if len(datepoint) == 0 {
+		datepoint = q.Date
+	}
+	value := string(datepoint)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+	t, err := parseQuoteTime(value)
+	if err != nil {
+		return entity.StockQuote{}, err
+	}
+
+	price := q.Price
+	if price == nil {
+		price = q.Close
+	}
+	if price == nil {
+		return entity.StockQuote{}, fmt.Errorf("price is missing")
+	}
+
+	return entity.StockQuote{Symbol: q.Symbol, Datepoint: t, Price: *price,
+		Open: q.Open, High: q.High, Low: q.Low, Volume: q.Volume}, nil
+}
+
+// ParseJSONQuote parses a single quote of a JSON file or body, the fields are the same as the columns of CSV files
+func ParseJSONQuote(data []byte) (entity.StockQuote, error) {
+	var record jsonQuote
+	if err := json.Unmarshal(data, &record); err != nil {
+		return entity.StockQuote{}, fmt.Errorf("quote is not a valid json object")
+	}
+	return record.quote()
+}
+
+// NewMemory initializes a new in-memory repository holding the given quotes
+func NewMemory(quotes ...entity.StockQuote) *MemoryRepository {
+	r := &MemoryRepository{
+		quotes:  map[string][]entity.StockQuote{},
+		actions: map[string][]entity.CorporateAction{},
+		catalog: map[string]entity.Symbol{},
+	}
+	r.Append(quotes...)
+	return r
+}
+
+// NewMemoryFromFile initializes a new in-memory repository with the quotes of the given .csv or .json file
+func NewMemoryFromFile(path string) (*MemoryRepository, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r := NewMemory()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		err = r.LoadCSV(file)
+	case ".json":
+		err = r.LoadJSON(file)
+	default:
+		err = fmt.Errorf("unsupported file type %s, csv or json expected", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+
+	return r, nil
+}
+
+// LoadCSV appends the quotes of a CSV file, see readQuotesCSV for the format
+func (r *MemoryRepository) LoadCSV(reader io.Reader) error {
+	quotes, err := readQuotesCSV(reader)
+	if err != nil {
+		return err
+	}
+
+	r.Append(quotes...)
+	return nil
+}
+
+// LoadJSON appends the quotes of a JSON array of objects with the same fields as the columns of CSV files
+func (r *MemoryRepository) LoadJSON(reader io.Reader) error {
+	var records []jsonQuote
+	if err := json.NewDecoder(reader).Decode(&records); err != nil {
+		return err
+	}
+
+	quotes := make([]entity.StockQuote, len(records))
+	for i, record := range records {
+		quote, err := record.quote()
+		if err != nil {
+			return fmt.Errorf("quote %d: %w", i, err)
+		}
+		quotes[i] = quote
+	}
+
+	r.Append(quotes...)
+	return nil
+}
+
+// Append stores the quotes in the order of their date points. A quote replaces the stored one of the same symbol and
+// date point. Quotes without an ID get the next free one, the unknown symbols are registered in the catalog. The missing
+// prices are completed by CompletePrices, so the quotes read back are the same as the ones of the SQL databases.
+func (r *MemoryRepository) Append(quotes ...entity.StockQuote) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, quote := range quotes {
+		quote = CompletePrices(quote)
+		if quote.ID == 0 {
+			r.lastID++
+			quote.ID = r.lastID
+		} else if quote.ID > r.lastID {
+			r.lastID = quote.ID
+		}
+
+		if _, ok := r.catalog[quote.Symbol]; !ok {
+			r.catalog[quote.Symbol] = defaultSymbol(quote.Symbol)
+		}
+
+		history := r.quotes[quote.Symbol]
+		i := sort.Search(len(history), func(i int) bool { return !history[i].Datepoint.Before(quote.Datepoint) })
+		switch {
+		case i < len(history) && history[i].Datepoint.Equal(quote.Datepoint):
+			history[i] = quote
+		case i == len(history):
+			// the common case of a new quote, no need to shift anything
+			history = append(history, quote)
+		default:
+			history = append(history, entity.StockQuote{})
+			copy(history[i+1:], history[i:])
+			history[i] = quote
+		}
+		r.quotes[quote.Symbol] = history
+	}
+}
+
+func (r *MemoryRepository) StockQuotesPerTimeSlice(ctx context.Context, req entity.StockQuoteRequest) ([]entity.StockQuote, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// both boundaries are exclusive, same as the SQL repositories
+	history := r.quotes[req.Symbol]
+	from := sort.Search(len(history), func(i int) bool { return history[i].Datepoint.After(req.Begin) })
+	to := sort.Search(len(history), func(i int) bool { return !history[i].Datepoint.Before(req.End) })
+	if from >= to {
+		return nil, nil
+	}
+
+	quotes := append([]entity.StockQuote{}, history[from:to]...)
+	if !req.Adjusted {
+		return quotes, nil
+	}
+
+	return adjustQuotes(quotes, r.actions[req.Symbol], req.End, func(datepoint time.Time) (float64, bool, error) {
+		i := sort.Search(len(history), func(i int) bool { return !history[i].Datepoint.Before(datepoint) })
+		if i == 0 {
+			return 0, false, nil
+		}
+		return history[i-1].Price, true, nil
+	})
+}
+
+// StockQuotesPerSymbol returns the whole stock quote history of the given symbol ordered by date
+func (r *MemoryRepository) StockQuotesPerSymbol(ctx context.Context, symbol string) ([]entity.StockQuote, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.quotes[symbol]) == 0 {
+		return nil, nil
+	}
+	return append([]entity.StockQuote{}, r.quotes[symbol]...), nil
+}
+
+// EachStockQuote passes the quotes of the time slice ordered by date to fn. They're copied first, so a slow fn doesn't
+// block the writers.
+func (r *MemoryRepository) EachStockQuote(ctx context.Context, req entity.StockQuoteRequest, fn func(entity.StockQuote) error) error {
+	quotes, err := r.StockQuotesPerTimeSlice(ctx, entity.StockQuoteRequest{Symbol: req.Symbol, Begin: req.Begin, End: req.End})
+	if err != nil {
+		return err
+	}
+	if req.Limit > 0 && len(quotes) > req.Limit {
+		quotes = quotes[:req.Limit]
+	}
+
+	return eachQuote(ctx, quotes, fn)
+}
+
+// Symbols returns all symbols that have stock quotes stored in alphabetical order
+func (r *MemoryRepository) Symbols(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	symbols := make([]string, 0, len(r.quotes))
+	for symbol, history := range r.quotes {
+		if len(history) > 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+
+	return symbols, nil
+}
+
+// SaveStockQuotes upserts the quotes by symbol and date point
+func (r *MemoryRepository) SaveStockQuotes(ctx context.Context, quotes []entity.StockQuote) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Append(quotes...)
+	return nil
+}
+
+// SymbolCatalog returns all known symbols in alphabetical order
+func (r *MemoryRepository) SymbolCatalog(ctx context.Context) ([]entity.Symbol, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	symbols := make([]entity.Symbol, 0, len(r.catalog))
+	for symbol := range r.catalog {
+		symbols = append(symbols, r.describe(symbol))
+	}
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].Symbol < symbols[j].Symbol })
+
+	return symbols, nil
+}
+
+// DescribeSymbol returns the symbol from the catalog, entity.ErrNotFound if it's unknown
+func (r *MemoryRepository) DescribeSymbol(ctx context.Context, symbol string) (entity.Symbol, error) {
+	if err := ctx.Err(); err != nil {
+		return entity.Symbol{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.catalog[symbol]; !ok {
+		return entity.Symbol{}, fmt.Errorf("unknown symbol %s: %w", symbol, entity.ErrNotFound)
+	}
+	return r.describe(symbol), nil
+}
+
+// AddSymbol stores the symbol in the catalog, entity.ErrConflict if it's known already
+func (r *MemoryRepository) AddSymbol(ctx context.Context, symbol entity.Symbol) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.catalog[symbol.Symbol]; ok {
+		return fmt.Errorf("symbol %s exists already: %w", symbol.Symbol, entity.ErrConflict)
+	}
+	symbol.FirstDatapoint, symbol.LastDatapoint = nil, nil
+	r.catalog[symbol.Symbol] = symbol
+	return nil
+}
+
+// describe returns the catalog entry of the symbol with the date points of its quotes, the lock must be held
+func (r *MemoryRepository) describe(symbol string) entity.Symbol {
+	s := r.catalog[symbol]
+	if history := r.quotes[symbol]; len(history) > 0 {
+		first, last := history[0].Datepoint, history[len(history)-1].Datepoint
+		s.FirstDatapoint, s.LastDatapoint = &first, &last
+	}
+	return s
+}
+
+// CorporateActions returns all corporate actions of the symbol ordered by ex-date
+func (r *MemoryRepository) CorporateActions(ctx context.Context, symbol string) ([]entity.CorporateAction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]entity.CorporateAction{}, r.actions[symbol]...), nil
+}
+
+// AddCorporateAction stores the corporate action and returns its id
+func (r *MemoryRepository) AddCorporateAction(ctx context.Context, action entity.CorporateAction) (int64, error) {
+	if err := validateCorporateAction(action); err != nil {
+		return 0, err
+	}
+	if action.Type == entity.ActionDividend {
+		action.Ratio = 1
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	action.ID = r.lastID
+	actions := append(r.actions[action.Symbol], action)
+	sort.SliceStable(actions, func(i, j int) bool { return actions[i].ExDate.Before(actions[j].ExDate) })
+	r.actions[action.Symbol] = actions
+
+	return action.ID, nil
+}
+
+// DeleteCorporateAction removes the corporate action with the given id
+func (r *MemoryRepository) DeleteCorporateAction(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for symbol, actions := range r.actions {
+		for i, action := range actions {
+			if action.ID == id {
+				r.actions[symbol] = append(actions[:i:i], actions[i+1:]...)
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("corporate action %d: %w", id, entity.ErrNotFound)
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"stockpricews/entity"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryRepository_StockQuotesPerTimeSlice(t *testing.T) {
+	day := time.Unix(1699228800, 0)
+	quote := func(symbol string, days int, price float64) entity.StockQuote {
+		return entity.StockQuote{Symbol: symbol, Datepoint: day.Add(time.Hour * 24 * time.Duration(days)), Price: price}
+	}
+
+	// quotes out of order are sorted by date point, the same date point is replaced
+	repo := NewMemory(quote("UBER", 2, 12), quote("UBER", 0, 10), quote("TSLA", 1, 200), quote("UBER", 1, 11), quote("UBER", 2, 13))
+
+	testCases := []struct {
+		name     string
+		req      entity.StockQuoteRequest
+		expected []float64
+	}{
+		{
+			name:     "Boundaries are exclusive",
+			req:      entity.StockQuoteRequest{Symbol: "UBER", Begin: day, End: day.Add(time.Hour * 48)},
+			expected: []float64{11},
+		},
+		{
+			name:     "Whole history",
+			req:      entity.StockQuoteRequest{Symbol: "UBER", Begin: day.Add(-time.Hour), End: day.Add(time.Hour * 72)},
+			expected: []float64{10, 11, 13},
+		},
+		{
+			name: "Empty time slice",
+			req:  entity.StockQuoteRequest{Symbol: "UBER", Begin: day.Add(time.Hour * 72), End: day.Add(time.Hour * 96)},
+		},
+		{
+			name: "Unknown symbol",
+			req:  entity.StockQuoteRequest{Symbol: "MSFT", Begin: day.Add(-time.Hour), End: day.Add(time.Hour * 72)},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.StockQuotesPerTimeSlice(context.Background(), tt.req)
+			assert.NoError(t, err)
+			var prices []float64
+			for _, q := range got {
+				prices = append(prices, q.Price)
+			}
+			assert.Equal(t, tt.expected, prices)
+		})
+	}
+
+	symbols, err := repo.Symbols(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"TSLA", "UBER"}, symbols)
+
+	// returned quotes are copies
+	history, _ := repo.StockQuotesPerSymbol(context.Background(), "UBER")
+	history[0].Price = 100
+	history, _ = repo.StockQuotesPerSymbol(context.Background(), "UBER")
+	assert.Equal(t, 10.0, history[0].Price)
+}
+
+func TestMemoryRepository_Adjusted(t *testing.T) {
+	day := time.Unix(1699228800, 0)
+	repo := NewMemory(
+		entity.StockQuote{Symbol: "UBER", Datepoint: day, Price: 100},
+		entity.StockQuote{Symbol: "UBER", Datepoint: day.Add(time.Hour * 24), Price: 50},
+	)
+	id, err := repo.AddCorporateAction(context.Background(), entity.CorporateAction{Symbol: "UBER", Type: entity.ActionSplit, ExDate: day.Add(time.Hour * 24), Ratio: 2})
+	assert.NoError(t, err)
+
+	history, err := repo.StockQuotesPerTimeSlice(context.Background(), entity.StockQuoteRequest{Symbol: "UBER", Begin: day.Add(-time.Hour),
+		End: day.Add(time.Hour * 48), Adjusted: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{50, 50}, []float64{history[0].Price, history[1].Price})
+
+	assert.NoError(t, repo.DeleteCorporateAction(context.Background(), id))
+	assert.True(t, errors.Is(repo.DeleteCorporateAction(context.Background(), id), entity.ErrNotFound))
+}
+
+func TestMemoryRepository_Load(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "quotes.csv")
+	jsonPath := filepath.Join(dir, "quotes.json")
+	assert.NoError(t, os.WriteFile(csvPath, []byte("symbol,date,close\nUBER,2023-11-06,48.14\nUBER,2023-11-07,49.92\n"), 0o644))
+	assert.NoError(t, os.WriteFile(jsonPath, []byte(`[
+		{"symbol": "UBER", "date": "2023-11-06", "close": 48.14},
+		{"symbol": "UBER", "datepoint": "2023-11-07T00:00:00Z", "price": 49.92, "open": 48.2, "high": 50.1, "low": 48, "volume": 2000}
+	]`), 0o644))
+
+	for _, path := range []string{csvPath, jsonPath} {
+		repo, err := NewMemoryFromFile(path)
+		assert.NoError(t, err)
+		history, err := repo.StockQuotesPerSymbol(context.Background(), "UBER")
+		assert.NoError(t, err)
+		assert.Len(t, history, 2)
+		assert.Equal(t, time.Date(2023, 11, 7, 0, 0, 0, 0, time.UTC), history[1].Datepoint)
+		assert.Equal(t, 49.92, history[1].Price)
+	}
+
+	assert.Error(t, NewMemory().LoadJSON(strings.NewReader(`[{"symbol": "UBER", "date": "2023-11-06"}]`)))
+	_, err := NewMemoryFromFile(filepath.Join(dir, "quotes.xml"))
+	assert.Error(t, err)
+}
+
+func TestMemoryRepository_Concurrent(t *testing.T) {
+	day := time.Unix(1699228800, 0)
+	repo := NewMemory()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				repo.Append(entity.StockQuote{Symbol: "UBER", Datepoint: day.Add(time.Minute * time.Duration(w*100+i)), Price: float64(i)})
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_, err := repo.StockQuotesPerTimeSlice(context.Background(), entity.StockQuoteRequest{Symbol: "UBER", Begin: day, End: day.Add(time.Hour * 24)})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	history, err := repo.StockQuotesPerSymbol(context.Background(), "UBER")
+	assert.NoError(t, err)
+	assert.Len(t, history, 400)
+	for i := 1; i < len(history); i++ {
+		assert.True(t, history[i-1].Datepoint.Before(history[i].Datepoint))
+	}
+}
+
+func TestMemoryRepository_SymbolCatalog(t *testing.T) {
+	day := time.Unix(1699228800, 0)
+	repo := NewMemory(
+		entity.StockQuote{Symbol: "UBER", Datepoint: day.Add(time.Hour * 24), Price: 11},
+		entity.StockQuote{Symbol: "UBER", Datepoint: day, Price: 10},
+	)
+	ctx := context.Background()
+
+	assert.NoError(t, repo.AddSymbol(ctx, entity.Symbol{Symbol: "BRK.B", Name: "Berkshire Hathaway Inc. Class B", Timezone: "America/New_York", Status: entity.SymbolListed}))
+	assert.True(t, errors.Is(repo.AddSymbol(ctx, defaultSymbol("UBER")), entity.ErrConflict))
+
+	first, last := day, day.Add(time.Hour*24)
+	symbols, err := repo.SymbolCatalog(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Symbol{
+		{Symbol: "BRK.B", Name: "Berkshire Hathaway Inc. Class B", Timezone: "America/New_York", Status: entity.SymbolListed},
+		{Symbol: "UBER", Timezone: "UTC", Status: entity.SymbolListed, FirstDatapoint: &first, LastDatapoint: &last},
+	}, symbols)
+
+	_, err = repo.DescribeSymbol(ctx, "UNKN")
+	assert.True(t, errors.Is(err, entity.ErrNotFound))
+}
@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationFiles holds the versioned schema migrations of every SQL dialect, named <version>_<name>.<up|down>.sql
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+var migrationName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema change with the statements to apply and to revert it
+type Migration struct {
+	Version int
+	Name    string
+	up      string
+	down    string
+}
+
+// MigrationStatus tells whether a migration is applied to the database and when
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// migrationDialect holds the statements managing the schema_migrations table of a SQL dialect
+type migrationDialect struct {
+	name          string
+	createTable   string
+	selectApplied string
+	insertApplied string
+	deleteApplied string
+}
+
+var (
+	mysqlMigrations = migrationDialect{
+		name: "mysql",
+		createTable: "CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, " +
+			"name varchar(255) NOT NULL, applied_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP)",
+		selectApplied: "SELECT version, applied_at FROM schema_migrations ORDER BY version ASC",
+		insertApplied: "INSERT INTO schema_migrations (version, name) VALUES (?, ?)",
+		deleteApplied: "DELETE FROM schema_migrations WHERE version = ?",
+	}
+	postgresMigrations = migrationDialect{
+		name: "postgres",
+		createTable: "CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, " +
+			"name varchar(255) NOT NULL, applied_at timestamptz NOT NULL DEFAULT now())",
+		selectApplied: "SELECT version, applied_at FROM schema_migrations ORDER BY version ASC",
+		insertApplied: "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+		deleteApplied: "DELETE FROM schema_migrations WHERE version = $1",
+	}
+	sqliteMigrations = migrationDialect{
+		name: "sqlite",
+		createTable: "CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, " +
+			"name VARCHAR(255) NOT NULL, applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)",
+		selectApplied: mysqlMigrations.selectApplied,
+		insertApplied: mysqlMigrations.insertApplied,
+		deleteApplied: mysqlMigrations.deleteApplied,
+	}
+)
+
+// Migrator applies and reverts the embedded migrations of a database, the applied versions are tracked in the
+// schema_migrations table. Every migration runs in its own transaction, on databases with transactional DDL
+// (PostgreSQL and SQLite) a failed migration leaves no trace.
+type Migrator struct {
+	db         *sql.DB
+	dialect    migrationDialect
+	migrations []Migration
+}
+
+func newMigrator(db *sql.DB, dialect migrationDialect) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles, path.Join("migrations", dialect.name))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// Migrator returns the migrator of the MySQL database
+func (r DBRepository) Migrator() (*Migrator, error) {
+	return newMigrator(r.db, mysqlMigrations)
+}
+
+// Migrator returns the migrator of the PostgreSQL database
+func (r PostgresRepository) Migrator() (*Migrator, error) {
+	return newMigrator(r.db, postgresMigrations)
+}
+
+// Migrator returns the migrator of the SQLite database
+func (r SQLiteRepository) Migrator() (*Migrator, error) {
+	return newMigrator(r.db, sqliteMigrations)
+}
+
+// Up applies all pending migrations in the order of their versions and returns the applied ones
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, status := range statuses {
+		if status.Applied {
+			continue
+		}
+		if err := m.apply(ctx, status.Migration, status.up, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, m.dialect.insertApplied, status.Version, status.Name)
+			return err
+		}); err != nil {
+			return applied, err
+		}
+		applied = append(applied, status.Migration)
+	}
+
+	return applied, nil
+}
+
+// Down reverts the given number of the latest applied migrations and returns the reverted ones
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	for i := len(statuses) - 1; i >= 0 && len(reverted) < steps; i-- {
+		status := statuses[i]
+		if !status.Applied {
+			continue
+		}
+		if err := m.apply(ctx, status.Migration, status.down, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, m.dialect.deleteApplied, status.Version)
+			return err
+		}); err != nil {
+			return reverted, err
+		}
+		reverted = append(reverted, status.Migration)
+	}
+
+	return reverted, nil
+}
+
+// Status returns all known migrations ordered by version and whether they're applied. Versions applied to the database
+// that are unknown to this binary are reported as an error as the schema is newer than the code.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if _, err := m.db.ExecContext(ctx, m.dialect.createTable); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	rows, err := m.db.QueryContext(ctx, m.dialect.selectApplied)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		appliedAt, ok := applied[migration.Version]
+		statuses[i] = MigrationStatus{Migration: migration, Applied: ok, AppliedAt: appliedAt}
+		delete(applied, migration.Version)
+	}
+	for version := range applied {
+		return nil, fmt.Errorf("migration %04d is applied to the database but unknown to this version of the service", version)
+	}
+
+	return statuses, nil
+}
+
+// apply executes the statements of the migration and records the change with track in the same transaction
+func (m *Migrator) apply(ctx context.Context, migration Migration, script string, track func(tx *sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+	}
+	if err := track(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// loadMigrations reads the migrations of the directory, every version must have both the up and the down script
+func loadMigrations(files fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		match := migrationName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration file %s doesn't match <version>_<name>.<up|down>.sql", entry.Name())
+		}
+
+		content, err := fs.ReadFile(files, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %04d has two names: %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.up = string(content)
+		} else {
+			migration.down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.up == "" || migration.down == "" {
+			return nil, fmt.Errorf("migration %04d_%s must have both up and down script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// splitStatements splits a script into statements ending with a semicolon at the end of a line, so drivers that
+// execute a single statement at once can run it. Semicolons within PostgreSQL $$ quoted blocks don't end a statement.
+func splitStatements(script string) []string {
+	var statements []string
+	var statement strings.Builder
+	quoted := false
+	for _, line := range strings.Split(script, "\n") {
+		statement.WriteString(line)
+		statement.WriteString("\n")
+		if strings.Count(line, "$$")%2 == 1 {
+			quoted = !quoted
+		}
+		if !quoted && strings.HasSuffix(strings.TrimSpace(line), ";") {
+			statements = append(statements, strings.TrimSpace(statement.String()))
+			statement.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(statement.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+
+	return statements
+}
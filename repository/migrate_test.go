@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"path"
+	"stockpricews/entity"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestMigrator(t *testing.T) {
+	repo, err := OpenSQLite(":memory:")
+	assert.NoError(t, err)
+	migrator, err := repo.Migrator()
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	statuses, err := migrator.Status(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 4)
+	for _, status := range statuses {
+		assert.False(t, status.Applied)
+	}
+
+	applied, err := migrator.Up(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, applied, 4)
+	applied, err = migrator.Up(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+
+	// the migrated schema takes the upserts
+	day := time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, repo.SaveStockQuotes(ctx, []entity.StockQuote{{Symbol: "UBER", Datepoint: day, Price: 48.14}}))
+	assert.NoError(t, repo.SaveStockQuotes(ctx, []entity.StockQuote{{Symbol: "UBER", Datepoint: day, Price: 48.5}}))
+
+	reverted, err := migrator.Down(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, reverted[0].Version)
+	assert.Equal(t, 3, reverted[1].Version)
+	statuses, err = migrator.Status(ctx)
+	assert.NoError(t, err)
+	assert.True(t, statuses[1].Applied)
+	assert.False(t, statuses[2].Applied)
+	// without the unique key the same quote can be inserted twice
+	_, err = repo.db.Exec(insertStockQuote, "UBER", 49, nil, nil, nil, 0, day.Format(sqliteTimeLayout))
+	assert.NoError(t, err)
+
+	// the duplicates are removed when the unique key is added back, the latest quote is kept
+	applied, err = migrator.Up(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, applied, 2)
+	history, err := repo.StockQuotesPerSymbol(ctx, "UBER")
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+	assert.Equal(t, 49.0, history[0].Price)
+	// the symbols of the stored quotes are registered when the catalog is created
+	symbol, err := repo.DescribeSymbol(ctx, "UBER")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.SymbolListed, symbol.Status)
+
+	reverted, err = migrator.Down(ctx, 10)
+	assert.NoError(t, err)
+	assert.Len(t, reverted, 4)
+	_, err = repo.Symbols(ctx)
+	assert.Error(t, err)
+
+	// a schema newer than the code is reported
+	_, err = repo.db.Exec("INSERT INTO schema_migrations (version, name) VALUES (99, 'future')")
+	assert.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	assert.EqualError(t, err, "migration 0099 is applied to the database but unknown to this version of the service")
+}
+
+func TestLoadMigrations(t *testing.T) {
+	// every dialect has the same migrations
+	var versions [][]int
+	for _, dialect := range []migrationDialect{mysqlMigrations, postgresMigrations, sqliteMigrations} {
+		migrations, err := loadMigrations(migrationFiles, path.Join("migrations", dialect.name))
+		assert.NoError(t, err)
+		dialectVersions := make([]int, len(migrations))
+		for i, migration := range migrations {
+			dialectVersions[i] = migration.Version
+		}
+		versions = append(versions, dialectVersions)
+	}
+	assert.Equal(t, versions[0], versions[1])
+	assert.Equal(t, versions[0], versions[2])
+
+	testCases := []struct {
+		name     string
+		files    fstest.MapFS
+		expected string
+	}{
+		{
+			name:     "Missing down script",
+			files:    fstest.MapFS{"m/0001_init.up.sql": {Data: []byte("CREATE TABLE t (id int);")}},
+			expected: "migration 0001_init must have both up and down script",
+		},
+		{
+			name:     "Unexpected file name",
+			files:    fstest.MapFS{"m/init.sql": {Data: []byte("CREATE TABLE t (id int);")}},
+			expected: "migration file init.sql doesn't match <version>_<name>.<up|down>.sql",
+		},
+		{
+			name: "Version with two names",
+			files: fstest.MapFS{
+				"m/0001_init.up.sql":    {Data: []byte("CREATE TABLE t (id int);")},
+				"m/0001_other.down.sql": {Data: []byte("DROP TABLE t;")},
+			},
+			expected: "migration 0001 has two names: init and other",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadMigrations(tt.files, "m")
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- comment
+CREATE TABLE t (id int);
+DO $$
+BEGIN
+  CREATE EXTENSION IF NOT EXISTS timescaledb;
+END
+$$;
+ALTER TABLE t
+  ADD COLUMN c int;`
+
+	assert.Equal(t, []string{
+		"-- comment\nCREATE TABLE t (id int);",
+		"DO $$\nBEGIN\n  CREATE EXTENSION IF NOT EXISTS timescaledb;\nEND\n$$;",
+		"ALTER TABLE t\n  ADD COLUMN c int;",
+	}, splitStatements(script))
+}
@@ -0,0 +1,13 @@
+-- The price column holds the close price, open, high, low and volume are optional
+CREATE TABLE IF NOT EXISTS `stock_quote` (
+   `id` int NOT NULL AUTO_INCREMENT,
+   `symbol` varchar(4) NOT NULL,
+   `price` double DEFAULT NULL,
+   `open` double DEFAULT NULL,
+   `high` double DEFAULT NULL,
+   `low` double DEFAULT NULL,
+   `volume` bigint DEFAULT NULL,
+   `datepoint` timestamp NULL DEFAULT NULL,
+   PRIMARY KEY (`id`),
+   KEY `symbol` (`symbol`,`datepoint`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS `corporate_action` (
+   `id` int NOT NULL AUTO_INCREMENT,
+   `symbol` varchar(4) NOT NULL,
+   `type` enum('split','reverse_split','dividend') NOT NULL,
+   `ex_date` timestamp NOT NULL,
+   `ratio` double NOT NULL DEFAULT 1,
+   `amount` double NOT NULL DEFAULT 0,
+   PRIMARY KEY (`id`),
+   KEY `symbol` (`symbol`,`ex_date`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
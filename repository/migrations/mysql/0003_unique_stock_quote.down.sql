@@ -0,0 +1,3 @@
+ALTER TABLE `stock_quote`
+  DROP INDEX `symbol`,
+  ADD KEY `symbol` (`symbol`,`datepoint`);
@@ -0,0 +1,6 @@
+-- Quotes are upserted by symbol and date point, only the latest inserted one of the duplicated quotes is kept
+DELETE older FROM `stock_quote` older
+  JOIN `stock_quote` newer ON older.symbol = newer.symbol AND older.datepoint = newer.datepoint AND older.id < newer.id;
+ALTER TABLE `stock_quote`
+  DROP INDEX `symbol`,
+  ADD UNIQUE KEY `symbol` (`symbol`,`datepoint`);
@@ -0,0 +1,4 @@
+-- fails rather than truncating if any symbol is longer than 4 chars
+ALTER TABLE `corporate_action` MODIFY `symbol` varchar(4) NOT NULL;
+ALTER TABLE `stock_quote` MODIFY `symbol` varchar(4) NOT NULL;
+DROP TABLE IF EXISTS `symbol`;
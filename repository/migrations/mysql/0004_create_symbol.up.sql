@@ -0,0 +1,15 @@
+-- The symbol catalog, every symbol with quotes is registered with the defaults when its first quote is stored
+CREATE TABLE IF NOT EXISTS `symbol` (
+   `symbol` varchar(16) NOT NULL,
+   `name` varchar(255) NOT NULL DEFAULT '',
+   `exchange` varchar(32) NOT NULL DEFAULT '',
+   `currency` varchar(3) NOT NULL DEFAULT '',
+   `timezone` varchar(64) NOT NULL DEFAULT 'UTC',
+   `status` enum('listed','delisted') NOT NULL DEFAULT 'listed',
+   PRIMARY KEY (`symbol`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+-- longer tickers and exchange suffixes, e.g. BRK.B or VOD.L
+ALTER TABLE `stock_quote` MODIFY `symbol` varchar(16) NOT NULL;
+ALTER TABLE `corporate_action` MODIFY `symbol` varchar(16) NOT NULL;
+INSERT INTO `symbol` (`symbol`) SELECT DISTINCT `symbol` FROM `stock_quote`
+  ON DUPLICATE KEY UPDATE `symbol` = VALUES(`symbol`);
@@ -0,0 +1,31 @@
+-- stock_quote is turned into a TimescaleDB hypertable partitioned by datepoint when the extension is available,
+-- otherwise it stays a plain table and the service works the same
+DO $$
+BEGIN
+  CREATE EXTENSION IF NOT EXISTS timescaledb;
+EXCEPTION WHEN OTHERS THEN
+  RAISE NOTICE 'timescaledb extension is not available, stock_quote stays a plain table';
+END
+$$;
+
+CREATE TABLE IF NOT EXISTS stock_quote (
+  id bigint GENERATED BY DEFAULT AS IDENTITY,
+  symbol varchar(4) NOT NULL,
+  price double precision,
+  open double precision,
+  high double precision,
+  low double precision,
+  volume bigint,
+  datepoint timestamptz NOT NULL,
+  -- unique constraints of a hypertable must include its partitioning column
+  PRIMARY KEY (id, datepoint)
+);
+CREATE INDEX IF NOT EXISTS stock_quote_symbol ON stock_quote (symbol, datepoint);
+
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb') THEN
+    PERFORM create_hypertable('stock_quote', 'datepoint', if_not_exists => TRUE, migrate_data => TRUE);
+  END IF;
+END
+$$;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS corporate_action;
+DROP TYPE IF EXISTS corporate_action_type;
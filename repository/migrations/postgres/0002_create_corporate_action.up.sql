@@ -0,0 +1,17 @@
+DO $$
+BEGIN
+  CREATE TYPE corporate_action_type AS ENUM ('split', 'reverse_split', 'dividend');
+EXCEPTION WHEN duplicate_object THEN
+  NULL;
+END
+$$;
+
+CREATE TABLE IF NOT EXISTS corporate_action (
+  id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  symbol varchar(4) NOT NULL,
+  type corporate_action_type NOT NULL,
+  ex_date timestamptz NOT NULL,
+  ratio double precision NOT NULL DEFAULT 1,
+  amount double precision NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS corporate_action_symbol ON corporate_action (symbol, ex_date);
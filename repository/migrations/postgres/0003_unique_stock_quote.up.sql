@@ -0,0 +1,6 @@
+-- Quotes are upserted by symbol and date point, only the latest inserted one of the duplicated quotes is kept
+DELETE FROM stock_quote older USING stock_quote newer
+  WHERE older.symbol = newer.symbol AND older.datepoint = newer.datepoint AND older.id < newer.id;
+DROP INDEX IF EXISTS stock_quote_symbol;
+-- the index includes the partitioning column as required by hypertables
+CREATE UNIQUE INDEX stock_quote_symbol ON stock_quote (symbol, datepoint);
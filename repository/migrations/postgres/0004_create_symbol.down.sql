@@ -0,0 +1,4 @@
+-- fails rather than truncating if any symbol is longer than 4 chars
+ALTER TABLE corporate_action ALTER COLUMN symbol TYPE varchar(4);
+ALTER TABLE stock_quote ALTER COLUMN symbol TYPE varchar(4);
+DROP TABLE IF EXISTS symbol;
@@ -0,0 +1,13 @@
+-- The symbol catalog, every symbol with quotes is registered with the defaults when its first quote is stored
+CREATE TABLE IF NOT EXISTS symbol (
+  symbol varchar(16) PRIMARY KEY,
+  name varchar(255) NOT NULL DEFAULT '',
+  exchange varchar(32) NOT NULL DEFAULT '',
+  currency varchar(3) NOT NULL DEFAULT '',
+  timezone varchar(64) NOT NULL DEFAULT 'UTC',
+  status text NOT NULL DEFAULT 'listed' CHECK (status IN ('listed', 'delisted'))
+);
+-- longer tickers and exchange suffixes, e.g. BRK.B or VOD.L
+ALTER TABLE stock_quote ALTER COLUMN symbol TYPE varchar(16);
+ALTER TABLE corporate_action ALTER COLUMN symbol TYPE varchar(16);
+INSERT INTO symbol (symbol) SELECT DISTINCT symbol FROM stock_quote ON CONFLICT (symbol) DO NOTHING;
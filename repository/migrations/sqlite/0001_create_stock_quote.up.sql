@@ -0,0 +1,12 @@
+-- Date points are stored as 'YYYY-MM-DD HH:MM:SS' text, the same layout as MySQL
+CREATE TABLE IF NOT EXISTS stock_quote (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  symbol VARCHAR(4) NOT NULL,
+  price DOUBLE,
+  open DOUBLE,
+  high DOUBLE,
+  low DOUBLE,
+  volume BIGINT,
+  datepoint TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS stock_quote_symbol ON stock_quote (symbol, datepoint);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS corporate_action (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  symbol VARCHAR(4) NOT NULL,
+  type TEXT NOT NULL CHECK (type IN ('split', 'reverse_split', 'dividend')),
+  ex_date TIMESTAMP NOT NULL,
+  ratio DOUBLE NOT NULL DEFAULT 1,
+  amount DOUBLE NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS corporate_action_symbol ON corporate_action (symbol, ex_date);
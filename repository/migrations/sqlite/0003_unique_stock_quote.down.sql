@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS stock_quote_symbol_datepoint;
+CREATE INDEX IF NOT EXISTS stock_quote_symbol ON stock_quote (symbol, datepoint);
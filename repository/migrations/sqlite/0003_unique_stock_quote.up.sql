@@ -0,0 +1,7 @@
+-- Quotes are upserted by symbol and date point, only the latest inserted one of the duplicated quotes is kept
+DELETE FROM stock_quote WHERE EXISTS (
+  SELECT 1 FROM stock_quote newer
+  WHERE newer.symbol = stock_quote.symbol AND newer.datepoint = stock_quote.datepoint AND newer.id > stock_quote.id
+);
+DROP INDEX IF EXISTS stock_quote_symbol;
+CREATE UNIQUE INDEX IF NOT EXISTS stock_quote_symbol_datepoint ON stock_quote (symbol, datepoint);
@@ -0,0 +1,11 @@
+-- The symbol catalog, every symbol with quotes is registered with the defaults when its first quote is stored.
+-- SQLite doesn't enforce the length of VARCHAR columns, so the quotes take longer symbols as they are.
+CREATE TABLE IF NOT EXISTS symbol (
+  symbol VARCHAR(16) PRIMARY KEY,
+  name VARCHAR(255) NOT NULL DEFAULT '',
+  exchange VARCHAR(32) NOT NULL DEFAULT '',
+  currency VARCHAR(3) NOT NULL DEFAULT '',
+  timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+  status TEXT NOT NULL DEFAULT 'listed' CHECK (status IN ('listed', 'delisted'))
+);
+INSERT INTO symbol (symbol) SELECT DISTINCT symbol FROM stock_quote WHERE true ON CONFLICT (symbol) DO NOTHING;
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	_ "github.com/lib/pq"
+	"stockpricews/entity"
+	"time"
+)
+
+// PostgresRepository is the Repository implementation backed by PostgreSQL. The stock_quote table is expected to be a
+// TimescaleDB hypertable partitioned by datepoint, see repository/migrations/postgres for the definition. It's a plain table
+// with the same queries on PostgreSQL without the TimescaleDB extension.
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+const (
+	pgGetStockQuotesPerTimeSlice = "SELECT " + stockQuoteColumns + " FROM stock_quote WHERE symbol = $1 AND datepoint > $2 AND datepoint < $3 ORDER BY datepoint ASC"
+	pgLimitStockQuotes           = " LIMIT $4"
+	pgGetStockQuotesPerSymbol    = "SELECT " + stockQuoteColumns + " FROM stock_quote WHERE symbol = $1 ORDER BY datepoint ASC"
+	pgGetSymbols                 = "SELECT DISTINCT symbol FROM stock_quote ORDER BY symbol ASC"
+	pgUpsertStockQuote           = "INSERT INTO stock_quote (symbol, price, open, high, low, volume, datepoint) VALUES ($1, $2, $3, $4, $5, $6, $7) " +
+		"ON CONFLICT (symbol, datepoint) DO UPDATE SET " +
+		"price = excluded.price, open = excluded.open, high = excluded.high, low = excluded.low, volume = excluded.volume"
+
+	pgGetCorporateActions      = "SELECT id, symbol, type, ex_date, ratio, amount FROM corporate_action WHERE symbol = $1 ORDER BY ex_date ASC"
+	pgGetCorporateActionsAfter = "SELECT id, symbol, type, ex_date, ratio, amount FROM corporate_action WHERE symbol = $1 AND ex_date > $2 ORDER BY ex_date ASC"
+	pgInsertCorporateAction    = "INSERT INTO corporate_action (symbol, type, ex_date, ratio, amount) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	pgDeleteCorporateAction    = "DELETE FROM corporate_action WHERE id = $1"
+	pgGetPriceBefore           = "SELECT price FROM stock_quote WHERE symbol = $1 AND datepoint < $2 ORDER BY datepoint DESC LIMIT 1"
+)
+
+// NewPostgres initializes a new DB repository that connects to PostgreSQL database
+func NewPostgres(user, pass string, port int) (PostgresRepository, error) {
+	connectionString := fmt.Sprintf("postgres://%s:%s@localhost:%d/stockquotedb?sslmode=disable", user, pass, port)
+
+	db, err := sql.Open("postgres", connectionString)
+	if err != nil {
+		return PostgresRepository{}, err
+	}
+
+	db.SetConnMaxLifetime(time.Minute * 3)
+	db.SetMaxOpenConns(10)
+	db.SetMaxIdleConns(10)
+
+	return PostgresRepository{db: db}, nil
+}
+
+func (r PostgresRepository) StockQuotesPerTimeSlice(ctx context.Context, req entity.StockQuoteRequest) ([]entity.StockQuote, error) {
+	// datepoint is timestamptz so the time points are passed as they are, no formatting in the local time zone needed
+	rows, err := r.db.QueryContext(ctx, pgGetStockQuotesPerTimeSlice, req.Symbol, req.Begin, req.End)
+	if err != nil {
+		return []entity.StockQuote{}, err
+	}
+
+	history, err := scanStockQuotes(rows)
+	if err != nil || !req.Adjusted {
+		return history, err
+	}
+
+	return adjustFromDB(ctx, r.db, pgGetCorporateActionsAfter, pgGetPriceBefore, func(datepoint time.Time) interface{} {
+		return datepoint
+	}, req, history)
+}
+
+// StockQuotesPerSymbol loads the whole stock quote history of the given symbol ordered by date
+func (r PostgresRepository) StockQuotesPerSymbol(ctx context.Context, symbol string) ([]entity.StockQuote, error) {
+	rows, err := r.db.QueryContext(ctx, pgGetStockQuotesPerSymbol, symbol)
+	if err != nil {
+		return []entity.StockQuote{}, err
+	}
+
+	return scanStockQuotes(rows)
+}
+
+// EachStockQuote streams the quotes of the time slice ordered by date to fn row by row
+func (r PostgresRepository) EachStockQuote(ctx context.Context, req entity.StockQuoteRequest, fn func(entity.StockQuote) error) error {
+	query, args := limitQuery(pgGetStockQuotesPerTimeSlice, pgLimitStockQuotes, req.Limit, req.Symbol, req.Begin, req.End)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return eachStockQuote(rows, fn)
+}
+
+// Symbols returns all symbols that have stock quotes stored in alphabetical order
+func (r PostgresRepository) Symbols(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, pgGetSymbols)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return scanSymbols(rows)
+}
+
+// SaveStockQuotes upserts the quotes by symbol and date point in a single transaction
+func (r PostgresRepository) SaveStockQuotes(ctx context.Context, quotes []entity.StockQuote) error {
+	return saveStockQuotes(ctx, r.db, pgUpsertStockQuote, pgRegisterSymbol, func(datepoint time.Time) interface{} {
+		return datepoint
+	}, quotes)
+}
+
+// CorporateActions returns all corporate actions of the symbol ordered by ex-date
+func (r PostgresRepository) CorporateActions(ctx context.Context, symbol string) ([]entity.CorporateAction, error) {
+	rows, err := r.db.QueryContext(ctx, pgGetCorporateActions, symbol)
+	if err != nil {
+		return []entity.CorporateAction{}, err
+	}
+
+	return scanCorporateActions(rows)
+}
+
+// AddCorporateAction stores the corporate action and returns its id
+func (r PostgresRepository) AddCorporateAction(ctx context.Context, action entity.CorporateAction) (int64, error) {
+	if err := validateCorporateAction(action); err != nil {
+		return 0, err
+	}
+
+	ratio := action.Ratio
+	if action.Type == entity.ActionDividend {
+		ratio = 1
+	}
+
+	// the driver doesn't support LastInsertId, the id is returned by the statement instead
+	var id int64
+	err := r.db.QueryRowContext(ctx, pgInsertCorporateAction, action.Symbol, string(action.Type), action.ExDate, ratio, action.Amount).Scan(&id)
+	return id, err
+}
+
+// DeleteCorporateAction removes the corporate action with the given id
+func (r PostgresRepository) DeleteCorporateAction(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, pgDeleteCorporateAction, id)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("corporate action %d: %w", id, entity.ErrNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"stockpricews/entity"
+	"testing"
+	"time"
+)
+
+// TestRepositoryQueryShapes runs the same requests against every Repository implementation and checks each of them
+// issues the queries of its SQL dialect
+func TestRepositoryQueryShapes(t *testing.T) {
+	from := time.Unix(1699356339, 0).UTC()
+	to := time.Unix(2699356339, 0).UTC()
+
+	testCases := []struct {
+		name           string
+		repository     func(db *sql.DB) Repository
+		timeSliceQuery string
+		timeSliceArgs  []driver.Value
+		perSymbolQuery string
+		symbolsQuery   string
+	}{
+		{
+			name:           "MySQL",
+			repository:     func(db *sql.DB) Repository { return DBRepository{db: db} },
+			timeSliceQuery: getStockQuotesPerTimeSlice,
+			timeSliceArgs:  []driver.Value{"UBER", from.Format("2006-01-02 15:04:05"), to.Format("2006-01-02 15:04:05")},
+			perSymbolQuery: getStockQuotesPerSymbol,
+			symbolsQuery:   getSymbols,
+		},
+		{
+			name:           "PostgreSQL",
+			repository:     func(db *sql.DB) Repository { return PostgresRepository{db: db} },
+			timeSliceQuery: pgGetStockQuotesPerTimeSlice,
+			timeSliceArgs:  []driver.Value{"UBER", from, to},
+			perSymbolQuery: pgGetStockQuotesPerSymbol,
+			symbolsQuery:   pgGetSymbols,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := NewMock()
+			repo := tt.repository(db)
+
+			mock.ExpectQuery(regexp.QuoteMeta(tt.timeSliceQuery)).WithArgs(tt.timeSliceArgs...).
+				WillReturnRows(sqlmock.NewRows(quoteColumns).
+					AddRow("1", "UBER", "19.99", "19.5", "20.1", "19.2", "1500000", time.Unix(1999356339, 0)))
+			history, err := repo.StockQuotesPerTimeSlice(context.Background(), entity.StockQuoteRequest{Symbol: "UBER", Begin: from, End: to})
+			assert.NoError(t, err)
+			assert.Equal(t, []entity.StockQuote{{ID: 1, Symbol: "UBER", Datepoint: time.Unix(1999356339, 0).UTC(), Price: 19.99,
+				Open: 19.5, High: 20.1, Low: 19.2, Volume: 1500000}}, history)
+
+			mock.ExpectQuery(regexp.QuoteMeta(tt.perSymbolQuery)).WithArgs("UBER").
+				WillReturnRows(sqlmock.NewRows(quoteColumns))
+			history, err = repo.StockQuotesPerSymbol(context.Background(), "UBER")
+			assert.NoError(t, err)
+			assert.Empty(t, history)
+
+			mock.ExpectQuery(regexp.QuoteMeta(tt.symbolsQuery)).
+				WillReturnRows(sqlmock.NewRows([]string{"symbol"}).AddRow("TSLA").AddRow("UBER"))
+			symbols, err := repo.Symbols(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"TSLA", "UBER"}, symbols)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostgresStockQuotesPerTimeSlice_Adjusted(t *testing.T) {
+	db, mock := NewMock()
+	repo := PostgresRepository{db: db}
+
+	from := time.Unix(1699228800, 0).UTC()
+	to := from.Add(time.Hour * 24 * 10)
+	mock.ExpectQuery(regexp.QuoteMeta(pgGetStockQuotesPerTimeSlice)).WithArgs("UBER", from, to).
+		WillReturnRows(sqlmock.NewRows(quoteColumns).
+			AddRow("1", "UBER", "100", "100", "100", "100", "0", from.Add(time.Hour*24)).
+			AddRow("2", "UBER", "50", "50", "50", "50", "0", from.Add(time.Hour*48)))
+	mock.ExpectQuery(regexp.QuoteMeta(pgGetCorporateActionsAfter)).WithArgs("UBER", from.Add(time.Hour*24)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "symbol", "type", "ex_date", "ratio", "amount"}).
+			AddRow("1", "UBER", "split", from.Add(time.Hour*48), 2, 0))
+
+	history, err := repo.StockQuotesPerTimeSlice(context.Background(), entity.StockQuoteRequest{Symbol: "UBER", Begin: from, End: to, Adjusted: true})
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.InDelta(t, 50, history[0].Price, 1e-9)
+	assert.InDelta(t, 50, history[1].Price, 1e-9)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresCorporateActions(t *testing.T) {
+	db, mock := NewMock()
+	repo := PostgresRepository{db: db}
+	exDate := time.Unix(1699228800, 0).UTC()
+
+	mock.ExpectQuery(regexp.QuoteMeta(pgInsertCorporateAction)).
+		WithArgs("UBER", "dividend", exDate, 1.0, 0.5).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	id, err := repo.AddCorporateAction(context.Background(), entity.CorporateAction{Symbol: "UBER", Type: entity.ActionDividend, ExDate: exDate, Amount: 0.5})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+
+	mock.ExpectQuery(regexp.QuoteMeta(pgGetCorporateActions)).WithArgs("UBER").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "symbol", "type", "ex_date", "ratio", "amount"}).
+			AddRow("7", "UBER", "dividend", exDate, 1, 0.5))
+	actions, err := repo.CorporateActions(context.Background(), "UBER")
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.CorporateAction{{ID: 7, Symbol: "UBER", Type: entity.ActionDividend, ExDate: exDate, Ratio: 1, Amount: 0.5}}, actions)
+
+	mock.ExpectExec(regexp.QuoteMeta(pgDeleteCorporateAction)).WithArgs(8).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.True(t, errors.Is(repo.DeleteCorporateAction(context.Background(), 8), entity.ErrNotFound))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
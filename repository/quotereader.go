@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"stockpricews/entity"
+	"strings"
+)
+
+// RecordError is a record of a quote file that can't be parsed, the reader can go on with the next record
+type RecordError struct {
+	Line int
+	Err  error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// QuoteReader streams the quotes of a CSV or JSON lines file one record at a time, so files of any size can be read
+// in constant memory
+type QuoteReader struct {
+	next func() (entity.StockQuote, error)
+	line int
+}
+
+// NewQuoteReader starts reading the quotes of the given format, the header row of CSV files is read right away
+func NewQuoteReader(r io.Reader, format entity.QuoteFormat) (*QuoteReader, error) {
+	switch format {
+	case entity.FormatCSV:
+		return newCSVQuoteReader(r)
+	case entity.FormatJSONLines:
+		return newJSONLinesQuoteReader(r), nil
+	default:
+		return nil, fmt.Errorf("unsupported quote format %s, csv or jsonl expected", format)
+	}
+}
+
+// Read returns the next quote of the file or io.EOF at its end. A record that can't be parsed is reported as
+// *RecordError and the reading can go on, any other error means the rest of the file can't be read.
+func (r *QuoteReader) Read() (entity.StockQuote, error) {
+	return r.next()
+}
+
+// Line returns the line of the file the last read record started at
+func (r *QuoteReader) Line() int {
+	return r.line
+}
+
+func newCSVQuoteReader(r io.Reader) (*QuoteReader, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	// rows with a missing column are rejected one by one rather than failing the whole file
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "date":
+			name = "datepoint"
+		case "close":
+			name = "price"
+		}
+		columns[name] = i
+	}
+	for _, name := range []string{"symbol", "datepoint", "price"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%s column is missing", name)
+		}
+	}
+
+	qr := &QuoteReader{line: 1}
+	qr.next = func() (entity.StockQuote, error) {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return entity.StockQuote{}, io.EOF
+		}
+		if err != nil {
+			// quoting errors leave the reader in an unknown state, so the rest of the file can't be trusted
+			return entity.StockQuote{}, err
+		}
+		qr.line, _ = reader.FieldPos(0)
+
+		quote, err := parseQuoteRecord(record, columns)
+		if err != nil {
+			return entity.StockQuote{}, &RecordError{Line: qr.line, Err: err}
+		}
+		return quote, nil
+	}
+
+	return qr, nil
+}
+
+func newJSONLinesQuoteReader(r io.Reader) *QuoteReader {
+	reader := bufio.NewReader(r)
+	qr := &QuoteReader{}
+	qr.next = func() (entity.StockQuote, error) {
+		for {
+			line, err := reader.ReadBytes('\n')
+			if err != nil && err != io.EOF {
+				return entity.StockQuote{}, err
+			}
+			if len(line) == 0 && err == io.EOF {
+				return entity.StockQuote{}, io.EOF
+			}
+			qr.line++
+
+			// blank lines, e.g. the one after the trailing newline, hold no record
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
+
+			quote, err := ParseJSONQuote(line)
+			if err != nil {
+				return entity.StockQuote{}, &RecordError{Line: qr.line, Err: err}
+			}
+			return quote, nil
+		}
+	}
+
+	return qr
+}
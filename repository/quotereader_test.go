@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"stockpricews/entity"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuoteReader(t *testing.T) {
+	day := time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		format entity.QuoteFormat
+		file   string
+		last   int
+	}{
+		{"CSV", entity.FormatCSV, "symbol,date,close,volume\nUBER,2023-11-06,48.14,100\nUBER,2023-11-07\nTSLA,1699228800,219.27,0\n", 4},
+		{"JSON lines", entity.FormatJSONLines, `
+{"symbol":"UBER","date":"2023-11-06","close":48.14,"volume":100}
+{"symbol":"UBER","date":"2023-11-07"}
+
+{"symbol":"TSLA","datepoint":1699228800,"price":219.27}`, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewQuoteReader(strings.NewReader(tt.file), tt.format)
+			assert.NoError(t, err)
+
+			quote, err := reader.Read()
+			assert.NoError(t, err)
+			assert.Equal(t, entity.StockQuote{Symbol: "UBER", Datepoint: day, Price: 48.14, Volume: 100}, quote)
+
+			// the record without a price is rejected and the reading goes on
+			_, err = reader.Read()
+			var recordErr *RecordError
+			assert.True(t, errors.As(err, &recordErr))
+			assert.Equal(t, 3, recordErr.Line)
+
+			quote, err = reader.Read()
+			assert.NoError(t, err)
+			assert.Equal(t, entity.StockQuote{Symbol: "TSLA", Datepoint: day, Price: 219.27}, quote)
+			assert.Equal(t, tt.last, reader.Line())
+
+			_, err = reader.Read()
+			assert.Equal(t, io.EOF, err)
+		})
+	}
+
+	_, err := NewQuoteReader(strings.NewReader("symbol,close\n"), entity.FormatCSV)
+	assert.Error(t, err)
+	_, err = NewQuoteReader(strings.NewReader(""), entity.QuoteFormat("xml"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	_ "github.com/glebarez/go-sqlite"
+	"os"
+	"path/filepath"
+	"stockpricews/entity"
+	"strings"
+	"time"
+)
+
+// SQLiteRepository is the Repository implementation backed by an embedded SQLite database file, so the service can run
+// without any external database. SQLite takes the same query placeholders and time format as MySQL, so the queries and
+// the corporate action management of DBRepository are reused as they are.
+type SQLiteRepository struct {
+	DBRepository
+}
+
+const (
+	countStockQuotes       = "SELECT COUNT(*) FROM stock_quote"
+	insertStockQuote       = "INSERT INTO stock_quote (symbol, price, open, high, low, volume, datepoint) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	normalizeDates         = "UPDATE stock_quote SET datepoint = datetime(datepoint)"
+	normalizeExDates       = "UPDATE corporate_action SET ex_date = datetime(ex_date)"
+	registerSeedSymbols    = "INSERT INTO symbol (symbol) SELECT DISTINCT symbol FROM stock_quote WHERE true ON CONFLICT (symbol) DO NOTHING"
+	sqliteTimeLayout       = "2006-01-02 15:04:05"
+	sqliteUpsertStockQuote = insertStockQuote + " ON CONFLICT (symbol, datepoint) DO UPDATE SET " +
+		"price = excluded.price, open = excluded.open, high = excluded.high, low = excluded.low, volume = excluded.volume"
+)
+
+// NewSQLite opens (or creates) the SQLite database at the given path and applies the pending migrations of
+// repository/migrations/sqlite. If the database holds no quotes yet it's seeded from the seed file, either a SQL dump
+// like data/dump.sql or a CSV file of quotes. An empty seed skips seeding.
+func NewSQLite(path, seed string) (SQLiteRepository, error) {
+	repo, err := OpenSQLite(path)
+	if err != nil {
+		return SQLiteRepository{}, err
+	}
+	db := repo.db
+
+	// the database is embedded in the service, so its schema is always kept up to date
+	if err := migrateSQLite(repo); err != nil {
+		db.Close()
+		return SQLiteRepository{}, fmt.Errorf("failed to migrate schema: %w", err)
+	}
+
+	var count int
+	if err := db.QueryRow(countStockQuotes).Scan(&count); err != nil {
+		db.Close()
+		return SQLiteRepository{}, err
+	}
+	if count == 0 && seed != "" {
+		if err := seedSQLite(db, seed); err != nil {
+			db.Close()
+			return SQLiteRepository{}, fmt.Errorf("failed to seed from %s: %w", seed, err)
+		}
+	}
+
+	return repo, nil
+}
+
+// OpenSQLite opens (or creates) the SQLite database at the given path as it is, without migrating or seeding it
+func OpenSQLite(path string) (SQLiteRepository, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return SQLiteRepository{}, err
+	}
+
+	// SQLite allows a single writer, besides every connection to :memory: would open a separate empty database
+	db.SetMaxOpenConns(1)
+
+	return SQLiteRepository{DBRepository{db: db}}, nil
+}
+
+func migrateSQLite(repo SQLiteRepository) error {
+	migrator, err := repo.Migrator()
+	if err != nil {
+		return err
+	}
+
+	_, err = migrator.Up(context.Background())
+	return err
+}
+
+// SaveStockQuotes upserts the quotes by symbol and date point in a single transaction, SQLite has its own upsert syntax
+func (r SQLiteRepository) SaveStockQuotes(ctx context.Context, quotes []entity.StockQuote) error {
+	return saveStockQuotes(ctx, r.db, sqliteUpsertStockQuote, sqliteRegisterSymbol, func(datepoint time.Time) interface{} {
+		return datepoint.UTC().Format(sqliteTimeLayout)
+	}, quotes)
+}
+
+// EachStockQuote loads the quotes of the time slice before passing them to fn. SQLite runs on a single connection, so
+// streaming the rows to a slow client would block every other query until the client is done.
+func (r SQLiteRepository) EachStockQuote(ctx context.Context, req entity.StockQuoteRequest, fn func(entity.StockQuote) error) error {
+	query, args := limitQuery(getStockQuotesPerTimeSlice, limitStockQuotes, req.Limit, req.Symbol,
+		req.Begin.UTC().Format(sqliteTimeLayout), req.End.UTC().Format(sqliteTimeLayout))
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	quotes, err := scanStockQuotes(rows)
+	if err != nil {
+		return err
+	}
+
+	return eachQuote(ctx, quotes, fn)
+}
+
+// seedSQLite loads the quotes of the seed file in a single transaction
+func seedSQLite(db *sql.DB, seed string) error {
+	file, err := os.Open(seed)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if strings.EqualFold(filepath.Ext(seed), ".csv") {
+		quotes, err := readQuotesCSV(file)
+		if err != nil {
+			return err
+		}
+		for _, q := range quotes {
+			if _, err := tx.Exec(insertStockQuote, q.Symbol, q.Price, nullablePrice(q.Open), nullablePrice(q.High),
+				nullablePrice(q.Low), q.Volume, q.Datepoint.UTC().Format(sqliteTimeLayout)); err != nil {
+				return err
+			}
+		}
+	} else if err := execDumpInserts(tx, file); err != nil {
+		return err
+	}
+
+	// dumps may hold bare dates, the queries compare the date points as text so they have to share the layout
+	if _, err := tx.Exec(normalizeDates); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(normalizeExDates); err != nil {
+		return err
+	}
+	// the seed is inserted as it is, so its symbols are registered in the catalog here
+	if _, err := tx.Exec(registerSeedSymbols); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// execDumpInserts executes the INSERT statements of a MySQL dump, the rest of it is MySQL specific and is skipped
+func execDumpInserts(tx *sql.Tx, file *os.File) error {
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(strings.ToUpper(line), "INSERT INTO") {
+			continue
+		}
+		if _, err := tx.Exec(line); err != nil {
+			return fmt.Errorf("failed to execute %q: %w", line, err)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"stockpricews/entity"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSQLite_SeedFromDump(t *testing.T) {
+	dir := t.TempDir()
+	seed := filepath.Join(dir, "dump.sql")
+	assert.NoError(t, os.WriteFile(seed, []byte(`DROP TABLE IF EXISTS `+"`stock_quote`"+`;
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-11-07',	49.92);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('UBER', '2023-11-06',	48.14);
+INSERT INTO stock_quote(symbol, datepoint, price) VALUES('TSLA', '2023-11-06',	219.27);
+`), 0o644))
+
+	path := filepath.Join(dir, "quotes.db")
+	repo, err := NewSQLite(path, seed)
+	assert.NoError(t, err)
+
+	symbols, err := repo.Symbols(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"TSLA", "UBER"}, symbols)
+
+	history, err := repo.StockQuotesPerSymbol(context.Background(), "UBER")
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, 48.14, history[0].Price)
+	assert.Equal(t, 48.14, history[0].High)
+	assert.Equal(t, time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC), history[0].Datepoint.UTC())
+	assert.NoError(t, repo.db.Close())
+
+	// the existing database isn't seeded again
+	repo, err = NewSQLite(path, seed)
+	assert.NoError(t, err)
+	history, err = repo.StockQuotesPerSymbol(context.Background(), "UBER")
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+}
+
+func TestNewSQLite_SeedFromCSV(t *testing.T) {
+	dir := t.TempDir()
+	seed := filepath.Join(dir, "quotes.csv")
+	assert.NoError(t, os.WriteFile(seed, []byte(`symbol,date,open,high,low,close,volume
+UBER,2023-11-06,47.5,48.5,47.1,48.14,1000
+UBER,2023-11-07,48.2,50.1,48,49.92,2000
+`), 0o644))
+
+	repo, err := NewSQLite(filepath.Join(dir, "quotes.db"), seed)
+	assert.NoError(t, err)
+
+	history, err := repo.StockQuotesPerSymbol(context.Background(), "UBER")
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	history[1].Datepoint = history[1].Datepoint.UTC()
+	assert.Equal(t, entity.StockQuote{ID: 2, Symbol: "UBER", Price: 49.92, Open: 48.2, High: 50.1, Low: 48, Volume: 2000,
+		Datepoint: time.Date(2023, 11, 7, 0, 0, 0, 0, time.UTC)}, history[1])
+
+	id, err := repo.AddCorporateAction(context.Background(), entity.CorporateAction{Symbol: "UBER", Type: entity.ActionSplit, ExDate: history[1].Datepoint, Ratio: 2})
+	assert.NoError(t, err)
+	actions, err := repo.CorporateActions(context.Background(), "UBER")
+	assert.NoError(t, err)
+	assert.Len(t, actions, 1)
+	assert.Equal(t, id, actions[0].ID)
+	assert.True(t, errors.Is(repo.DeleteCorporateAction(context.Background(), id+1), entity.ErrNotFound))
+}
+
+func TestReadQuotesCSV(t *testing.T) {
+	testCases := []struct {
+		name        string
+		csv         string
+		expected    []entity.StockQuote
+		expectedErr string
+	}{
+		{
+			name: "Close price only with unix seconds",
+			csv:  "datepoint,symbol,price\n1699228800,UBER,48.14\n",
+			expected: []entity.StockQuote{
+				{Symbol: "UBER", Price: 48.14, Datepoint: time.Unix(1699228800, 0).UTC()},
+			},
+		},
+		{
+			name:        "Missing price column",
+			csv:         "symbol,date\nUBER,2023-11-06\n",
+			expectedErr: "price column is missing",
+		},
+		{
+			name:        "Malformed price",
+			csv:         "symbol,date,close\nUBER,2023-11-06,n/a\n",
+			expectedErr: "line 2",
+		},
+		{
+			name:        "Malformed date",
+			csv:         "symbol,date,close\nUBER,06/11/2023,48.14\n",
+			expectedErr: "unknown format",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readQuotesCSV(strings.NewReader(tt.csv))
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestSQLiteSaveStockQuotes(t *testing.T) {
+	repo, err := NewSQLite(":memory:", "")
+	assert.NoError(t, err)
+	day := time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, repo.SaveStockQuotes(context.Background(), []entity.StockQuote{
+		{Symbol: "UBER", Datepoint: day, Price: 48.14},
+		{Symbol: "UBER", Datepoint: day.Add(time.Hour * 24), Price: 49.92, High: 50.1},
+	}))
+	// the quote of the same symbol and date point is replaced
+	assert.NoError(t, repo.SaveStockQuotes(context.Background(), []entity.StockQuote{
+		{Symbol: "UBER", Datepoint: day, Price: 48.5, Volume: 1000},
+	}))
+
+	history, err := repo.StockQuotesPerSymbol(context.Background(), "UBER")
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, 48.5, history[0].Price)
+	assert.Equal(t, 48.5, history[0].High)
+	assert.Equal(t, int64(1000), history[0].Volume)
+	assert.Equal(t, 50.1, history[1].High)
+
+	// a failing quote rolls back the whole batch
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, repo.SaveStockQuotes(ctx, []entity.StockQuote{{Symbol: "TSLA", Datepoint: day, Price: 219.27}}))
+	symbols, err := repo.Symbols(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"UBER"}, symbols)
+}
+
+func TestSQLiteEachStockQuote(t *testing.T) {
+	repo, err := NewSQLite(":memory:", "")
+	assert.NoError(t, err)
+	day := time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, repo.SaveStockQuotes(context.Background(), []entity.StockQuote{
+		{Symbol: "UBER", Datepoint: day.AddDate(0, 0, 2), Price: 50},
+		{Symbol: "UBER", Datepoint: day, Price: 48},
+		{Symbol: "UBER", Datepoint: day.AddDate(0, 0, 1), Price: 49},
+		{Symbol: "TSLA", Datepoint: day, Price: 219},
+	}))
+
+	req := entity.StockQuoteRequest{Symbol: "UBER", Begin: day.Add(-time.Hour), End: day.AddDate(0, 0, 3)}
+	var prices []float64
+	assert.NoError(t, repo.EachStockQuote(context.Background(), req, func(quote entity.StockQuote) error {
+		prices = append(prices, quote.Price)
+		return nil
+	}))
+	assert.Equal(t, []float64{48, 49, 50}, prices)
+
+	// the limit is applied by the query
+	prices = nil
+	req.Limit = 2
+	assert.NoError(t, repo.EachStockQuote(context.Background(), req, func(quote entity.StockQuote) error {
+		prices = append(prices, quote.Price)
+		return nil
+	}))
+	assert.Equal(t, []float64{48, 49}, prices)
+	req.Limit = 0
+
+	// the quotes are loaded first, so the single connection is free while fn takes them
+	assert.NoError(t, repo.EachStockQuote(context.Background(), req, func(quote entity.StockQuote) error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err := repo.Symbols(ctx)
+		return err
+	}))
+
+	// the error of the callback stops the streaming
+	stop := errors.New("client went away")
+	assert.Equal(t, stop, repo.EachStockQuote(context.Background(), req, func(entity.StockQuote) error { return stop }))
+	symbols, err := repo.Symbols(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"TSLA", "UBER"}, symbols)
+}
+
+func TestSQLiteSymbolCatalog(t *testing.T) {
+	repo, err := NewSQLite(":memory:", "")
+	assert.NoError(t, err)
+	ctx := context.Background()
+	day := time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC)
+
+	// the symbols of the stored quotes are registered with the defaults
+	assert.NoError(t, repo.SaveStockQuotes(ctx, []entity.StockQuote{
+		{Symbol: "BRK.B", Datepoint: day.AddDate(0, 0, 1), Price: 360},
+		{Symbol: "BRK.B", Datepoint: day, Price: 358},
+	}))
+	assert.NoError(t, repo.AddSymbol(ctx, entity.Symbol{Symbol: "VOD.L", Name: "Vodafone Group", Exchange: "LSE",
+		Currency: "GBP", Timezone: "Europe/London", Status: entity.SymbolListed}))
+	assert.True(t, errors.Is(repo.AddSymbol(ctx, defaultSymbol("BRK.B")), entity.ErrConflict))
+
+	first, last := day, day.AddDate(0, 0, 1)
+	symbols, err := repo.SymbolCatalog(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Symbol{
+		{Symbol: "BRK.B", Timezone: "UTC", Status: entity.SymbolListed, FirstDatapoint: &first, LastDatapoint: &last},
+		{Symbol: "VOD.L", Name: "Vodafone Group", Exchange: "LSE", Currency: "GBP", Timezone: "Europe/London", Status: entity.SymbolListed},
+	}, symbols)
+
+	symbol, err := repo.DescribeSymbol(ctx, "VOD.L")
+	assert.NoError(t, err)
+	assert.Equal(t, symbols[1], symbol)
+
+	_, err = repo.DescribeSymbol(ctx, "UNKN")
+	assert.True(t, errors.Is(err, entity.ErrNotFound))
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	_ "github.com/go-sql-driver/mysql"
@@ -8,21 +9,24 @@ import (
 	"time"
 )
 
-// Repository layer used to query stock_quote historical data. The table has the following definition:
+// Repository layer used to query stock_quote historical data. The schema of the tables is defined by the versioned
+// migrations of repository/migrations/mysql, applied with the migrate subcommand.
 //
-// CREATE TABLE `stock_quote` (
-//    `id` int NOT NULL AUTO_INCREMENT,
-//    `symbol` varchar(4) NOT NULL,
-//    `price` double DEFAULT NULL,
-//    `datepoint` timestamp NULL DEFAULT NULL,
-//  PRIMARY KEY (`id`),
-//  KEY `symbol` (`symbol`,`datepoint`)
-//) ENGINE=InnoDB AUTO_INCREMENT=529 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci |
+// The price column holds the close price. Quotes stored before the OHLCV columns were added only have the close,
+// which then stands for the open, high and low as well.
 type DBRepository struct {
 	db *sql.DB
 }
 
-const getStockQuotesPerTimeSlice = "SELECT * FROM stock_quote WHERE symbol = ? AND datepoint > ? AND datepoint < ? ORDER BY datepoint ASC"
+const (
+	stockQuoteColumns          = "id, symbol, price, COALESCE(open, price), COALESCE(high, price), COALESCE(low, price), COALESCE(volume, 0), datepoint"
+	getStockQuotesPerTimeSlice = "SELECT " + stockQuoteColumns + " FROM stock_quote WHERE symbol = ? AND datepoint > ? AND datepoint < ? ORDER BY datepoint ASC"
+	getStockQuotesPerSymbol    = "SELECT " + stockQuoteColumns + " FROM stock_quote WHERE symbol = ? ORDER BY datepoint ASC"
+	limitStockQuotes           = " LIMIT ?"
+	getSymbols                 = "SELECT DISTINCT symbol FROM stock_quote ORDER BY symbol ASC"
+	upsertStockQuote           = "INSERT INTO stock_quote (symbol, price, open, high, low, volume, datepoint) VALUES (?, ?, ?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE price = VALUES(price), open = VALUES(open), high = VALUES(high), low = VALUES(low), volume = VALUES(volume)"
+)
 
 // New initializes a new DB repository that connects to MySQL database
 func New(user, pass string, port int) (DBRepository, error) {
@@ -42,24 +46,179 @@ func New(user, pass string, port int) (DBRepository, error) {
 	return DBRepository{db: db}, nil
 }
 
-func (r DBRepository) StockQuotesPerTimeSlice(req entity.StockQuoteRequest) ([]entity.StockQuote, error) {
+func (r DBRepository) StockQuotesPerTimeSlice(ctx context.Context, req entity.StockQuoteRequest) ([]entity.StockQuote, error) {
 	// db.Query uses prepared statement under the hook for a performance optimization and SQL injection protection
-	rows, err := r.db.Query(getStockQuotesPerTimeSlice, req.Symbol,
-		req.Begin.Format("2006-01-02 15:04:05"), req.End.Format("2006-01-02 15:04:05"))
+	rows, err := r.db.QueryContext(ctx, getStockQuotesPerTimeSlice, req.Symbol,
+		req.Begin.UTC().Format("2006-01-02 15:04:05"), req.End.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return []entity.StockQuote{}, err
+	}
+
+	history, err := scanStockQuotes(rows)
+	if err != nil || !req.Adjusted {
+		return history, err
+	}
+
+	return r.adjust(ctx, req, history)
+}
+
+// StockQuotesPerSymbol loads the whole stock quote history of the given symbol ordered by date
+func (r DBRepository) StockQuotesPerSymbol(ctx context.Context, symbol string) ([]entity.StockQuote, error) {
+	rows, err := r.db.QueryContext(ctx, getStockQuotesPerSymbol, symbol)
 	if err != nil {
 		return []entity.StockQuote{}, err
 	}
-	// essentially not needed as the sql.DB will close it internally as soon as rows iteration is over
+
+	return scanStockQuotes(rows)
+}
+
+// EachStockQuote streams the quotes of the time slice ordered by date to fn row by row
+func (r DBRepository) EachStockQuote(ctx context.Context, req entity.StockQuoteRequest, fn func(entity.StockQuote) error) error {
+	query, args := limitQuery(getStockQuotesPerTimeSlice, limitStockQuotes, req.Limit, req.Symbol,
+		req.Begin.UTC().Format("2006-01-02 15:04:05"), req.End.UTC().Format("2006-01-02 15:04:05"))
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return eachStockQuote(rows, fn)
+}
+
+// Symbols returns all symbols that have stock quotes stored in alphabetical order
+func (r DBRepository) Symbols(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, getSymbols)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return scanSymbols(rows)
+}
+
+// SaveStockQuotes upserts the quotes by symbol and date point in a single transaction
+func (r DBRepository) SaveStockQuotes(ctx context.Context, quotes []entity.StockQuote) error {
+	return saveStockQuotes(ctx, r.db, upsertStockQuote, registerSymbol, func(datepoint time.Time) interface{} {
+		return datepoint.UTC().Format("2006-01-02 15:04:05")
+	}, quotes)
+}
+
+// saveStockQuotes executes the upsert statement of a SQL dialect for every quote within a single transaction and
+// registers the unknown symbols of the quotes in the catalog by the register statement. The date points are converted
+// to the statement argument by timeArg.
+func saveStockQuotes(ctx context.Context, db *sql.DB, upsert, register string, timeArg func(time.Time) interface{}, quotes []entity.StockQuote) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	registered := map[string]bool{}
+	for _, q := range quotes {
+		if registered[q.Symbol] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, register, q.Symbol); err != nil {
+			return fmt.Errorf("failed to register symbol %s: %w", q.Symbol, err)
+		}
+		registered[q.Symbol] = true
+	}
+
+	stmt, err := tx.PrepareContext(ctx, upsert)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, q := range quotes {
+		if _, err := stmt.ExecContext(ctx, q.Symbol, q.Price, nullablePrice(q.Open), nullablePrice(q.High),
+			nullablePrice(q.Low), q.Volume, timeArg(q.Datepoint)); err != nil {
+			return fmt.Errorf("failed to store %s quote at %s: %w", q.Symbol, q.Datepoint.Format(time.RFC3339), err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// nullablePrice stores the missing open, high and low prices as NULL so the close is used instead
+func nullablePrice(price float64) interface{} {
+	if price == 0 {
+		return nil
+	}
+	return price
+}
+
+func scanSymbols(rows *sql.Rows) ([]string, error) {
 	defer rows.Close()
 
+	var symbols []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return symbols, err
+		}
+		symbols = append(symbols, s)
+	}
+
+	return symbols, rows.Err()
+}
+
+func scanStockQuotes(rows *sql.Rows) ([]entity.StockQuote, error) {
 	var history []entity.StockQuote
+	err := eachStockQuote(rows, func(quote entity.StockQuote) error {
+		history = append(history, quote)
+		return nil
+	})
+
+	return history, err
+}
+
+// limitQuery appends the limit clause and its argument to the query if the limit is set
+func limitQuery(query, limitClause string, limit int, args ...interface{}) (string, []interface{}) {
+	if limit <= 0 {
+		return query, args
+	}
+	return query + limitClause, append(args, limit)
+}
+
+// eachQuote passes the loaded quotes to fn until it fails or the context is done
+func eachQuote(ctx context.Context, quotes []entity.StockQuote, fn func(entity.StockQuote) error) error {
+	for _, quote := range quotes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(quote); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CompletePrices fills the missing open, high and low prices of the quote with the close, the same way the stored
+// quotes are read from the database
+func CompletePrices(quote entity.StockQuote) entity.StockQuote {
+	for _, price := range []*float64{&quote.Open, &quote.High, &quote.Low} {
+		if *price == 0 {
+			*price = quote.Price
+		}
+	}
+	return quote
+}
+
+// eachStockQuote scans the rows one by one and passes every quote to fn, an error of fn stops the scan
+func eachStockQuote(rows *sql.Rows, fn func(entity.StockQuote) error) error {
+	// closing the rows releases the connection if fn stops the scan early, otherwise sql.DB closes them at the end
+	defer rows.Close()
+
 	for rows.Next() {
 		quote := entity.StockQuote{}
-		if err = rows.Scan(&quote.ID, &quote.Symbol, &quote.Price, &quote.Datepoint); err != nil {
-			return history, err
+		if err := rows.Scan(&quote.ID, &quote.Symbol, &quote.Price, &quote.Open, &quote.High, &quote.Low, &quote.Volume, &quote.Datepoint); err != nil {
+			return err
+		}
+		// the drivers return the session or server time zone, the responses are in UTC whatever the database
+		quote.Datepoint = quote.Datepoint.UTC()
+		if err := fn(quote); err != nil {
+			return err
 		}
-		history = append(history, quote)
 	}
 
-	return history, nil
+	return rows.Err()
 }
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"regexp"
@@ -12,6 +14,9 @@ import (
 	"testing"
 )
 
+// quoteColumns are the columns selected by the stock quote queries
+var quoteColumns = []string{"id", "symbol", "price", "open", "high", "low", "volume", "datapoint"}
+
 func NewMock() (*sql.DB, sqlmock.Sqlmock) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -25,17 +30,168 @@ func TestStockQuotesPerTimeSlice(t *testing.T) {
 	db, mock := NewMock()
 	repo := &DBRepository{db: db}
 
-	from := time.Unix(1699356339, 0)
-	to := time.Unix(2699356339, 0)
-	rows := sqlmock.NewRows([]string{"id", "symbol", "price", "datapoint"}).
-		AddRow("1", "UBER", "19.99", time.Unix(1999356339, 0))
+	from := time.Unix(1699356339, 0).UTC()
+	to := time.Unix(2699356339, 0).UTC()
+	rows := sqlmock.NewRows(quoteColumns).
+		AddRow("1", "UBER", "19.99", "19.5", "20.1", "19.2", "1500000", time.Unix(1999356339, 0))
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM stock_quote WHERE symbol = ? AND datepoint > ? AND datepoint < ? ORDER BY datepoint ASC")).
+	mock.ExpectQuery(regexp.QuoteMeta(getStockQuotesPerTimeSlice)).
 		WithArgs("UBER", from.Format("2006-01-02 15:04:05"), to.Format("2006-01-02 15:04:05")).WillReturnRows(rows)
 
-	history, err := repo.StockQuotesPerTimeSlice(entity.StockQuoteRequest{Symbol: "UBER", Begin: from, End: to})
+	history, err := repo.StockQuotesPerTimeSlice(context.Background(), entity.StockQuoteRequest{Symbol: "UBER", Begin: from, End: to})
 	assert.NotNil(t, history)
 	assert.NoError(t, err)
 	assert.True(t, len(history) == 1)
-	assert.Equal(t, entity.StockQuote{ID: 1, Symbol: "UBER", Datepoint: time.Date(2033, time.May, 10, 19, 45, 39, 0, time.Local), Price: 19.99}, history[0])
+	assert.Equal(t, entity.StockQuote{ID: 1, Symbol: "UBER", Datepoint: time.Unix(1999356339, 0).UTC(), Price: 19.99,
+		Open: 19.5, High: 20.1, Low: 19.2, Volume: 1500000}, history[0])
+}
+
+func TestEachStockQuote_Limit(t *testing.T) {
+	db, mock := NewMock()
+	repo := &DBRepository{db: db}
+
+	from := time.Unix(1699356339, 0).UTC()
+	to := time.Unix(2699356339, 0).UTC()
+	rows := sqlmock.NewRows(quoteColumns).
+		AddRow("1", "UBER", "19.99", "19.5", "20.1", "19.2", "1500000", time.Unix(1999356339, 0))
+
+	mock.ExpectQuery(regexp.QuoteMeta(getStockQuotesPerTimeSlice+limitStockQuotes)).
+		WithArgs("UBER", from.Format("2006-01-02 15:04:05"), to.Format("2006-01-02 15:04:05"), 1).WillReturnRows(rows)
+
+	var prices []float64
+	err := repo.EachStockQuote(context.Background(), entity.StockQuoteRequest{Symbol: "UBER", Begin: from, End: to, Limit: 1},
+		func(quote entity.StockQuote) error {
+			prices = append(prices, quote.Price)
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{19.99}, prices)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStockQuotesPerSymbol(t *testing.T) {
+	db, mock := NewMock()
+	repo := &DBRepository{db: db}
+
+	rows := sqlmock.NewRows(quoteColumns).
+		AddRow("1", "UBER", "19.99", "19.99", "19.99", "19.99", "0", time.Unix(1999356339, 0)).
+		AddRow("2", "UBER", "21.5", "21.5", "21.5", "21.5", "0", time.Unix(1999442739, 0))
+
+	mock.ExpectQuery(regexp.QuoteMeta(getStockQuotesPerSymbol)).
+		WithArgs("UBER").WillReturnRows(rows)
+
+	history, err := repo.StockQuotesPerSymbol(context.Background(), "UBER")
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.StockQuote{
+		{ID: 1, Symbol: "UBER", Datepoint: time.Unix(1999356339, 0).UTC(), Price: 19.99, Open: 19.99, High: 19.99, Low: 19.99},
+		{ID: 2, Symbol: "UBER", Datepoint: time.Unix(1999442739, 0).UTC(), Price: 21.5, Open: 21.5, High: 21.5, Low: 21.5},
+	}, history)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSymbols(t *testing.T) {
+	db, mock := NewMock()
+	repo := &DBRepository{db: db}
+
+	rows := sqlmock.NewRows([]string{"symbol"}).AddRow("TSLA").AddRow("UBER")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT symbol FROM stock_quote ORDER BY symbol ASC")).WillReturnRows(rows)
+
+	symbols, err := repo.Symbols(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"TSLA", "UBER"}, symbols)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStockQuotesPerTimeSlice_Adjusted(t *testing.T) {
+	db, mock := NewMock()
+	repo := &DBRepository{db: db}
+
+	from := time.Unix(1699228800, 0).UTC()
+	to := from.Add(time.Hour * 24 * 10)
+	rows := sqlmock.NewRows(quoteColumns).
+		AddRow("1", "UBER", "100", "98", "104", "96", "1000", from.Add(time.Hour*24)).
+		AddRow("2", "UBER", "50", "50", "50", "50", "2000", from.Add(time.Hour*48))
+	mock.ExpectQuery(regexp.QuoteMeta(getStockQuotesPerTimeSlice)).
+		WithArgs("UBER", from.Format("2006-01-02 15:04:05"), to.Format("2006-01-02 15:04:05")).WillReturnRows(rows)
+
+	actions := sqlmock.NewRows([]string{"id", "symbol", "type", "ex_date", "ratio", "amount"}).
+		AddRow("1", "UBER", "split", from.Add(time.Hour*48), 2, 0).
+		AddRow("2", "UBER", "dividend", to.Add(time.Hour*24), 1, 5)
+	mock.ExpectQuery(regexp.QuoteMeta(getCorporateActionsAfter)).
+		WithArgs("UBER", from.Add(time.Hour*24).Format("2006-01-02 15:04:05")).WillReturnRows(actions)
+	mock.ExpectQuery(regexp.QuoteMeta(getPriceBefore)).
+		WithArgs("UBER", to.Add(time.Hour*24).Format("2006-01-02 15:04:05")).
+		WillReturnRows(sqlmock.NewRows([]string{"price"}).AddRow(100))
+
+	history, err := repo.StockQuotesPerTimeSlice(context.Background(), entity.StockQuoteRequest{Symbol: "UBER", Begin: from, End: to, Adjusted: true})
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.InDelta(t, 47.5, history[0].Price, 1e-9)
+	assert.InDelta(t, 46.55, history[0].Open, 1e-9)
+	assert.InDelta(t, 49.4, history[0].High, 1e-9)
+	assert.InDelta(t, 45.6, history[0].Low, 1e-9)
+	assert.InDelta(t, 47.5, history[1].Price, 1e-9)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCorporateActions(t *testing.T) {
+	db, mock := NewMock()
+	repo := &DBRepository{db: db}
+	exDate := time.Unix(1699228800, 0).UTC()
+
+	mock.ExpectExec(regexp.QuoteMeta(insertCorporateAction)).
+		WithArgs("UBER", "split", exDate.Format("2006-01-02 15:04:05"), 2.0, 0.0).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	id, err := repo.AddCorporateAction(context.Background(), entity.CorporateAction{Symbol: "UBER", Type: entity.ActionSplit, ExDate: exDate, Ratio: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+
+	_, err = repo.AddCorporateAction(context.Background(), entity.CorporateAction{Symbol: "UBER", Type: entity.ActionSplit, ExDate: exDate})
+	assert.True(t, errors.Is(err, entity.ErrBadRequest))
+
+	mock.ExpectQuery(regexp.QuoteMeta(getCorporateActions)).WithArgs("UBER").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "symbol", "type", "ex_date", "ratio", "amount"}).
+			AddRow("7", "UBER", "split", exDate, 2, 0))
+	actions, err := repo.CorporateActions(context.Background(), "UBER")
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.CorporateAction{{ID: 7, Symbol: "UBER", Type: entity.ActionSplit, ExDate: exDate, Ratio: 2}}, actions)
+
+	mock.ExpectExec(regexp.QuoteMeta(deleteCorporateAction)).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.DeleteCorporateAction(context.Background(), 7))
+
+	mock.ExpectExec(regexp.QuoteMeta(deleteCorporateAction)).WithArgs(8).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.True(t, errors.Is(repo.DeleteCorporateAction(context.Background(), 8), entity.ErrNotFound))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveStockQuotes(t *testing.T) {
+	db, mock := NewMock()
+	repo := DBRepository{db: db}
+	day := time.Unix(1699228800, 0).UTC()
+	quotes := []entity.StockQuote{
+		{Symbol: "UBER", Datepoint: day, Price: 48.14},
+		{Symbol: "UBER", Datepoint: day.Add(time.Hour * 24), Price: 49.92, Open: 48.2, High: 50.1, Low: 48, Volume: 2000},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(registerSymbol)).WithArgs("UBER").WillReturnResult(sqlmock.NewResult(0, 0))
+	upsert := mock.ExpectPrepare(regexp.QuoteMeta(upsertStockQuote))
+	upsert.ExpectExec().WithArgs("UBER", 48.14, nil, nil, nil, 0, day.Format("2006-01-02 15:04:05")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	upsert.ExpectExec().WithArgs("UBER", 49.92, 48.2, 50.1, 48.0, 2000, day.Add(time.Hour*24).Format("2006-01-02 15:04:05")).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, repo.SaveStockQuotes(context.Background(), quotes))
+
+	dbErr := errors.New("deadlock found")
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(registerSymbol)).WithArgs("UBER").WillReturnResult(sqlmock.NewResult(0, 1))
+	upsert = mock.ExpectPrepare(regexp.QuoteMeta(upsertStockQuote))
+	upsert.ExpectExec().WillReturnResult(sqlmock.NewResult(1, 1))
+	upsert.ExpectExec().WillReturnError(dbErr)
+	mock.ExpectRollback()
+	assert.True(t, errors.Is(repo.SaveStockQuotes(context.Background(), quotes), dbErr))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"stockpricews/entity"
+	"time"
+)
+
+// The symbol catalog is stored in the symbol table, see the 0004_create_symbol migrations. The first and last date
+// points are looked up from stock_quote by its (symbol, datepoint) index, so they're always up to date.
+const (
+	symbolColumns = "s.symbol, s.name, s.exchange, s.currency, s.timezone, s.status, " +
+		"(SELECT MIN(q.datepoint) FROM stock_quote q WHERE q.symbol = s.symbol), " +
+		"(SELECT MAX(q.datepoint) FROM stock_quote q WHERE q.symbol = s.symbol)"
+	getSymbolCatalog = "SELECT " + symbolColumns + " FROM symbol s ORDER BY s.symbol ASC"
+	getSymbol        = "SELECT " + symbolColumns + " FROM symbol s WHERE s.symbol = ?"
+	// the no-op update makes the duplicate affect no rows, so it's told apart from a new symbol
+	insertSymbol = "INSERT INTO symbol (symbol, name, exchange, currency, timezone, status) VALUES (?, ?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE symbol = symbol"
+	registerSymbol = "INSERT INTO symbol (symbol) VALUES (?) ON DUPLICATE KEY UPDATE symbol = symbol"
+
+	pgGetSymbol      = "SELECT " + symbolColumns + " FROM symbol s WHERE s.symbol = $1"
+	pgInsertSymbol   = "INSERT INTO symbol (symbol, name, exchange, currency, timezone, status) VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (symbol) DO NOTHING"
+	pgRegisterSymbol = "INSERT INTO symbol (symbol) VALUES ($1) ON CONFLICT (symbol) DO NOTHING"
+
+	sqliteInsertSymbol   = "INSERT INTO symbol (symbol, name, exchange, currency, timezone, status) VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT (symbol) DO NOTHING"
+	sqliteRegisterSymbol = "INSERT INTO symbol (symbol) VALUES (?) ON CONFLICT (symbol) DO NOTHING"
+)
+
+// defaultSymbol is the catalog entry of a symbol registered by its first quote, same as the defaults of the symbol table
+func defaultSymbol(symbol string) entity.Symbol {
+	return entity.Symbol{Symbol: symbol, Timezone: "UTC", Status: entity.SymbolListed}
+}
+
+// SymbolCatalog returns all known symbols in alphabetical order
+func (r DBRepository) SymbolCatalog(ctx context.Context) ([]entity.Symbol, error) {
+	return querySymbolCatalog(ctx, r.db)
+}
+
+// DescribeSymbol returns the symbol from the catalog, entity.ErrNotFound if it's unknown
+func (r DBRepository) DescribeSymbol(ctx context.Context, symbol string) (entity.Symbol, error) {
+	return querySymbol(ctx, r.db, getSymbol, symbol)
+}
+
+// AddSymbol stores the symbol in the catalog, entity.ErrConflict if it's known already
+func (r DBRepository) AddSymbol(ctx context.Context, symbol entity.Symbol) error {
+	return execInsertSymbol(ctx, r.db, insertSymbol, symbol)
+}
+
+// SymbolCatalog returns all known symbols in alphabetical order
+func (r PostgresRepository) SymbolCatalog(ctx context.Context) ([]entity.Symbol, error) {
+	return querySymbolCatalog(ctx, r.db)
+}
+
+// DescribeSymbol returns the symbol from the catalog, entity.ErrNotFound if it's unknown
+func (r PostgresRepository) DescribeSymbol(ctx context.Context, symbol string) (entity.Symbol, error) {
+	return querySymbol(ctx, r.db, pgGetSymbol, symbol)
+}
+
+// AddSymbol stores the symbol in the catalog, entity.ErrConflict if it's known already
+func (r PostgresRepository) AddSymbol(ctx context.Context, symbol entity.Symbol) error {
+	return execInsertSymbol(ctx, r.db, pgInsertSymbol, symbol)
+}
+
+// AddSymbol stores the symbol in the catalog, entity.ErrConflict if it's known already. SQLite has its own upsert syntax.
+func (r SQLiteRepository) AddSymbol(ctx context.Context, symbol entity.Symbol) error {
+	return execInsertSymbol(ctx, r.db, sqliteInsertSymbol, symbol)
+}
+
+func querySymbolCatalog(ctx context.Context, db *sql.DB) ([]entity.Symbol, error) {
+	rows, err := db.QueryContext(ctx, getSymbolCatalog)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	symbols := []entity.Symbol{}
+	for rows.Next() {
+		symbol, err := scanSymbol(rows)
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols, rows.Err()
+}
+
+func querySymbol(ctx context.Context, db *sql.DB, query, symbol string) (entity.Symbol, error) {
+	s, err := scanSymbol(db.QueryRowContext(ctx, query, symbol))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Symbol{}, fmt.Errorf("unknown symbol %s: %w", symbol, entity.ErrNotFound)
+	}
+	return s, err
+}
+
+func execInsertSymbol(ctx context.Context, db *sql.DB, insert string, symbol entity.Symbol) error {
+	res, err := db.ExecContext(ctx, insert, symbol.Symbol, symbol.Name, symbol.Exchange, symbol.Currency, symbol.Timezone,
+		string(symbol.Status))
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("symbol %s exists already: %w", symbol.Symbol, entity.ErrConflict)
+	}
+	return nil
+}
+
+// rowScanner is either *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSymbol(row rowScanner) (entity.Symbol, error) {
+	var s entity.Symbol
+	var status string
+	var first, last aggregatedTime
+	if err := row.Scan(&s.Symbol, &s.Name, &s.Exchange, &s.Currency, &s.Timezone, &status, &first, &last); err != nil {
+		return entity.Symbol{}, err
+	}
+	s.Status = entity.SymbolStatus(status)
+	s.FirstDatapoint, s.LastDatapoint = first.t, last.t
+
+	return s, nil
+}
+
+// aggregatedTime scans the MIN and MAX of a date point column. SQLite returns the text they're stored as rather than
+// a time, as the aggregates lose the type of the column.
+type aggregatedTime struct {
+	t *time.Time
+}
+
+func (a *aggregatedTime) Scan(value interface{}) error {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		a.t = nil
+		return nil
+	case time.Time:
+		t := v.UTC()
+		a.t = &t
+		return nil
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return fmt.Errorf("can't scan %T as date point", value)
+	}
+
+	for _, layout := range []string{sqliteTimeLayout, time.RFC3339Nano} {
+		if t, err := time.Parse(layout, text); err == nil {
+			a.t = &t
+			return nil
+		}
+	}
+	return fmt.Errorf("can't parse date point %q", text)
+}
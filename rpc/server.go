@@ -0,0 +1,255 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"stockpricews/controller"
+	"stockpricews/entity"
+	"stockpricews/handler"
+	"stockpricews/rpc/stockpricepb"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// StockPriceServer is the gRPC counterpart of handler.StockPriceHandler, it answers the queries with the same
+// controller.Controller
+type StockPriceServer struct {
+	stockpricepb.UnimplementedStockPriceServiceServer
+	Controller controller.Controller
+	// Timeout bounds the computation of a single request, zero means no deadline besides the one of the client
+	Timeout time.Duration
+}
+
+// directions maps the trade directions of the API to the entity ones, unspecified is long
+var directions = map[stockpricepb.Direction]entity.Direction{
+	stockpricepb.Direction_DIRECTION_UNSPECIFIED: entity.DirectionLong,
+	stockpricepb.Direction_DIRECTION_LONG:        entity.DirectionLong,
+	stockpricepb.Direction_DIRECTION_SHORT:       entity.DirectionShort,
+}
+
+// priceFields maps the price fields of the API to the entity ones, unspecified is close
+var priceFields = map[stockpricepb.PriceField]entity.PriceField{
+	stockpricepb.PriceField_PRICE_FIELD_UNSPECIFIED: entity.FieldClose,
+	stockpricepb.PriceField_PRICE_FIELD_OPEN:        entity.FieldOpen,
+	stockpricepb.PriceField_PRICE_FIELD_HIGH:        entity.FieldHigh,
+	stockpricepb.PriceField_PRICE_FIELD_LOW:         entity.FieldLow,
+	stockpricepb.PriceField_PRICE_FIELD_CLOSE:       entity.FieldClose,
+	stockpricepb.PriceField_PRICE_FIELD_OPTIMISTIC:  entity.FieldOptimistic,
+	stockpricepb.PriceField_PRICE_FIELD_PESSIMISTIC: entity.FieldPessimistic,
+}
+
+// endpoints names the REST endpoints whose token buckets the methods of the StockPriceService share, they only read
+var endpoints = map[string]string{
+	"MaxProfit":  handler.Endpoint(http.MethodGet, "/maxprofit"),
+	"QuoteRange": handler.Endpoint(http.MethodGet, "/quotes"),
+}
+
+// NewServer returns the gRPC server of the StockPriceService with the standard health checking and reflection
+// services, so the clients can discover the API with tools like grpcurl. The methods of the StockPriceService are rate
+// limited by the given limits, they're not limited at all if it's nil.
+func NewServer(controller controller.Controller, timeout time.Duration, limits *handler.RateLimits) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := allow(limits, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := allow(limits, info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, stream)
+		}),
+	)
+	stockpricepb.RegisterStockPriceServiceServer(server, &StockPriceServer{Controller: controller, Timeout: timeout})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(stockpricepb.StockPriceService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+	return server
+}
+
+// Serve serves the gRPC API on the port, every request is computed within the given timeout and rate limited by the
+// given limits
+func Serve(controller controller.Controller, port int, timeout time.Duration, limits *handler.RateLimits) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+	return NewServer(controller, timeout, limits).Serve(listener)
+}
+
+// allow takes a token of the bucket of the method, RESOURCE_EXHAUSTED if there's none left. The health checking and
+// reflection services aren't limited.
+func allow(limits *handler.RateLimits, fullMethod string) error {
+	prefix := "/" + stockpricepb.StockPriceService_ServiceDesc.ServiceName + "/"
+	if limits == nil || !strings.HasPrefix(fullMethod, prefix) {
+		return nil
+	}
+
+	method := strings.TrimPrefix(fullMethod, prefix)
+	endpoint, ok := endpoints[method]
+	if !ok {
+		endpoint = fullMethod
+	}
+	if !limits.Limiter(endpoint).Allow() {
+		return status.Error(codes.ResourceExhausted, "The API is at capacity, try again later.")
+	}
+	return nil
+}
+
+// MaxProfit returns the single buy/sell trade with the maximum profit within the time slice, NOT_FOUND if the symbol
+// is unknown or no profit can be realized
+func (s *StockPriceServer) MaxProfit(ctx context.Context, req *stockpricepb.MaxProfitRequest) (*stockpricepb.MaxProfitResponse, error) {
+	timeSlice, err := parseTimeSlice(req.GetSymbol(), req.GetBegin(), req.GetEnd())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	timeSlice.Adjusted = req.GetAdjusted()
+
+	var ok bool
+	if timeSlice.Field, ok = priceFields[req.GetField()]; !ok {
+		return nil, statusError(fmt.Errorf("unknown price field %d: %w", req.GetField(), entity.ErrBadRequest))
+	}
+	tradeDirection, ok := directions[req.GetDirection()]
+	if !ok {
+		return nil, statusError(fmt.Errorf("unknown direction %d: %w", req.GetDirection(), entity.ErrBadRequest))
+	}
+
+	ctx, cancel := s.requestContext(ctx)
+	defer cancel()
+
+	if _, err := s.Controller.DescribeSymbol(ctx, timeSlice.Symbol); err != nil {
+		return nil, statusError(err)
+	}
+
+	var points entity.MaxProfitPoints
+	if tradeDirection == entity.DirectionShort {
+		points, err = s.Controller.MaxShortProfitForPeriod(ctx, timeSlice)
+	} else {
+		points, err = s.Controller.MaxProfitForPeriod(ctx, timeSlice)
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	response := &stockpricepb.MaxProfitResponse{
+		BuyPoint:         &stockpricepb.TradePoint{Price: points.BuyPoint.Price, Date: timestamppb.New(points.BuyPoint.Date)},
+		SellPoint:        &stockpricepb.TradePoint{Price: points.SellPoint.Price, Date: timestamppb.New(points.SellPoint.Date)},
+		Direction:        stockpricepb.Direction_DIRECTION_LONG,
+		Profit:           points.Profit,
+		PercentReturn:    points.Return,
+		AnnualizedReturn: points.AnnualizedReturn,
+		HoldingDays:      points.HoldingDays,
+	}
+	if tradeDirection == entity.DirectionShort {
+		response.Direction = stockpricepb.Direction_DIRECTION_SHORT
+	}
+	return response, nil
+}
+
+// QuoteRange streams the stored quotes of the time slice ordered by date point, straight from the repository. As the
+// stream lasts as long as the client reads it, the request timeout doesn't apply; it fails with DEADLINE_EXCEEDED if
+// the client doesn't take a quote within the export idle timeout.
+func (s *StockPriceServer) QuoteRange(req *stockpricepb.QuoteRangeRequest, stream stockpricepb.StockPriceService_QuoteRangeServer) error {
+	timeSlice, err := parseTimeSlice(req.GetSymbol(), req.GetBegin(), req.GetEnd())
+	if err != nil {
+		return statusError(err)
+	}
+
+	if _, err := s.Controller.DescribeSymbol(stream.Context(), timeSlice.Symbol); err != nil {
+		return statusError(err)
+	}
+
+	err = s.Controller.ExportStockQuotes(stream.Context(), timeSlice, func(quote entity.StockQuote) error {
+		return stream.Send(&stockpricepb.StockQuote{
+			Symbol:    quote.Symbol,
+			Datepoint: timestamppb.New(quote.Datepoint),
+			Price:     quote.Price,
+			Open:      quote.Open,
+			High:      quote.High,
+			Low:       quote.Low,
+			Volume:    quote.Volume,
+		})
+	})
+	if err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+// requestContext returns the context of the request bounded by the server timeout, the deadline of the client applies
+// if it's earlier
+func (s *StockPriceServer) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.Timeout)
+}
+
+// parseTimeSlice validates the params every query takes the same way as the REST endpoints do
+func parseTimeSlice(symbol string, begin, end *timestamppb.Timestamp) (entity.StockQuoteRequest, error) {
+	if err := controller.ValidateSymbol(symbol); err != nil {
+		return entity.StockQuoteRequest{}, err
+	}
+
+	if begin == nil {
+		return entity.StockQuoteRequest{}, fmt.Errorf("begin is missing: %w", entity.ErrBadRequest)
+	}
+	if end == nil {
+		return entity.StockQuoteRequest{}, fmt.Errorf("end is missing: %w", entity.ErrBadRequest)
+	}
+	if err := begin.CheckValid(); err != nil {
+		return entity.StockQuoteRequest{}, fmt.Errorf("begin is not a valid timestamp: %w", entity.ErrBadRequest)
+	}
+	if err := end.CheckValid(); err != nil {
+		return entity.StockQuoteRequest{}, fmt.Errorf("end is not a valid timestamp: %w", entity.ErrBadRequest)
+	}
+
+	timeSlice := entity.StockQuoteRequest{Symbol: symbol, Begin: begin.AsTime(), End: end.AsTime()}
+	if timeSlice.Begin.After(timeSlice.End) {
+		return entity.StockQuoteRequest{}, fmt.Errorf("begin period is after the end period: %w", entity.ErrBadRequest)
+	}
+
+	return timeSlice, nil
+}
+
+// statusError maps the error to the gRPC status with the message that is safe to be reported to the client, the same
+// way handler maps the errors to HTTP status codes
+func statusError(err error) error {
+	// log the error at the server log for debug purposes
+	fmt.Println(err)
+
+	switch {
+	case errors.Is(err, entity.ErrBadRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, entity.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, entity.ErrConflict):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, entity.ErrUnauthorized):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "Request timed out")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "Request canceled")
+	default:
+		// we don't want to leak internal messages to the client
+		return status.Error(codes.Internal, "Internal server error")
+	}
+}
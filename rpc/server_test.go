@@ -0,0 +1,234 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"stockpricews/controller"
+	"stockpricews/entity"
+	"stockpricews/handler"
+	"stockpricews/repository"
+	"stockpricews/rpc/stockpricepb"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// dialServer serves the quotes on an in-memory connection and returns the client connection to it
+func dialServer(t *testing.T, quotes ...entity.StockQuote) *grpc.ClientConn {
+	return dialLimitedServer(t, nil, quotes...)
+}
+
+// dialLimitedServer serves the quotes rate limited by the given limits
+func dialLimitedServer(t *testing.T, limits *handler.RateLimits, quotes ...entity.StockQuote) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(controller.New(repository.NewMemory(quotes...)), time.Second, limits)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestMaxProfit(t *testing.T) {
+	day := time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC)
+	client := stockpricepb.NewStockPriceServiceClient(dialServer(t,
+		entity.StockQuote{Symbol: "BRK.B", Datepoint: day, Price: 50},
+		entity.StockQuote{Symbol: "BRK.B", Datepoint: day.AddDate(0, 0, 1), Price: 40},
+		entity.StockQuote{Symbol: "BRK.B", Datepoint: day.AddDate(0, 0, 2), Price: 60},
+	))
+
+	testCases := []struct {
+		name         string
+		req          *stockpricepb.MaxProfitRequest
+		expected     *stockpricepb.MaxProfitResponse
+		expectedCode codes.Code
+	}{
+		{
+			name: "Long",
+			req:  &stockpricepb.MaxProfitRequest{Symbol: "BRK.B", Begin: timestamppb.New(day.Add(-time.Hour)), End: timestamppb.New(day.AddDate(0, 0, 3))},
+			expected: &stockpricepb.MaxProfitResponse{
+				BuyPoint:      &stockpricepb.TradePoint{Price: 40, Date: timestamppb.New(day.AddDate(0, 0, 1))},
+				SellPoint:     &stockpricepb.TradePoint{Price: 60, Date: timestamppb.New(day.AddDate(0, 0, 2))},
+				Direction:     stockpricepb.Direction_DIRECTION_LONG,
+				Profit:        20,
+				PercentReturn: 50,
+				HoldingDays:   1,
+			},
+		},
+		{
+			name: "Short",
+			req: &stockpricepb.MaxProfitRequest{Symbol: "BRK.B", Begin: timestamppb.New(day.Add(-time.Hour)), End: timestamppb.New(day.AddDate(0, 0, 3)),
+				Direction: stockpricepb.Direction_DIRECTION_SHORT},
+			expected: &stockpricepb.MaxProfitResponse{
+				BuyPoint:      &stockpricepb.TradePoint{Price: 40, Date: timestamppb.New(day.AddDate(0, 0, 1))},
+				SellPoint:     &stockpricepb.TradePoint{Price: 50, Date: timestamppb.New(day)},
+				Direction:     stockpricepb.Direction_DIRECTION_SHORT,
+				Profit:        10,
+				PercentReturn: 20,
+				HoldingDays:   1,
+			},
+		},
+		{
+			name:         "Missing begin",
+			req:          &stockpricepb.MaxProfitRequest{Symbol: "BRK.B", End: timestamppb.New(day)},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Begin after end",
+			req:          &stockpricepb.MaxProfitRequest{Symbol: "BRK.B", Begin: timestamppb.New(day), End: timestamppb.New(day.Add(-time.Hour))},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Invalid symbol",
+			req:          &stockpricepb.MaxProfitRequest{Symbol: "BRK B", Begin: timestamppb.New(day), End: timestamppb.New(day)},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Unknown price field",
+			req: &stockpricepb.MaxProfitRequest{Symbol: "BRK.B", Begin: timestamppb.New(day), End: timestamppb.New(day.AddDate(0, 0, 3)),
+				Field: stockpricepb.PriceField(42)},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Unknown symbol",
+			req:          &stockpricepb.MaxProfitRequest{Symbol: "UNKN", Begin: timestamppb.New(day), End: timestamppb.New(day.AddDate(0, 0, 3))},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "No quotes in the time slice",
+			req:          &stockpricepb.MaxProfitRequest{Symbol: "BRK.B", Begin: timestamppb.New(day.AddDate(1, 0, 0)), End: timestamppb.New(day.AddDate(1, 0, 1))},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.MaxProfit(context.Background(), tt.req)
+			if tt.expectedCode != codes.OK {
+				assert.Equal(t, tt.expectedCode, status.Code(err), err)
+				return
+			}
+			assert.NoError(t, err)
+			// the annualized return is computed by the controller, it's only checked to be passed on
+			assert.NotNil(t, got.AnnualizedReturn)
+			got.AnnualizedReturn = nil
+			assert.True(t, proto.Equal(tt.expected, got), got.String())
+		})
+	}
+}
+
+func TestQuoteRange(t *testing.T) {
+	day := time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC)
+	client := stockpricepb.NewStockPriceServiceClient(dialServer(t,
+		entity.StockQuote{Symbol: "VOD.L", Datepoint: day.AddDate(0, 0, 1), Price: 72.5, Volume: 1000},
+		entity.StockQuote{Symbol: "VOD.L", Datepoint: day, Price: 71.9, Open: 71, High: 72, Low: 70.8, Volume: 900},
+		entity.StockQuote{Symbol: "VOD.L", Datepoint: day.AddDate(0, 0, 5), Price: 73},
+	))
+
+	stream, err := client.QuoteRange(context.Background(), &stockpricepb.QuoteRangeRequest{
+		Symbol: "VOD.L", Begin: timestamppb.New(day.Add(-time.Hour)), End: timestamppb.New(day.AddDate(0, 0, 2))})
+	assert.NoError(t, err)
+	var quotes []string
+	for {
+		quote, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NoError(t, err)
+		quotes = append(quotes, fmt.Sprintf("%s %s %v", quote.GetSymbol(), quote.GetDatepoint().AsTime().Format(time.RFC3339), quote.GetPrice()))
+	}
+	assert.Equal(t, []string{"VOD.L 2023-11-06T00:00:00Z 71.9", "VOD.L 2023-11-07T00:00:00Z 72.5"}, quotes)
+
+	// the errors before the first quote are reported as the status of the stream
+	stream, err = client.QuoteRange(context.Background(), &stockpricepb.QuoteRangeRequest{
+		Symbol: "UNKN", Begin: timestamppb.New(day), End: timestamppb.New(day.AddDate(0, 0, 2))})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestHealthCheck(t *testing.T) {
+	client := healthpb.NewHealthClient(dialServer(t))
+	for _, service := range []string{"", "stockprice.v1.StockPriceService"} {
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		assert.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	day := time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC)
+	limits := handler.NewRateLimits()
+	conn := dialLimitedServer(t, limits, entity.StockQuote{Symbol: "UBER", Datepoint: day, Price: 10})
+	client := stockpricepb.NewStockPriceServiceClient(conn)
+
+	// the REST clients posting quotes don't take the tokens of the export
+	for limits.Limiter("POST /quotes").Allow() {
+	}
+	stream, err := client.QuoteRange(context.Background(), &stockpricepb.QuoteRangeRequest{
+		Symbol: "UBER", Begin: timestamppb.New(day.Add(-time.Hour)), End: timestamppb.New(day.AddDate(0, 0, 1))})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.NoError(t, err)
+
+	// the REST clients used up the bucket of GET /quotes
+	for limits.Limiter("GET /quotes").Allow() {
+	}
+	stream, err = client.QuoteRange(context.Background(), &stockpricepb.QuoteRangeRequest{
+		Symbol: "UBER", Begin: timestamppb.New(day.Add(-time.Hour)), End: timestamppb.New(day.AddDate(0, 0, 1))})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// the other endpoints have buckets of their own
+	_, err = client.MaxProfit(context.Background(), &stockpricepb.MaxProfitRequest{
+		Symbol: "UBER", Begin: timestamppb.New(day.Add(-time.Hour)), End: timestamppb.New(day.AddDate(0, 0, 1))})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	for limits.Limiter("GET /maxprofit").Allow() {
+	}
+	_, err = client.MaxProfit(context.Background(), &stockpricepb.MaxProfitRequest{
+		Symbol: "UBER", Begin: timestamppb.New(day.Add(-time.Hour)), End: timestamppb.New(day.AddDate(0, 0, 1))})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// health checks aren't limited
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+}
+
+func TestStatusError(t *testing.T) {
+	testCases := []struct {
+		err             error
+		expectedCode    codes.Code
+		expectedMessage string
+	}{
+		{fmt.Errorf("begin is missing: %w", entity.ErrBadRequest), codes.InvalidArgument, "begin is missing: bad request"},
+		{fmt.Errorf("failed to describe symbol: %w", fmt.Errorf("unknown symbol UNKN: %w", entity.ErrNotFound)), codes.NotFound, "failed to describe symbol: unknown symbol UNKN: not found"},
+		{fmt.Errorf("symbol UBER exists already: %w", entity.ErrConflict), codes.AlreadyExists, "symbol UBER exists already: conflict"},
+		{fmt.Errorf("failed to load stock quotes: %w", context.DeadlineExceeded), codes.DeadlineExceeded, "Request timed out"},
+		{errors.New("connection refused"), codes.Internal, "Internal server error"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.expectedCode.String(), func(t *testing.T) {
+			s := status.Convert(statusError(tt.err))
+			assert.Equal(t, tt.expectedCode, s.Code())
+			assert.Equal(t, tt.expectedMessage, s.Message())
+		})
+	}
+}
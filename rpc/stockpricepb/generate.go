@@ -0,0 +1,6 @@
+// Package stockpricepb holds the code generated from stockprice.proto, regenerate it with go generate after changing
+// the definition. The plugins are protoc-gen-go v1.31.0 and protoc-gen-go-grpc v1.3.0, the versions of the generated
+// code.
+package stockpricepb
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative rpc/stockpricepb/stockprice.proto
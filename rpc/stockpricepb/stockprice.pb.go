@@ -0,0 +1,787 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: rpc/stockpricepb/stockprice.proto
+
+// The gRPC API of the service, it answers the same queries as the REST API and reports the same errors with the
+// matching status codes: INVALID_ARGUMENT for bad requests, NOT_FOUND for unknown symbols or time slices without
+// quotes and DEADLINE_EXCEEDED if the request timeout expires.
+
+package stockpricepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Direction of a trade, long buys first and sells later, short sells first and buys back later.
+type Direction int32
+
+const (
+	Direction_DIRECTION_UNSPECIFIED Direction = 0
+	Direction_DIRECTION_LONG        Direction = 1
+	Direction_DIRECTION_SHORT       Direction = 2
+)
+
+// Enum value maps for Direction.
+var (
+	Direction_name = map[int32]string{
+		0: "DIRECTION_UNSPECIFIED",
+		1: "DIRECTION_LONG",
+		2: "DIRECTION_SHORT",
+	}
+	Direction_value = map[string]int32{
+		"DIRECTION_UNSPECIFIED": 0,
+		"DIRECTION_LONG":        1,
+		"DIRECTION_SHORT":       2,
+	}
+)
+
+func (x Direction) Enum() *Direction {
+	p := new(Direction)
+	*p = x
+	return p
+}
+
+func (x Direction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Direction) Descriptor() protoreflect.EnumDescriptor {
+	return file_rpc_stockpricepb_stockprice_proto_enumTypes[0].Descriptor()
+}
+
+func (Direction) Type() protoreflect.EnumType {
+	return &file_rpc_stockpricepb_stockprice_proto_enumTypes[0]
+}
+
+func (x Direction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Direction.Descriptor instead.
+func (Direction) EnumDescriptor() ([]byte, []int) {
+	return file_rpc_stockpricepb_stockprice_proto_rawDescGZIP(), []int{0}
+}
+
+// PriceField is the price of the quotes the trades are made at, close if unspecified.
+type PriceField int32
+
+const (
+	PriceField_PRICE_FIELD_UNSPECIFIED PriceField = 0
+	PriceField_PRICE_FIELD_OPEN        PriceField = 1
+	PriceField_PRICE_FIELD_HIGH        PriceField = 2
+	PriceField_PRICE_FIELD_LOW         PriceField = 3
+	PriceField_PRICE_FIELD_CLOSE       PriceField = 4
+	// buys at the low and sells at the high of the period, the upper bound of the profit
+	PriceField_PRICE_FIELD_OPTIMISTIC PriceField = 5
+	// buys at the high and sells at the low of the period, the lower bound of the profit
+	PriceField_PRICE_FIELD_PESSIMISTIC PriceField = 6
+)
+
+// Enum value maps for PriceField.
+var (
+	PriceField_name = map[int32]string{
+		0: "PRICE_FIELD_UNSPECIFIED",
+		1: "PRICE_FIELD_OPEN",
+		2: "PRICE_FIELD_HIGH",
+		3: "PRICE_FIELD_LOW",
+		4: "PRICE_FIELD_CLOSE",
+		5: "PRICE_FIELD_OPTIMISTIC",
+		6: "PRICE_FIELD_PESSIMISTIC",
+	}
+	PriceField_value = map[string]int32{
+		"PRICE_FIELD_UNSPECIFIED": 0,
+		"PRICE_FIELD_OPEN":        1,
+		"PRICE_FIELD_HIGH":        2,
+		"PRICE_FIELD_LOW":         3,
+		"PRICE_FIELD_CLOSE":       4,
+		"PRICE_FIELD_OPTIMISTIC":  5,
+		"PRICE_FIELD_PESSIMISTIC": 6,
+	}
+)
+
+func (x PriceField) Enum() *PriceField {
+	p := new(PriceField)
+	*p = x
+	return p
+}
+
+func (x PriceField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PriceField) Descriptor() protoreflect.EnumDescriptor {
+	return file_rpc_stockpricepb_stockprice_proto_enumTypes[1].Descriptor()
+}
+
+func (PriceField) Type() protoreflect.EnumType {
+	return &file_rpc_stockpricepb_stockprice_proto_enumTypes[1]
+}
+
+func (x PriceField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PriceField.Descriptor instead.
+func (PriceField) EnumDescriptor() ([]byte, []int) {
+	return file_rpc_stockpricepb_stockprice_proto_rawDescGZIP(), []int{1}
+}
+
+type MaxProfitRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Symbol string `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// quotes after begin and before end count, both are required
+	Begin *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=begin,proto3" json:"begin,omitempty"`
+	End   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	// long if unspecified
+	Direction Direction  `protobuf:"varint,4,opt,name=direction,proto3,enum=stockprice.v1.Direction" json:"direction,omitempty"`
+	Field     PriceField `protobuf:"varint,5,opt,name=field,proto3,enum=stockprice.v1.PriceField" json:"field,omitempty"`
+	// computes on prices back-adjusted for splits and dividends
+	Adjusted bool `protobuf:"varint,6,opt,name=adjusted,proto3" json:"adjusted,omitempty"`
+}
+
+func (x *MaxProfitRequest) Reset() {
+	*x = MaxProfitRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_stockpricepb_stockprice_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MaxProfitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaxProfitRequest) ProtoMessage() {}
+
+func (x *MaxProfitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_stockpricepb_stockprice_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaxProfitRequest.ProtoReflect.Descriptor instead.
+func (*MaxProfitRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_stockpricepb_stockprice_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MaxProfitRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *MaxProfitRequest) GetBegin() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Begin
+	}
+	return nil
+}
+
+func (x *MaxProfitRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *MaxProfitRequest) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_DIRECTION_UNSPECIFIED
+}
+
+func (x *MaxProfitRequest) GetField() PriceField {
+	if x != nil {
+		return x.Field
+	}
+	return PriceField_PRICE_FIELD_UNSPECIFIED
+}
+
+func (x *MaxProfitRequest) GetAdjusted() bool {
+	if x != nil {
+		return x.Adjusted
+	}
+	return false
+}
+
+type TradePoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Price float64                `protobuf:"fixed64,1,opt,name=price,proto3" json:"price,omitempty"`
+	Date  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+}
+
+func (x *TradePoint) Reset() {
+	*x = TradePoint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_stockpricepb_stockprice_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TradePoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradePoint) ProtoMessage() {}
+
+func (x *TradePoint) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_stockpricepb_stockprice_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradePoint.ProtoReflect.Descriptor instead.
+func (*TradePoint) Descriptor() ([]byte, []int) {
+	return file_rpc_stockpricepb_stockprice_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TradePoint) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *TradePoint) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+type MaxProfitResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuyPoint  *TradePoint `protobuf:"bytes,1,opt,name=buy_point,json=buyPoint,proto3" json:"buy_point,omitempty"`
+	SellPoint *TradePoint `protobuf:"bytes,2,opt,name=sell_point,json=sellPoint,proto3" json:"sell_point,omitempty"`
+	Direction Direction   `protobuf:"varint,3,opt,name=direction,proto3,enum=stockprice.v1.Direction" json:"direction,omitempty"`
+	// profit per share
+	Profit float64 `protobuf:"fixed64,4,opt,name=profit,proto3" json:"profit,omitempty"`
+	// percentage return relative to the price the position is opened at
+	PercentReturn float64 `protobuf:"fixed64,5,opt,name=percent_return,json=percentReturn,proto3" json:"percent_return,omitempty"`
+	// compound annual growth rate in percents, missing for positions held less than a day
+	AnnualizedReturn *float64 `protobuf:"fixed64,6,opt,name=annualized_return,json=annualizedReturn,proto3,oneof" json:"annualized_return,omitempty"`
+	HoldingDays      float64  `protobuf:"fixed64,7,opt,name=holding_days,json=holdingDays,proto3" json:"holding_days,omitempty"`
+}
+
+func (x *MaxProfitResponse) Reset() {
+	*x = MaxProfitResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_stockpricepb_stockprice_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MaxProfitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaxProfitResponse) ProtoMessage() {}
+
+func (x *MaxProfitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_stockpricepb_stockprice_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaxProfitResponse.ProtoReflect.Descriptor instead.
+func (*MaxProfitResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_stockpricepb_stockprice_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MaxProfitResponse) GetBuyPoint() *TradePoint {
+	if x != nil {
+		return x.BuyPoint
+	}
+	return nil
+}
+
+func (x *MaxProfitResponse) GetSellPoint() *TradePoint {
+	if x != nil {
+		return x.SellPoint
+	}
+	return nil
+}
+
+func (x *MaxProfitResponse) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_DIRECTION_UNSPECIFIED
+}
+
+func (x *MaxProfitResponse) GetProfit() float64 {
+	if x != nil {
+		return x.Profit
+	}
+	return 0
+}
+
+func (x *MaxProfitResponse) GetPercentReturn() float64 {
+	if x != nil {
+		return x.PercentReturn
+	}
+	return 0
+}
+
+func (x *MaxProfitResponse) GetAnnualizedReturn() float64 {
+	if x != nil && x.AnnualizedReturn != nil {
+		return *x.AnnualizedReturn
+	}
+	return 0
+}
+
+func (x *MaxProfitResponse) GetHoldingDays() float64 {
+	if x != nil {
+		return x.HoldingDays
+	}
+	return 0
+}
+
+type QuoteRangeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Symbol string `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// quotes after begin and before end are streamed, both are required
+	Begin *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=begin,proto3" json:"begin,omitempty"`
+	End   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+}
+
+func (x *QuoteRangeRequest) Reset() {
+	*x = QuoteRangeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_stockpricepb_stockprice_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuoteRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuoteRangeRequest) ProtoMessage() {}
+
+func (x *QuoteRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_stockpricepb_stockprice_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuoteRangeRequest.ProtoReflect.Descriptor instead.
+func (*QuoteRangeRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_stockpricepb_stockprice_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *QuoteRangeRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *QuoteRangeRequest) GetBegin() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Begin
+	}
+	return nil
+}
+
+func (x *QuoteRangeRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type StockQuote struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Symbol    string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Datepoint *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=datepoint,proto3" json:"datepoint,omitempty"`
+	// close price of the period
+	Price  float64 `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	Open   float64 `protobuf:"fixed64,4,opt,name=open,proto3" json:"open,omitempty"`
+	High   float64 `protobuf:"fixed64,5,opt,name=high,proto3" json:"high,omitempty"`
+	Low    float64 `protobuf:"fixed64,6,opt,name=low,proto3" json:"low,omitempty"`
+	Volume int64   `protobuf:"varint,7,opt,name=volume,proto3" json:"volume,omitempty"`
+}
+
+func (x *StockQuote) Reset() {
+	*x = StockQuote{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_stockpricepb_stockprice_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StockQuote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockQuote) ProtoMessage() {}
+
+func (x *StockQuote) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_stockpricepb_stockprice_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockQuote.ProtoReflect.Descriptor instead.
+func (*StockQuote) Descriptor() ([]byte, []int) {
+	return file_rpc_stockpricepb_stockprice_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StockQuote) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *StockQuote) GetDatepoint() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Datepoint
+	}
+	return nil
+}
+
+func (x *StockQuote) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *StockQuote) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *StockQuote) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *StockQuote) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *StockQuote) GetVolume() int64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+var File_rpc_stockpricepb_stockprice_proto protoreflect.FileDescriptor
+
+var file_rpc_stockpricepb_stockprice_proto_rawDesc = []byte{
+	0x0a, 0x21, 0x72, 0x70, 0x63, 0x2f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x70, 0x72, 0x69, 0x63, 0x65,
+	0x70, 0x62, 0x2f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x70, 0x72, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x70, 0x72, 0x69, 0x63, 0x65, 0x2e,
+	0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0x8f, 0x02, 0x0a, 0x10, 0x4d, 0x61, 0x78, 0x50, 0x72, 0x6f, 0x66, 0x69,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62,
+	0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c,
+	0x12, 0x30, 0x0a, 0x05, 0x62, 0x65, 0x67, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x62, 0x65, 0x67,
+	0x69, 0x6e, 0x12, 0x2c, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x03, 0x65, 0x6e, 0x64,
+	0x12, 0x36, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x70, 0x72, 0x69, 0x63, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x64,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2f, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x70,
+	0x72, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x69, 0x63, 0x65, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x64, 0x6a,
+	0x75, 0x73, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61, 0x64, 0x6a,
+	0x75, 0x73, 0x74, 0x65, 0x64, 0x22, 0x52, 0x0a, 0x0a, 0x54, 0x72, 0x61, 0x64, 0x65, 0x50, 0x6f,
+	0x69, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x22, 0xe7, 0x02, 0x0a, 0x11, 0x4d, 0x61,
+	0x78, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x36, 0x0a, 0x09, 0x62, 0x75, 0x79, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x70, 0x72, 0x69, 0x63, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x08, 0x62,
+	0x75, 0x79, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x38, 0x0a, 0x0a, 0x73, 0x65, 0x6c, 0x6c, 0x5f,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x74,
+	0x6f, 0x63, 0x6b, 0x70, 0x72, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x64,
+	0x65, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x09, 0x73, 0x65, 0x6c, 0x6c, 0x50, 0x6f, 0x69, 0x6e,
+	0x74, 0x12, 0x36, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x70, 0x72, 0x69, 0x63,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09,
+	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x6f,
+	0x66, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x70, 0x72, 0x6f, 0x66, 0x69,
+	0x74, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x74,
+	0x75, 0x72, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x70, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x12, 0x30, 0x0a, 0x11, 0x61, 0x6e, 0x6e, 0x75,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x10, 0x61, 0x6e, 0x6e, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65,
+	0x64, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x0c, 0x68, 0x6f,
+	0x6c, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0b, 0x68, 0x6f, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x44, 0x61, 0x79, 0x73, 0x42, 0x14, 0x0a,
+	0x12, 0x5f, 0x61, 0x6e, 0x6e, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x74,
+	0x75, 0x72, 0x6e, 0x22, 0x8b, 0x01, 0x0a, 0x11, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d,
+	0x62, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f,
+	0x6c, 0x12, 0x30, 0x0a, 0x05, 0x62, 0x65, 0x67, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x62, 0x65,
+	0x67, 0x69, 0x6e, 0x12, 0x2c, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x03, 0x65, 0x6e,
+	0x64, 0x22, 0xc6, 0x01, 0x0a, 0x0a, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x51, 0x75, 0x6f, 0x74, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12, 0x38, 0x0a, 0x09, 0x64, 0x61, 0x74, 0x65,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x64, 0x61, 0x74, 0x65, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6f, 0x70, 0x65, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x6f, 0x70, 0x65, 0x6e, 0x12, 0x12, 0x0a, 0x04,
+	0x68, 0x69, 0x67, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x68, 0x69, 0x67, 0x68,
+	0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x77, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6c,
+	0x6f, 0x77, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x06, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x2a, 0x4f, 0x0a, 0x09, 0x44, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x15, 0x44, 0x49, 0x52, 0x45, 0x43,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
+	0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x44, 0x49, 0x52, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x4c, 0x4f, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x44, 0x49, 0x52, 0x45, 0x43, 0x54,
+	0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x48, 0x4f, 0x52, 0x54, 0x10, 0x02, 0x2a, 0xba, 0x01, 0x0a, 0x0a,
+	0x50, 0x72, 0x69, 0x63, 0x65, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1b, 0x0a, 0x17, 0x50, 0x52,
+	0x49, 0x43, 0x45, 0x5f, 0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x52, 0x49, 0x43, 0x45,
+	0x5f, 0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x4f, 0x50, 0x45, 0x4e, 0x10, 0x01, 0x12, 0x14, 0x0a,
+	0x10, 0x50, 0x52, 0x49, 0x43, 0x45, 0x5f, 0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x48, 0x49, 0x47,
+	0x48, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x52, 0x49, 0x43, 0x45, 0x5f, 0x46, 0x49, 0x45,
+	0x4c, 0x44, 0x5f, 0x4c, 0x4f, 0x57, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x52, 0x49, 0x43,
+	0x45, 0x5f, 0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x10, 0x04, 0x12,
+	0x1a, 0x0a, 0x16, 0x50, 0x52, 0x49, 0x43, 0x45, 0x5f, 0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x4f,
+	0x50, 0x54, 0x49, 0x4d, 0x49, 0x53, 0x54, 0x49, 0x43, 0x10, 0x05, 0x12, 0x1b, 0x0a, 0x17, 0x50,
+	0x52, 0x49, 0x43, 0x45, 0x5f, 0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x50, 0x45, 0x53, 0x53, 0x49,
+	0x4d, 0x49, 0x53, 0x54, 0x49, 0x43, 0x10, 0x06, 0x32, 0xb0, 0x01, 0x0a, 0x11, 0x53, 0x74, 0x6f,
+	0x63, 0x6b, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4e,
+	0x0a, 0x09, 0x4d, 0x61, 0x78, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x74, 0x12, 0x1f, 0x2e, 0x73, 0x74,
+	0x6f, 0x63, 0x6b, 0x70, 0x72, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x61, 0x78, 0x50,
+	0x72, 0x6f, 0x66, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x73,
+	0x74, 0x6f, 0x63, 0x6b, 0x70, 0x72, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x61, 0x78,
+	0x50, 0x72, 0x6f, 0x66, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4b,
+	0x0a, 0x0a, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x20, 0x2e, 0x73,
+	0x74, 0x6f, 0x63, 0x6b, 0x70, 0x72, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x6f,
+	0x74, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
+	0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x70, 0x72, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x74, 0x6f, 0x63, 0x6b, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x30, 0x01, 0x42, 0x1f, 0x5a, 0x1d, 0x73,
+	0x74, 0x6f, 0x63, 0x6b, 0x70, 0x72, 0x69, 0x63, 0x65, 0x77, 0x73, 0x2f, 0x72, 0x70, 0x63, 0x2f,
+	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x70, 0x72, 0x69, 0x63, 0x65, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_stockpricepb_stockprice_proto_rawDescOnce sync.Once
+	file_rpc_stockpricepb_stockprice_proto_rawDescData = file_rpc_stockpricepb_stockprice_proto_rawDesc
+)
+
+func file_rpc_stockpricepb_stockprice_proto_rawDescGZIP() []byte {
+	file_rpc_stockpricepb_stockprice_proto_rawDescOnce.Do(func() {
+		file_rpc_stockpricepb_stockprice_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_stockpricepb_stockprice_proto_rawDescData)
+	})
+	return file_rpc_stockpricepb_stockprice_proto_rawDescData
+}
+
+var file_rpc_stockpricepb_stockprice_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_rpc_stockpricepb_stockprice_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_rpc_stockpricepb_stockprice_proto_goTypes = []interface{}{
+	(Direction)(0),                // 0: stockprice.v1.Direction
+	(PriceField)(0),               // 1: stockprice.v1.PriceField
+	(*MaxProfitRequest)(nil),      // 2: stockprice.v1.MaxProfitRequest
+	(*TradePoint)(nil),            // 3: stockprice.v1.TradePoint
+	(*MaxProfitResponse)(nil),     // 4: stockprice.v1.MaxProfitResponse
+	(*QuoteRangeRequest)(nil),     // 5: stockprice.v1.QuoteRangeRequest
+	(*StockQuote)(nil),            // 6: stockprice.v1.StockQuote
+	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+}
+var file_rpc_stockpricepb_stockprice_proto_depIdxs = []int32{
+	7,  // 0: stockprice.v1.MaxProfitRequest.begin:type_name -> google.protobuf.Timestamp
+	7,  // 1: stockprice.v1.MaxProfitRequest.end:type_name -> google.protobuf.Timestamp
+	0,  // 2: stockprice.v1.MaxProfitRequest.direction:type_name -> stockprice.v1.Direction
+	1,  // 3: stockprice.v1.MaxProfitRequest.field:type_name -> stockprice.v1.PriceField
+	7,  // 4: stockprice.v1.TradePoint.date:type_name -> google.protobuf.Timestamp
+	3,  // 5: stockprice.v1.MaxProfitResponse.buy_point:type_name -> stockprice.v1.TradePoint
+	3,  // 6: stockprice.v1.MaxProfitResponse.sell_point:type_name -> stockprice.v1.TradePoint
+	0,  // 7: stockprice.v1.MaxProfitResponse.direction:type_name -> stockprice.v1.Direction
+	7,  // 8: stockprice.v1.QuoteRangeRequest.begin:type_name -> google.protobuf.Timestamp
+	7,  // 9: stockprice.v1.QuoteRangeRequest.end:type_name -> google.protobuf.Timestamp
+	7,  // 10: stockprice.v1.StockQuote.datepoint:type_name -> google.protobuf.Timestamp
+	2,  // 11: stockprice.v1.StockPriceService.MaxProfit:input_type -> stockprice.v1.MaxProfitRequest
+	5,  // 12: stockprice.v1.StockPriceService.QuoteRange:input_type -> stockprice.v1.QuoteRangeRequest
+	4,  // 13: stockprice.v1.StockPriceService.MaxProfit:output_type -> stockprice.v1.MaxProfitResponse
+	6,  // 14: stockprice.v1.StockPriceService.QuoteRange:output_type -> stockprice.v1.StockQuote
+	13, // [13:15] is the sub-list for method output_type
+	11, // [11:13] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_rpc_stockpricepb_stockprice_proto_init() }
+func file_rpc_stockpricepb_stockprice_proto_init() {
+	if File_rpc_stockpricepb_stockprice_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_stockpricepb_stockprice_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MaxProfitRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_stockpricepb_stockprice_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TradePoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_stockpricepb_stockprice_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MaxProfitResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_stockpricepb_stockprice_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QuoteRangeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_stockpricepb_stockprice_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StockQuote); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_rpc_stockpricepb_stockprice_proto_msgTypes[2].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_stockpricepb_stockprice_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rpc_stockpricepb_stockprice_proto_goTypes,
+		DependencyIndexes: file_rpc_stockpricepb_stockprice_proto_depIdxs,
+		EnumInfos:         file_rpc_stockpricepb_stockprice_proto_enumTypes,
+		MessageInfos:      file_rpc_stockpricepb_stockprice_proto_msgTypes,
+	}.Build()
+	File_rpc_stockpricepb_stockprice_proto = out.File
+	file_rpc_stockpricepb_stockprice_proto_rawDesc = nil
+	file_rpc_stockpricepb_stockprice_proto_goTypes = nil
+	file_rpc_stockpricepb_stockprice_proto_depIdxs = nil
+}
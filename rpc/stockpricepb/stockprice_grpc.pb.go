@@ -0,0 +1,186 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: rpc/stockpricepb/stockprice.proto
+
+// The gRPC API of the service, it answers the same queries as the REST API and reports the same errors with the
+// matching status codes: INVALID_ARGUMENT for bad requests, NOT_FOUND for unknown symbols or time slices without
+// quotes and DEADLINE_EXCEEDED if the request timeout expires.
+
+package stockpricepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	StockPriceService_MaxProfit_FullMethodName  = "/stockprice.v1.StockPriceService/MaxProfit"
+	StockPriceService_QuoteRange_FullMethodName = "/stockprice.v1.StockPriceService/QuoteRange"
+)
+
+// StockPriceServiceClient is the client API for StockPriceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StockPriceServiceClient interface {
+	// MaxProfit returns the single buy/sell trade with the maximum profit within the time slice.
+	MaxProfit(ctx context.Context, in *MaxProfitRequest, opts ...grpc.CallOption) (*MaxProfitResponse, error)
+	// QuoteRange streams the stored quotes of the time slice ordered by date point. Like the REST export it isn't bound
+	// by the request timeout, it stops when the client goes away or fails with DEADLINE_EXCEEDED if the client doesn't
+	// take a quote within the export idle timeout.
+	QuoteRange(ctx context.Context, in *QuoteRangeRequest, opts ...grpc.CallOption) (StockPriceService_QuoteRangeClient, error)
+}
+
+type stockPriceServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStockPriceServiceClient(cc grpc.ClientConnInterface) StockPriceServiceClient {
+	return &stockPriceServiceClient{cc}
+}
+
+func (c *stockPriceServiceClient) MaxProfit(ctx context.Context, in *MaxProfitRequest, opts ...grpc.CallOption) (*MaxProfitResponse, error) {
+	out := new(MaxProfitResponse)
+	err := c.cc.Invoke(ctx, StockPriceService_MaxProfit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stockPriceServiceClient) QuoteRange(ctx context.Context, in *QuoteRangeRequest, opts ...grpc.CallOption) (StockPriceService_QuoteRangeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StockPriceService_ServiceDesc.Streams[0], StockPriceService_QuoteRange_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &stockPriceServiceQuoteRangeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StockPriceService_QuoteRangeClient interface {
+	Recv() (*StockQuote, error)
+	grpc.ClientStream
+}
+
+type stockPriceServiceQuoteRangeClient struct {
+	grpc.ClientStream
+}
+
+func (x *stockPriceServiceQuoteRangeClient) Recv() (*StockQuote, error) {
+	m := new(StockQuote)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StockPriceServiceServer is the server API for StockPriceService service.
+// All implementations must embed UnimplementedStockPriceServiceServer
+// for forward compatibility
+type StockPriceServiceServer interface {
+	// MaxProfit returns the single buy/sell trade with the maximum profit within the time slice.
+	MaxProfit(context.Context, *MaxProfitRequest) (*MaxProfitResponse, error)
+	// QuoteRange streams the stored quotes of the time slice ordered by date point. Like the REST export it isn't bound
+	// by the request timeout, it stops when the client goes away or fails with DEADLINE_EXCEEDED if the client doesn't
+	// take a quote within the export idle timeout.
+	QuoteRange(*QuoteRangeRequest, StockPriceService_QuoteRangeServer) error
+	mustEmbedUnimplementedStockPriceServiceServer()
+}
+
+// UnimplementedStockPriceServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedStockPriceServiceServer struct {
+}
+
+func (UnimplementedStockPriceServiceServer) MaxProfit(context.Context, *MaxProfitRequest) (*MaxProfitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MaxProfit not implemented")
+}
+func (UnimplementedStockPriceServiceServer) QuoteRange(*QuoteRangeRequest, StockPriceService_QuoteRangeServer) error {
+	return status.Errorf(codes.Unimplemented, "method QuoteRange not implemented")
+}
+func (UnimplementedStockPriceServiceServer) mustEmbedUnimplementedStockPriceServiceServer() {}
+
+// UnsafeStockPriceServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StockPriceServiceServer will
+// result in compilation errors.
+type UnsafeStockPriceServiceServer interface {
+	mustEmbedUnimplementedStockPriceServiceServer()
+}
+
+func RegisterStockPriceServiceServer(s grpc.ServiceRegistrar, srv StockPriceServiceServer) {
+	s.RegisterService(&StockPriceService_ServiceDesc, srv)
+}
+
+func _StockPriceService_MaxProfit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MaxProfitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockPriceServiceServer).MaxProfit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StockPriceService_MaxProfit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockPriceServiceServer).MaxProfit(ctx, req.(*MaxProfitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StockPriceService_QuoteRange_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QuoteRangeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StockPriceServiceServer).QuoteRange(m, &stockPriceServiceQuoteRangeServer{stream})
+}
+
+type StockPriceService_QuoteRangeServer interface {
+	Send(*StockQuote) error
+	grpc.ServerStream
+}
+
+type stockPriceServiceQuoteRangeServer struct {
+	grpc.ServerStream
+}
+
+func (x *stockPriceServiceQuoteRangeServer) Send(m *StockQuote) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// StockPriceService_ServiceDesc is the grpc.ServiceDesc for StockPriceService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StockPriceService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "stockprice.v1.StockPriceService",
+	HandlerType: (*StockPriceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "MaxProfit",
+			Handler:    _StockPriceService_MaxProfit_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "QuoteRange",
+			Handler:       _StockPriceService_QuoteRange_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rpc/stockpricepb/stockprice.proto",
+}